@@ -0,0 +1,55 @@
+package blueprint
+
+// NeuronAblation measures how much each hidden neuron contributes to the model. For every neuron that
+// is neither an input nor an output, all connections reading from it are temporarily set to zero so
+// its output no longer reaches the rest of the network, and the drop in exact accuracy (in percentage
+// points) relative to the intact model is recorded. Negative values mean the model did better without
+// the neuron. The original weights are restored exactly after each ablation.
+func (bp *Blueprint) NeuronAblation(sessions []Session) map[int]float64 {
+	importance := make(map[int]float64)
+	if len(sessions) == 0 {
+		return importance
+	}
+
+	outputs := make(map[int]bool, len(bp.OutputNodes))
+	for _, id := range bp.OutputNodes {
+		outputs[id] = true
+	}
+
+	baseline, _, _, _, _, _ := bp.EvaluateModelPerformance(sessions)
+
+	type savedWeight struct {
+		neuron *Neuron
+		index  int
+		weight float64
+	}
+
+	for _, id := range bp.getAllNeuronIDs() {
+		if bp.Neurons[id].Type == "input" || outputs[id] {
+			continue
+		}
+
+		// Silence every connection that reads from the neuron
+		var saved []savedWeight
+		for _, neuron := range bp.Neurons {
+			for i := 0; i < neuron.numConnections(); i++ {
+				sourceID, weight := neuron.connection(i)
+				if sourceID == id {
+					saved = append(saved, savedWeight{neuron: neuron, index: i, weight: weight})
+					neuron.setConnectionWeight(i, 0)
+				}
+			}
+		}
+		bp.invalidateCompiled()
+
+		ablated, _, _, _, _, _ := bp.EvaluateModelPerformance(sessions)
+		importance[id] = baseline - ablated
+
+		// Widening float32 weights is exact, so writing them back restores the original bits
+		for _, s := range saved {
+			s.neuron.setConnectionWeight(s.index, s.weight)
+		}
+		bp.invalidateCompiled()
+	}
+	return importance
+}
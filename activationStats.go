@@ -0,0 +1,57 @@
+package blueprint
+
+import "math"
+
+// ActivationStats summarizes the values a neuron took over a set of sessions.
+type ActivationStats struct {
+	Min          float64
+	Max          float64
+	Mean         float64
+	StdDev       float64 // Population standard deviation
+	FractionZero float64 // Fraction of sessions in which the value was exactly 0, 1 for a dead ReLU neuron
+	Samples      int     // Number of values summarized, one per session
+}
+
+// CollectActivationStats runs every session through Forward outside training mode and summarizes the value each
+// neuron holds after the session's last timestep, keyed by neuron ID. A neuron whose FractionZero is 1 never
+// fired, and a sigmoid or tanh neuron whose Min and Max are both close to a bound is saturated. Output values are
+// read after softmax, as GetOutputs returns them. Without sessions the result is empty.
+func (bp *Blueprint) CollectActivationStats(sessions []Session) map[int]ActivationStats {
+	defer bp.evalMode()()
+
+	stats := make(map[int]ActivationStats)
+	if len(sessions) == 0 {
+		return stats
+	}
+	sums := make(map[int]float64)
+	squares := make(map[int]float64)
+	for _, session := range sessions {
+		bp.Forward(session.InputVariables, session.Timesteps)
+		for id, neuron := range bp.Neurons {
+			value := neuron.Value
+			s, seen := stats[id]
+			if !seen || value < s.Min {
+				s.Min = value
+			}
+			if !seen || value > s.Max {
+				s.Max = value
+			}
+			if value == 0 {
+				s.FractionZero++
+			}
+			s.Samples++
+			stats[id] = s
+			sums[id] += value
+			squares[id] += value * value
+		}
+	}
+
+	for id, s := range stats {
+		n := float64(s.Samples)
+		s.Mean = sums[id] / n
+		s.StdDev = math.Sqrt(math.Max(squares[id]/n-s.Mean*s.Mean, 0))
+		s.FractionZero /= n
+		stats[id] = s
+	}
+	return stats
+}
@@ -0,0 +1,38 @@
+package blueprint
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCollectActivationStatsFindsDeadNeuron(t *testing.T) {
+	bp := NewBlueprint()
+	bp.AddInputNeurons([]int{1, 2})
+	// Neuron 3 only sees non-negative inputs through negative weights, so it never fires
+	bp.Neurons[3] = &Neuron{ID: 3, Type: "dense", Activation: "relu", Bias: -1, Connections: [][]float64{{1, -1}, {2, -1}}}
+	bp.Neurons[4] = &Neuron{ID: 4, Type: "dense", Activation: "relu", Connections: [][]float64{{1, 1}, {2, 1}}}
+	bp.AddOutputNeurons([]int{5}, "linear")
+	bp.Neurons[5].Connections = [][]float64{{3, 1}, {4, 1}}
+	sessions := []Session{
+		{InputVariables: map[int]float64{1: 1, 2: 1}, Timesteps: 1},
+		{InputVariables: map[int]float64{1: 2, 2: 0}, Timesteps: 1},
+		{InputVariables: map[int]float64{1: 0.5, 2: 0.5}, Timesteps: 1},
+	}
+
+	stats := bp.CollectActivationStats(sessions)
+
+	dead := stats[3]
+	if dead.FractionZero != 1 || dead.Max != 0 || dead.Samples != 3 {
+		t.Errorf("dead neuron stats = %+v, want FractionZero 1, Max 0 and 3 samples", dead)
+	}
+	live := stats[4] // Values 2, 2 and 1
+	if live.FractionZero != 0 || live.Min != 1 || live.Max != 2 {
+		t.Errorf("live neuron stats = %+v, want FractionZero 0, Min 1 and Max 2", live)
+	}
+	if math.Abs(live.Mean-5.0/3) > 1e-12 || math.Abs(live.StdDev-math.Sqrt(2.0/9)) > 1e-12 {
+		t.Errorf("live neuron Mean = %v and StdDev = %v, want 5/3 and sqrt(2/9)", live.Mean, live.StdDev)
+	}
+	if len(bp.CollectActivationStats(nil)) != 0 {
+		t.Error("stats without sessions are not empty")
+	}
+}
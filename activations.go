@@ -1,6 +1,9 @@
 package blueprint
 
-import "math"
+import (
+	"math"
+	"sort"
+)
 
 // ActivationFunc defines the type for scalar activation functions
 type ActivationFunc func(float64) float64
@@ -12,6 +15,8 @@ var scalarActivationFunctions = map[string]ActivationFunc{
 	"tanh":       Tanh,
 	"leaky_relu": LeakyReLU,
 	"elu":        ELU,
+	"gelu":       GELU,
+	"swish":      Swish,
 	"linear":     Linear,
 }
 
@@ -46,6 +51,16 @@ func ELU(x float64) float64 {
 	return 1.0 * (math.Exp(x) - 1)
 }
 
+// GELU activation function, using the exact Gaussian CDF
+func GELU(x float64) float64 {
+	return 0.5 * x * (1 + math.Erf(x/math.Sqrt2))
+}
+
+// Swish activation function (x * sigmoid(x), also known as SiLU)
+func Swish(x float64) float64 {
+	return x * (1 / (1 + math.Exp(-x)))
+}
+
 // Linear activation function
 func Linear(x float64) float64 {
 	return x
@@ -60,3 +75,53 @@ func InitializeActivationFunctions() map[string]ActivationFunc {
 func (bp *Blueprint) InitializeActivationFunctions() {
 	bp.ScalarActivationMap = InitializeActivationFunctions()
 }
+
+// RegisterActivation adds a custom activation function under name, or replaces the one registered under it,
+// so neurons of this blueprint can use it as their Activation. The map is copied first, so other blueprints
+// keep their own activations; Clone carries the registration over. Custom activations run in Forward and
+// compiled plans but are not differentiated by TrainBackprop and cannot be exported by GenerateGoCode.
+func (bp *Blueprint) RegisterActivation(name string, fn ActivationFunc) {
+	activations := make(map[string]ActivationFunc, len(bp.ScalarActivationMap)+1)
+	for existing, f := range bp.ScalarActivationMap {
+		activations[existing] = f
+	}
+	if len(activations) == 0 {
+		for existing, f := range scalarActivationFunctions {
+			activations[existing] = f
+		}
+	}
+	activations[name] = fn
+	bp.ScalarActivationMap = activations
+	bp.invalidateCompiled()
+}
+
+// activationNames returns the names of the activations available to this blueprint's neurons, built-in and
+// registered, in sorted order.
+func (bp *Blueprint) activationNames() []string {
+	activations := bp.ScalarActivationMap
+	if activations == nil {
+		activations = scalarActivationFunctions
+	}
+	names := make([]string, 0, len(activations))
+	for name := range activations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// hasActivation reports whether name is a built-in or registered activation of this blueprint.
+func (bp *Blueprint) hasActivation(name string) bool {
+	activations := bp.ScalarActivationMap
+	if activations == nil {
+		activations = scalarActivationFunctions
+	}
+	_, ok := activations[name]
+	return ok
+}
+
+// randomActivation picks one of activationNames at random, so mutations can choose registered activations too.
+func (bp *Blueprint) randomActivation() string {
+	names := bp.activationNames()
+	return names[random.Intn(len(names))]
+}
@@ -0,0 +1,46 @@
+package blueprint
+
+import "testing"
+
+func square(x float64) float64 { return x * x }
+
+func TestRegisteredActivationIsUsedByRunNetwork(t *testing.T) {
+	bp := NewBlueprint()
+	bp.RegisterActivation("square", square)
+	bp.AddInputNeurons([]int{1})
+	bp.Neurons[2] = &Neuron{ID: 2, Type: "dense", Activation: "linear", Connections: [][]float64{{1, 1}}}
+	bp.AddOutputNeurons([]int{3}, "linear")
+	bp.Neurons[3].Connections = [][]float64{{2, 1}}
+
+	if err := bp.modifyActivationFunction(2, "square"); err != nil {
+		t.Fatalf("modifyActivationFunction rejected a registered activation: %v", err)
+	}
+	bp.RunNetwork(map[int]float64{1: -3}, 1)
+	if got := bp.Neurons[2].Value; got != 9 {
+		t.Errorf("hidden neuron with the square activation has value %v, want 9", got)
+	}
+
+	if err := bp.modifyActivationFunction(2, "cube"); err == nil {
+		t.Error("modifyActivationFunction accepted an unregistered activation")
+	}
+}
+
+func TestCreateNeuronChoosesRegisteredActivations(t *testing.T) {
+	randomSource.Seed(1)
+	bp := NewBlueprint()
+	bp.RegisterActivation("square", square)
+
+	for i := 0; i < 500; i++ {
+		neuron, err := bp.createNeuron(i+1, "dense")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bp.hasActivation(neuron.Activation) {
+			t.Fatalf("createNeuron chose unknown activation %q", neuron.Activation)
+		}
+		if neuron.Activation == "square" {
+			return
+		}
+	}
+	t.Error("createNeuron never chose the registered activation in 500 neurons")
+}
@@ -0,0 +1,125 @@
+package blueprint
+
+import "math"
+
+const (
+	// adamEpsilon keeps the Adam update finite when the second moment estimate is zero.
+	adamEpsilon = 1e-8
+	// adamGradientStep is the weight offset of the central finite differences used by AdamWeightUpdate.
+	adamGradientStep = 1e-5
+)
+
+// adamState holds the Adam moment estimates of every connection, keyed by source and target neuron ID.
+type adamState struct {
+	step   int
+	first  map[[2]int]float64
+	second map[[2]int]float64
+}
+
+// AdamWeightUpdate takes one Adam step on every connection weight, using central finite differences of the mean
+// cross-entropy between the softmaxed outputs and the normalized expected outputs over the sessions as the
+// gradient. Unlike TrainBackprop it works for every neuron type, at the cost of two passes over the sessions per
+// connection, and unlike HillClimbWeightUpdate it moves every weight downhill at once. Moment estimates persist
+// on the blueprint across calls, keyed by source and target, so duplicate connections share the first one's
+// moments and only that one is updated; they take two float64 values per connection plus map overhead, about
+// 100 bytes per connection, and are not serialized or cloned. Recurrent state makes the loss, and therefore the
+// gradient, noisy. The weights of frozen neurons are left unchanged. Requires float64 connection storage.
+func (bp *Blueprint) AdamWeightUpdate(sessions []Session, lr, beta1, beta2 float64) {
+	if len(sessions) == 0 {
+		bp.infof("No sessions provided for the Adam update.")
+		return
+	}
+	if bp.LowPrecision {
+		bp.infof("Weight updates require float64 storage. Call ConvertToFloat64Storage first.")
+		return
+	}
+	if lr <= 0 || beta1 < 0 || beta1 >= 1 || beta2 < 0 || beta2 >= 1 {
+		bp.warnf("Invalid Adam parameters: lr=%g must be positive, beta1=%g and beta2=%g must be in [0, 1).", lr, beta1, beta2)
+		return
+	}
+	if bp.ScalarActivationMap == nil {
+		bp.InitializeActivationFunctions()
+	}
+	defer bp.evalMode()()
+
+	if bp.adam == nil {
+		bp.adam = &adamState{first: make(map[[2]int]float64), second: make(map[[2]int]float64)}
+	}
+	bp.adam.step++
+
+	// Estimate the gradient of every connection before changing any weight
+	type connectionGradient struct {
+		neuron   *Neuron
+		index    int
+		key      [2]int
+		gradient float64
+	}
+	gradients := []connectionGradient{}
+	for _, targetID := range bp.getAllNeuronIDs() {
+		if bp.isFrozen(targetID) {
+			continue
+		}
+		neuron := bp.Neurons[targetID]
+		seen := make(map[int]bool)
+		for i := 0; i < neuron.numConnections(); i++ {
+			sourceID, weight := neuron.connection(i)
+			if seen[sourceID] {
+				continue
+			}
+			seen[sourceID] = true
+
+			neuron.setConnectionWeight(i, weight+adamGradientStep)
+			lossUp := bp.crossEntropyLoss(sessions)
+			neuron.setConnectionWeight(i, weight-adamGradientStep)
+			lossDown := bp.crossEntropyLoss(sessions)
+			neuron.setConnectionWeight(i, weight)
+
+			gradients = append(gradients, connectionGradient{
+				neuron:   neuron,
+				index:    i,
+				key:      [2]int{sourceID, targetID},
+				gradient: (lossUp - lossDown) / (2 * adamGradientStep),
+			})
+		}
+	}
+
+	// Bias-corrected Adam step
+	correction1 := 1 - math.Pow(beta1, float64(bp.adam.step))
+	correction2 := 1 - math.Pow(beta2, float64(bp.adam.step))
+	for _, g := range gradients {
+		first := beta1*bp.adam.first[g.key] + (1-beta1)*g.gradient
+		second := beta2*bp.adam.second[g.key] + (1-beta2)*g.gradient*g.gradient
+		bp.adam.first[g.key] = first
+		bp.adam.second[g.key] = second
+
+		_, weight := g.neuron.connection(g.index)
+		g.neuron.setConnectionWeight(g.index, weight-lr*(first/correction1)/(math.Sqrt(second/correction2)+adamEpsilon))
+	}
+	bp.invalidateCompiled()
+
+	bp.debugf("Adam step %d: loss %.6f", bp.adam.step, bp.crossEntropyLoss(sessions))
+}
+
+// crossEntropyLoss returns the mean cross-entropy between the softmaxed outputs and the expected outputs,
+// normalized to sum to 1, over the sessions. Sessions without a positive expected output are skipped.
+func (bp *Blueprint) crossEntropyLoss(sessions []Session) float64 {
+	total := 0.0
+	for _, session := range sessions {
+		bp.Forward(session.InputVariables, session.Timesteps)
+		outputs := bp.GetOutputs()
+
+		expectedTotal := 0.0
+		for _, id := range bp.OutputNodes {
+			expectedTotal += session.ExpectedOutput[id]
+		}
+		if expectedTotal <= 0 {
+			continue
+		}
+		for _, id := range bp.OutputNodes {
+			if p, exists := outputs[id]; exists {
+				total -= session.ExpectedOutput[id] / expectedTotal * math.Log(math.Max(p, minProbability))
+			}
+		}
+	}
+	return total / float64(len(sessions))
+}
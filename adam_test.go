@@ -0,0 +1,74 @@
+package blueprint
+
+import (
+	"math"
+	"testing"
+)
+
+// softRegressionTask returns a network with all-zero weights and sessions whose expected outputs are the
+// probabilities the network gives with weights 1 and -1.
+func softRegressionTask() (*Blueprint, []Session) {
+	bp := NewBlueprint()
+	bp.AddInputNeurons([]int{1})
+	bp.AddOutputNeurons([]int{2, 3}, "linear")
+	bp.Neurons[2].Connections = [][]float64{{1, 0}}
+	bp.Neurons[3].Connections = [][]float64{{1, 0}}
+
+	sessions := []Session{}
+	for _, x := range []float64{-1.5, -0.5, 0.5, 1.5} {
+		p := 1 / (1 + math.Exp(-2*x))
+		sessions = append(sessions, Session{
+			InputVariables: map[int]float64{1: x},
+			ExpectedOutput: map[int]float64{2: p, 3: 1 - p},
+			Timesteps:      1,
+		})
+	}
+	return bp, sessions
+}
+
+// outputMAE returns the mean absolute error of the network's outputs over the sessions.
+func outputMAE(bp *Blueprint, sessions []Session) float64 {
+	total, count := 0.0, 0
+	for _, session := range sessions {
+		outputs := bp.Predict(session.InputVariables, session.Timesteps)
+		for id, expected := range session.ExpectedOutput {
+			total += math.Abs(outputs[id] - expected)
+			count++
+		}
+	}
+	return total / float64(count)
+}
+
+// iterationsToMAE runs step until the MAE falls below target and returns the number of steps taken, or
+// maxIters if it never does.
+func iterationsToMAE(bp *Blueprint, sessions []Session, target float64, maxIters int, step func()) int {
+	for i := 0; i < maxIters; i++ {
+		if outputMAE(bp, sessions) < target {
+			return i
+		}
+		step()
+	}
+	return maxIters
+}
+
+func TestAdamConvergesFasterThanHillClimbing(t *testing.T) {
+	const target, maxIters = 0.02, 2000
+	randomSource.Seed(6)
+
+	adamBP, sessions := softRegressionTask()
+	adamIters := iterationsToMAE(adamBP, sessions, target, maxIters, func() {
+		adamBP.AdamWeightUpdate(sessions, 0.05, 0.9, 0.999)
+	})
+	hillBP, _ := softRegressionTask()
+	hillIters := iterationsToMAE(hillBP, sessions, target, maxIters, func() {
+		hillBP.HillClimbWeightUpdate(sessions)
+	})
+
+	if adamIters >= maxIters {
+		t.Fatalf("Adam did not reach an MAE of %v in %d iterations", target, maxIters)
+	}
+	if adamIters >= hillIters {
+		t.Errorf("Adam needed %d iterations and hill climbing %d, want Adam to need fewer", adamIters, hillIters)
+	}
+	t.Logf("Adam: %d iterations, hill climbing: %d", adamIters, hillIters)
+}
@@ -0,0 +1,111 @@
+package blueprint
+
+// adversarialLearningRate is the gradient descent step size used by AdversarialTrain.
+const adversarialLearningRate = 0.05
+
+// GenerateAdversarial returns a copy of input perturbed by a single fast gradient sign step of size epsilon.
+// With targetClass set to an output neuron ID, every input moves by epsilon against the sign of the gradient
+// of the cross-entropy towards that class, pushing the prediction to it. With any other targetClass, such as
+// -1, inputs move along the sign of the gradient of the loss of the current prediction, pushing away from it.
+// Gradients are computed like TrainBackprop, so they only flow through dense neurons; inputs whose gradient
+// is zero and IDs that are not input nodes are returned unchanged. The weights of the model are not modified.
+func (bp *Blueprint) GenerateAdversarial(input map[int]float64, epsilon float64, targetClass int) map[int]float64 {
+	if bp.ScalarActivationMap == nil {
+		bp.InitializeActivationFunctions()
+	}
+	order, trainable, err := bp.backpropOrder()
+	if err != nil {
+		bp.warnf("Cannot generate an adversarial input: %v", err)
+		return copyInputs(input)
+	}
+
+	// Descend the loss towards a target class, ascend it away from the current one
+	if bp.isOutputNode(targetClass) {
+		return bp.fgsmStep(input, map[int]float64{targetClass: 1}, -epsilon, order, trainable)
+	}
+	bp.Forward(input, 1)
+	return bp.fgsmStep(input, map[int]float64{argmaxMap(bp.GetOutputs()): 1}, epsilon, order, trainable)
+}
+
+// AdversarialTrain trains the model with gradient descent on the sessions together with an FGSM-perturbed copy
+// of each of them, keeping the original expected outputs. The perturbed copies are regenerated against the
+// current model at the start of every epoch by stepping each input by epsilon in the direction that increases
+// the loss of its expected output. After each epoch the exact accuracy on the clean sessions and on freshly
+// perturbed ones is printed. Training follows TrainBackprop, with a learning rate of 0.05.
+func (bp *Blueprint) AdversarialTrain(sessions []Session, epsilon float64, epochs int) {
+	if len(sessions) == 0 {
+		bp.infof("No sessions provided for adversarial training.")
+		return
+	}
+	if bp.LowPrecision {
+		bp.infof("Adversarial training requires float64 storage. Call ConvertToFloat64Storage first.")
+		return
+	}
+	if bp.ScalarActivationMap == nil {
+		bp.InitializeActivationFunctions()
+	}
+	order, trainable, err := bp.backpropOrder()
+	if err != nil {
+		bp.warnf("Cannot train adversarially: %v", err)
+		return
+	}
+
+	rates := bp.layerLearningRates()
+	for epoch := 0; epoch < epochs; epoch++ {
+		combined := append(append([]Session{}, sessions...), bp.adversarialSessions(sessions, epsilon, order, trainable)...)
+		random.Shuffle(len(combined), func(i, j int) { combined[i], combined[j] = combined[j], combined[i] })
+		for _, session := range combined {
+			bp.backpropSession(session, order, trainable, rates, adversarialLearningRate)
+		}
+		bp.invalidateCompiled()
+
+		cleanExact, _, _, _, _, _ := bp.EvaluateModelPerformance(sessions)
+		adversarialExact, _, _, _, _, _ := bp.EvaluateModelPerformance(bp.adversarialSessions(sessions, epsilon, order, trainable))
+		bp.infof("Adversarial epoch %d/%d: clean exact accuracy %.2f%%, adversarial exact accuracy %.2f%%",
+			epoch+1, epochs, cleanExact, adversarialExact)
+	}
+}
+
+// adversarialSessions returns a copy of every session whose inputs are moved by one FGSM step against its
+// expected outputs.
+func (bp *Blueprint) adversarialSessions(sessions []Session, epsilon float64, order []int, trainable map[int]bool) []Session {
+	perturbed := make([]Session, len(sessions))
+	for i, session := range sessions {
+		perturbed[i] = Session{
+			InputVariables: bp.fgsmStep(session.InputVariables, session.ExpectedOutput, epsilon, order, trainable),
+			ExpectedOutput: session.ExpectedOutput,
+			Timesteps:      session.Timesteps,
+		}
+	}
+	return perturbed
+}
+
+// fgsmStep moves every input node of input by epsilon along the sign of the gradient of the cross-entropy
+// between the model's outputs and expected, so a positive epsilon increases the loss and a negative one
+// decreases it. It returns a new map and leaves the weights untouched.
+func (bp *Blueprint) fgsmStep(input, expected map[int]float64, epsilon float64, order []int, trainable map[int]bool) map[int]float64 {
+	adversarial := copyInputs(input)
+	session := Session{InputVariables: input, ExpectedOutput: expected}
+	_, grads := bp.backpropSession(session, order, trainable, nil, 0)
+	for id := range adversarial {
+		if !bp.isInputNode(id) {
+			continue
+		}
+		switch g := grads[id]; {
+		case g > 0:
+			adversarial[id] += epsilon
+		case g < 0:
+			adversarial[id] -= epsilon
+		}
+	}
+	return adversarial
+}
+
+// copyInputs returns a copy of an input map.
+func copyInputs(input map[int]float64) map[int]float64 {
+	copied := make(map[int]float64, len(input))
+	for id, value := range input {
+		copied[id] = value
+	}
+	return copied
+}
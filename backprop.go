@@ -0,0 +1,206 @@
+package blueprint
+
+import (
+	"fmt"
+	"math"
+)
+
+// TrainBackprop trains the model with stochastic gradient descent, updating weights and biases in place after
+// every session. The loss is the cross-entropy between the softmaxed outputs, as returned by Forward, and the
+// expected outputs normalized to sum to 1. Each epoch visits the sessions in a shuffled order and runs one
+// feed-forward pass per session in layer order, ignoring Timesteps. Gradients flow through dense neurons with
+// relu, sigmoid, tanh, leaky_relu, elu, gelu, swish or linear activations; other neuron types such as rnn, lstm
+// or cnn still run in the forward pass but are left untrained and block the gradient. Updates are scaled by the
+// layer multipliers set with SetLayerLearningRates. Output neurons with bounded activations such as tanh or sigmoid
+// limit how confident the softmax can become and can saturate, so linear outputs train more reliably. Frozen
+// neurons keep their weights and bias but still pass the gradient on to their sources.
+func (bp *Blueprint) TrainBackprop(sessions []Session, learningRate float64, epochs int) error {
+	if len(sessions) == 0 {
+		return fmt.Errorf("no sessions to train on")
+	}
+	if learningRate <= 0 || math.IsNaN(learningRate) {
+		return fmt.Errorf("learning rate must be positive, got %g", learningRate)
+	}
+	if bp.LowPrecision {
+		return fmt.Errorf("backpropagation requires float64 storage, call ConvertToFloat64Storage first")
+	}
+	if bp.ScalarActivationMap == nil {
+		bp.InitializeActivationFunctions()
+	}
+
+	order, trainable, err := bp.backpropOrder()
+	if err != nil {
+		return err
+	}
+
+	rates := bp.layerLearningRates()
+	shuffled := append([]Session{}, sessions...)
+	for epoch := 0; epoch < epochs; epoch++ {
+		random.Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+		totalLoss := 0.0
+		for _, session := range shuffled {
+			loss, _ := bp.backpropSession(session, order, trainable, rates, learningRate)
+			totalLoss += loss
+		}
+		averageLoss := totalLoss / float64(len(sessions))
+		if math.IsNaN(averageLoss) || math.IsInf(averageLoss, 0) {
+			bp.invalidateCompiled()
+			return fmt.Errorf("training diverged at epoch %d, try a smaller learning rate", epoch+1)
+		}
+		bp.debugf("Backprop epoch %d: average loss %.6f", epoch+1, averageLoss)
+	}
+
+	bp.invalidateCompiled()
+	return nil
+}
+
+// backpropOrder returns the non-input neurons in layer order, so every source is computed before its targets,
+// and the set of neurons backpropagation can differentiate, warning about the others.
+func (bp *Blueprint) backpropOrder() ([]int, map[int]bool, error) {
+	layers, err := bp.ComputeLayers()
+	if err != nil {
+		return nil, nil, fmt.Errorf("backpropagation requires a feed-forward network: %w", err)
+	}
+
+	order := []int{}
+	trainable := make(map[int]bool)
+	skipped := []int{}
+	for _, layer := range layers {
+		for _, id := range layer {
+			neuron := bp.Neurons[id]
+			if bp.isInputNode(id) || neuron.Type == "input" {
+				continue
+			}
+			order = append(order, id)
+			if _, ok := bp.activationDerivative(neuron.Activation, 0, 0); ok && isDenseNeuronType(neuron.Type) {
+				trainable[id] = true
+			} else {
+				skipped = append(skipped, id)
+			}
+		}
+	}
+	if len(skipped) > 0 {
+		bp.warnf("Warning: backpropagation does not differentiate %d unsupported neurons: %v", len(skipped), skipped)
+	}
+	return order, trainable, nil
+}
+
+// backpropSession runs a forward and a backward pass for one session and returns the session's cross-entropy
+// loss and its gradient with respect to the value of every neuron, inputs included, before any update.
+// A positive learningRate also applies the gradient step to the weights and biases of the trainable neurons.
+func (bp *Blueprint) backpropSession(session Session, order []int, trainable map[int]bool, rates map[int]float64, learningRate float64) (float64, map[int]float64) {
+	bp.setInputValues(session.InputVariables)
+
+	// Forward pass, keeping the pre-activation sums of trainable neurons
+	sums := make(map[int]float64, len(trainable))
+	for _, id := range order {
+		neuron := bp.Neurons[id]
+		inputValues := []float64{}
+		sum := neuron.Bias
+		for i := 0; i < neuron.numConnections(); i++ {
+			sourceID, weight := neuron.connection(i)
+			if sourceNeuron, exists := bp.Neurons[sourceID]; exists {
+				inputValues = append(inputValues, sourceNeuron.Value*weight)
+				sum += sourceNeuron.Value * weight
+			}
+		}
+		if trainable[id] {
+			sums[id] = sum
+		}
+		bp.ProcessNeuron(neuron, inputValues, 0)
+	}
+
+	// Softmax cross-entropy gradient at the outputs
+	outputIDs := []int{}
+	outputValues := []float64{}
+	expectedTotal := 0.0
+	for _, id := range bp.OutputNodes {
+		if neuron, exists := bp.Neurons[id]; exists {
+			outputIDs = append(outputIDs, id)
+			outputValues = append(outputValues, neuron.Value)
+			expectedTotal += session.ExpectedOutput[id]
+		}
+	}
+	if len(outputIDs) == 0 || expectedTotal <= 0 {
+		return 0, nil
+	}
+	probs := Softmax(outputValues)
+
+	loss := 0.0
+	grads := make(map[int]float64, len(order))
+	for i, id := range outputIDs {
+		expected := session.ExpectedOutput[id] / expectedTotal
+		loss -= expected * math.Log(math.Max(probs[i], minProbability))
+		grads[id] += probs[i] - expected
+	}
+
+	// Backward pass in reverse layer order
+	for i := len(order) - 1; i >= 0; i-- {
+		id := order[i]
+		if !trainable[id] {
+			continue
+		}
+		neuron := bp.Neurons[id]
+		derivative, _ := bp.activationDerivative(neuron.Activation, sums[id], neuron.Value)
+		delta := grads[id] * derivative
+		if delta == 0 {
+			continue
+		}
+		step := learningRate * learningRateMultiplier(rates, id)
+		if bp.isFrozen(id) {
+			step = 0
+		}
+		for c := 0; c < neuron.numConnections(); c++ {
+			sourceID, weight := neuron.connection(c)
+			sourceNeuron, exists := bp.Neurons[sourceID]
+			if !exists {
+				continue
+			}
+			grads[sourceID] += delta * weight
+			if step > 0 {
+				neuron.setConnectionWeight(c, weight-step*delta*sourceNeuron.Value)
+			}
+		}
+		neuron.Bias -= step * delta
+	}
+
+	return loss, grads
+}
+
+// activationDerivative returns the derivative of a scalar activation given the neuron's pre-activation sum and
+// activated value. Activations missing from ScalarActivationMap run as linear in the forward pass and are
+// differentiated as such. It returns false for custom activations whose derivative is unknown.
+func (bp *Blueprint) activationDerivative(activation string, sum, value float64) (float64, bool) {
+	switch activation {
+	case "relu":
+		if sum > 0 {
+			return 1, true
+		}
+		return 0, true
+	case "sigmoid":
+		return value * (1 - value), true
+	case "tanh":
+		return 1 - value*value, true
+	case "leaky_relu":
+		if sum > 0 {
+			return 1, true
+		}
+		return 0.01, true
+	case "elu":
+		if sum >= 0 {
+			return 1, true
+		}
+		return value + 1, true
+	case "gelu":
+		return 0.5*(1+math.Erf(sum/math.Sqrt2)) + sum*math.Exp(-sum*sum/2)/math.Sqrt(2*math.Pi), true
+	case "swish":
+		sigmoid := 1 / (1 + math.Exp(-sum))
+		return sigmoid + sum*sigmoid*(1-sigmoid), true
+	case "linear":
+		return 1, true
+	}
+	if _, custom := bp.ScalarActivationMap[activation]; custom {
+		return 0, false
+	}
+	return 1, true
+}
@@ -0,0 +1,48 @@
+package blueprint
+
+import "testing"
+
+func xorSessions() []Session {
+	sessions := []Session{}
+	for _, x := range [][2]float64{{0, 0}, {0, 1}, {1, 0}, {1, 1}} {
+		class := 5.0
+		if x[0] != x[1] {
+			class = 6
+		}
+		sessions = append(sessions, Session{
+			InputVariables: map[int]float64{1: x[0], 2: x[1]},
+			ExpectedOutput: map[int]float64{5: boolFloat(class == 5), 6: boolFloat(class == 6)},
+			Timesteps:      1,
+		})
+	}
+	return sessions
+}
+
+func boolFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func TestTrainBackpropLearnsXOR(t *testing.T) {
+	randomSource.Seed(1)
+	bp := NewBlueprint()
+	bp.AddInputNeurons([]int{1, 2})
+	for _, id := range []int{3, 4} {
+		bp.Neurons[id] = &Neuron{ID: id, Type: "dense", Activation: "tanh", Bias: random.Float64()*2 - 1,
+			Connections: [][]float64{{1, random.Float64()*2 - 1}, {2, random.Float64()*2 - 1}}}
+	}
+	bp.AddOutputNeurons([]int{5, 6}, "linear")
+	for _, id := range []int{5, 6} {
+		bp.Neurons[id].Connections = [][]float64{{3, random.Float64()*2 - 1}, {4, random.Float64()*2 - 1}}
+	}
+
+	sessions := xorSessions()
+	if err := bp.TrainBackprop(sessions, 0.1, 3000); err != nil {
+		t.Fatal(err)
+	}
+	if exact, _, _, _, _, _ := bp.EvaluateModelPerformance(sessions); exact <= 95 {
+		t.Errorf("exact accuracy on XOR is %.1f%%, want above 95%%", exact)
+	}
+}
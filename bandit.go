@@ -0,0 +1,147 @@
+package blueprint
+
+import (
+	"math"
+	"sync"
+)
+
+// ArmStats reports what a Bandit has learned about one arm.
+type ArmStats struct {
+	Arm        string
+	Pulls      int     // Times the arm was selected
+	Reward     float64 // Total reward received
+	MeanReward float64 // Reward per pull
+}
+
+// Bandit chooses between named arms with the UCB1 rule: every arm is tried once, after which
+// the arm with the highest mean reward plus exploration bonus sqrt(2 ln N / n) is selected.
+// Select counts the pull immediately so concurrent callers spread over the arms before their
+// rewards arrive. A Bandit is safe for concurrent use.
+type Bandit struct {
+	mu      sync.Mutex
+	arms    []string
+	pulls   []int
+	rewards []float64
+	total   int
+}
+
+// NewBandit creates a Bandit over the given arms.
+func NewBandit(arms []string) *Bandit {
+	return &Bandit{
+		arms:    append([]string{}, arms...),
+		pulls:   make([]int, len(arms)),
+		rewards: make([]float64, len(arms)),
+	}
+}
+
+// Select returns the arm to try next, or an empty string if the bandit has no arms.
+func (b *Bandit) Select() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(b.arms) == 0 {
+		return ""
+	}
+
+	best, bestScore := 0, math.Inf(-1)
+	for i := range b.arms {
+		if b.pulls[i] == 0 {
+			best = i
+			break
+		}
+		score := b.rewards[i]/float64(b.pulls[i]) + math.Sqrt(2*math.Log(float64(b.total))/float64(b.pulls[i]))
+		if score > bestScore {
+			best, bestScore = i, score
+		}
+	}
+	b.pulls[best]++
+	b.total++
+	return b.arms[best]
+}
+
+// Update records the reward received for a previously selected arm. Unknown arms are ignored,
+// and calling Update on a nil Bandit does nothing.
+func (b *Bandit) Update(arm string, reward float64) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for i, name := range b.arms {
+		if name == arm {
+			b.rewards[i] += reward
+			return
+		}
+	}
+}
+
+// Arms returns the arms of the bandit.
+func (b *Bandit) Arms() []string {
+	return append([]string{}, b.arms...)
+}
+
+// Stats returns the learned statistics of every arm in the order the arms were given.
+func (b *Bandit) Stats() []ArmStats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	stats := make([]ArmStats, len(b.arms))
+	for i, arm := range b.arms {
+		stats[i] = ArmStats{Arm: arm, Pulls: b.pulls[i], Reward: b.rewards[i]}
+		if b.pulls[i] > 0 {
+			stats[i].MeanReward = b.rewards[i] / float64(b.pulls[i])
+		}
+	}
+	return stats
+}
+
+// defaultNeuronTypes are the neuron types sampled when no other list is given.
+var defaultNeuronTypes = []string{"dense", "rnn", "lstm", "cnn", "maxpool", "avgpool", "dropout", "batch_norm", "attention", "nca"}
+
+// AdaptiveTypeSampler returns a function that samples neuron types from the blueprint's type bandit.
+// The bandit learns which insertions improved the model during NAS runs configured with
+// NASConfig.AdaptiveTypes, so types that keep paying off are sampled more often while the rest
+// are still revisited now and then. Before any such run the bandit covers the default neuron types.
+func (bp *Blueprint) AdaptiveTypeSampler() func() string {
+	if bp.typeBandit == nil {
+		bp.typeBandit = NewBandit(defaultNeuronTypes)
+	}
+	return bp.typeBandit.Select
+}
+
+// NeuronTypeStats returns what the blueprint's type bandit has learned about each neuron type.
+func (bp *Blueprint) NeuronTypeStats() []ArmStats {
+	if bp.typeBandit == nil {
+		return nil
+	}
+	return bp.typeBandit.Stats()
+}
+
+// neuronTypeBandit returns the blueprint's type bandit, creating it over types if it does not exist
+// or was built for a different set of types.
+func (bp *Blueprint) neuronTypeBandit(types []string) *Bandit {
+	if bp.typeBandit == nil || !sameStrings(bp.typeBandit.Arms(), types) {
+		bp.typeBandit = NewBandit(types)
+	}
+	return bp.typeBandit
+}
+
+// sampleNeuronType picks the next neuron type to insert, from the bandit when one is given and
+// uniformly at random otherwise.
+func sampleNeuronType(bandit *Bandit, types []string) string {
+	if bandit != nil {
+		return bandit.Select()
+	}
+	return types[random.Intn(len(types))]
+}
+
+// sameStrings reports whether two string slices have the same elements in the same order.
+func sameStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,82 @@
+package blueprint
+
+// forwardBuffers holds the resolved connections of every non-input neuron and one reusable input slice per
+// neuron, so repeated forward passes over an unchanged topology do not allocate.
+type forwardBuffers struct {
+	neurons []*Neuron
+	sources [][]*Neuron
+	weights [][]float64
+	inputs  [][]float64
+}
+
+// newForwardBuffers resolves the connections of the non-input neurons in ascending ID order, the order used by
+// Forward, skipping connections from missing neurons like forwardTimestep does.
+func (bp *Blueprint) newForwardBuffers() *forwardBuffers {
+	buffers := &forwardBuffers{}
+	for _, id := range bp.getAllNeuronIDs() {
+		neuron := bp.Neurons[id]
+		if neuron.Type == "input" {
+			continue
+		}
+		sources := make([]*Neuron, 0, neuron.numConnections())
+		weights := make([]float64, 0, neuron.numConnections())
+		for i := 0; i < neuron.numConnections(); i++ {
+			sourceID, weight := neuron.connection(i)
+			if sourceNeuron, exists := bp.Neurons[sourceID]; exists {
+				sources = append(sources, sourceNeuron)
+				weights = append(weights, weight)
+			}
+		}
+		buffers.neurons = append(buffers.neurons, neuron)
+		buffers.sources = append(buffers.sources, sources)
+		buffers.weights = append(buffers.weights, weights)
+		buffers.inputs = append(buffers.inputs, make([]float64, len(sources)))
+	}
+	return buffers
+}
+
+// timestep is forwardTimestep over the preallocated buffers.
+func (bp *Blueprint) timestep(buffers *forwardBuffers, t int) {
+	for n, neuron := range buffers.neurons {
+		inputValues := buffers.inputs[n]
+		for i, sourceNeuron := range buffers.sources[n] {
+			inputValues[i] = sourceNeuron.Value * buffers.weights[n][i]
+		}
+		bp.ProcessNeuron(neuron, inputValues, t)
+	}
+
+	if bp.StateClamp > 0 {
+		bp.clampRecurrentState()
+	}
+}
+
+// ForwardBatch runs the network on every input map in turn and returns the output values after each run.
+// It produces the same outputs as calling RunNetwork and GetOutputs for each input in order, including any
+// state that carries over between runs, but resolves the connections and allocates the input buffers of every
+// neuron once for the whole batch. The network must not be modified while the batch runs.
+func (bp *Blueprint) ForwardBatch(inputsList []map[int]float64, timesteps int) []map[int]float64 {
+	if bp.ScalarActivationMap == nil {
+		bp.InitializeActivationFunctions()
+	}
+
+	outputs := make([]map[int]float64, len(inputsList))
+	if len(bp.QuantumNeurons) > 0 {
+		// Quantum neurons are only processed by forwardTimestep
+		for s, inputs := range inputsList {
+			bp.Forward(inputs, timesteps)
+			outputs[s] = bp.GetOutputs()
+		}
+		return outputs
+	}
+
+	buffers := bp.newForwardBuffers()
+	for s, inputs := range inputsList {
+		bp.setInputValues(inputs)
+		for t := 0; t < timesteps; t++ {
+			bp.timestep(buffers, t)
+		}
+		bp.ApplySoftmax()
+		outputs[s] = bp.GetOutputs()
+	}
+	return outputs
+}
@@ -0,0 +1,63 @@
+package blueprint
+
+import (
+	"math"
+	"testing"
+)
+
+// batchInputs returns n input maps for the input nodes of bp.
+func batchInputs(bp *Blueprint, n int) []map[int]float64 {
+	inputsList := make([]map[int]float64, n)
+	for s := range inputsList {
+		inputs := make(map[int]float64, len(bp.InputNodes))
+		for i, id := range bp.InputNodes {
+			inputs[id] = math.Sin(float64(s*len(bp.InputNodes) + i))
+		}
+		inputsList[s] = inputs
+	}
+	return inputsList
+}
+
+func TestForwardBatchMatchesRunNetwork(t *testing.T) {
+	randomSource.Seed(2)
+	bp := NewDenseMLP([]int{8, 16, 4}, "tanh")
+	bp.Neurons[30] = &Neuron{ID: 30, Type: "rnn", Activation: "tanh", Connections: [][]float64{{1, 0.5}, {9, -0.5}}}
+	bp.Neurons[25].Connections = append(bp.Neurons[25].Connections, []float64{30, 0.25})
+	inputsList := batchInputs(bp, 5)
+
+	reference := bp.DeepCopy()
+	got := bp.ForwardBatch(inputsList, 3)
+	for s, inputs := range inputsList {
+		reference.RunNetwork(inputs, 3)
+		for id, want := range reference.GetOutputs() {
+			if got[s][id] != want {
+				t.Errorf("session %d output %d is %v, RunNetwork gives %v", s, id, got[s][id], want)
+			}
+		}
+	}
+}
+
+func BenchmarkRunNetworkLoop(b *testing.B) {
+	randomSource.Seed(2)
+	bp := NewDenseMLP([]int{32, 64, 10}, "relu")
+	inputsList := batchInputs(bp, 64)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, inputs := range inputsList {
+			bp.RunNetwork(inputs, 1)
+			bp.GetOutputs()
+		}
+	}
+}
+
+func BenchmarkForwardBatch(b *testing.B) {
+	randomSource.Seed(2)
+	bp := NewDenseMLP([]int{32, 64, 10}, "relu")
+	inputsList := batchInputs(bp, 64)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		bp.ForwardBatch(inputsList, 1)
+	}
+}
@@ -1,8 +1,8 @@
 package blueprint
 
 import (
-	"fmt"
-	"math/rand"
+	"sort"
+	"sync"
 )
 
 // Blueprint encapsulates the entire neural network
@@ -12,7 +12,25 @@ type Blueprint struct {
 	InputNodes          []int                     `json:"input_nodes"`
 	OutputNodes         []int                     `json:"output_nodes"`
 	ScalarActivationMap map[string]ActivationFunc `json:"-"`
-	Debug               bool                      `json:"-"`
+	Debug               bool                      `json:"-"`                              // Sends debug messages to the Logger
+	Logger              Logger                    `json:"-"`                              // Receives the blueprint's messages; nil discards them
+	TrainingMode        bool                      `json:"-"`                              // Dropout neurons drop values at random; otherwise they scale them by 1 - DropoutRate
+	LayerLearningRates  []float64                 `json:"layer_learning_rates,omitempty"` // Per-layer learning-rate multipliers, see SetLayerLearningRates
+	LowPrecision        bool                      `json:"low_precision,omitempty"`        // Connection weights are stored as float32, see ConvertToFloat32Storage
+	StateClamp          float64                   `json:"state_clamp,omitempty"`          // Bound on recurrent neuron state after each timestep, 0 disables it
+	DecileStep          float64                   `json:"decile_step,omitempty"`          // Width of the error buckets of forgiveness accuracy, 0 uses defaultDecileStep
+	WeightInit          string                    `json:"weight_init,omitempty"`          // Strategy for the incoming weights of inserted neurons, see InitializeWeights; empty keeps uniform [-1, 1]
+
+	compiledMatrix *matrixPlan        // Cached layer matrices for ForwardMatrix
+	compiledPlan   *ExecutionPlan     // Cached plan returned by Compile
+	typeBandit     *Bandit            // Neuron type statistics learned by adaptive NAS, see AdaptiveTypeSampler
+	modBandit      *Bandit            // Modification type statistics learned by LearnOneDataItemAtATime
+	scoreHistory   []EvaluationResult // Sampled evaluations of this model during NAS, see SmoothedMetrics
+	frozenNeurons  map[int]bool       // Neurons NAS must leave untouched, see NASConfig.MutableNeuronIDs
+	nextNeuronID   int                // Next ID handed out by generateUniqueNeuronID, 0 until first use
+	neuronMu       *sync.Mutex        // Serializes neuron insertion, see neuronLock
+	rpcMu          *sync.Mutex        // Serializes calls received over HTTP, see rpcLock
+	adam           *adamState         // Moment estimates of AdamWeightUpdate
 }
 
 // ModelMetadata holds metadata, evaluation benchmarks, and additional information for models in the AI framework.
@@ -32,6 +50,10 @@ type ModelMetadata struct {
 	LayerRange   [2]int `json:"layerRange"`  // Min and max layers
 	NeuronRange  [2]int `json:"neuronRange"` // Min and max neurons per layer
 
+	// Parameter counts, see ParameterCount and EffectiveParameters
+	TotalParameters     int `json:"totalParameters"`
+	EffectiveParameters int `json:"effectiveParameters"` // Excludes duplicate connections and foldable linear chains
+
 	// Accuracy and error metrics
 	LastTrainingAccuracy              float64 `json:"lastTrainingAccuracy"`
 	LastTestAccuracy                  float64 `json:"lastTestAccuracy"`
@@ -93,19 +115,56 @@ func NewBlueprint() *Blueprint {
 func (bp *Blueprint) RandomWeights(size int) []float64 {
 	weights := make([]float64, size)
 	for i := range weights {
-		weights[i] = rand.NormFloat64() * 0.5 // Increase scale
+		weights[i] = random.NormFloat64() * 0.5 // Increase scale
 	}
 	return weights
 }
 
-// AddInputNodes adds multiple input nodes to the network
+// AddInputNodes adds multiple input nodes to the network.
+// It only registers the IDs; Forward ignores input values for IDs that have no neuron in
+// bp.Neurons, so use AddInputNeurons unless the neurons are created separately.
 func (bp *Blueprint) AddInputNodes(ids []int) {
 	bp.InputNodes = append(bp.InputNodes, ids...)
+	bp.invalidateCompiled()
 }
 
-// AddOutputNodes adds multiple output nodes to the network
+// AddOutputNodes adds multiple output nodes to the network.
+// Like AddInputNodes it only registers the IDs; see AddOutputNeurons.
 func (bp *Blueprint) AddOutputNodes(ids []int) {
 	bp.OutputNodes = append(bp.OutputNodes, ids...)
+	bp.invalidateCompiled()
+}
+
+// AddInputNeurons registers the IDs as input nodes and creates an input-typed neuron for each ID
+// that does not exist yet. Existing neurons are left untouched.
+func (bp *Blueprint) AddInputNeurons(ids []int) {
+	for _, id := range ids {
+		if _, exists := bp.Neurons[id]; !exists {
+			bp.Neurons[id] = &Neuron{
+				ID:          id,
+				Type:        "input",
+				Activation:  "linear",
+				Connections: [][]float64{},
+			}
+		}
+	}
+	bp.AddInputNodes(ids)
+}
+
+// AddOutputNeurons registers the IDs as output nodes and creates a dense neuron with the given
+// activation for each ID that does not exist yet. Existing neurons are left untouched.
+func (bp *Blueprint) AddOutputNeurons(ids []int, activation string) {
+	for _, id := range ids {
+		if _, exists := bp.Neurons[id]; !exists {
+			bp.Neurons[id] = &Neuron{
+				ID:          id,
+				Type:        "dense",
+				Activation:  activation,
+				Connections: [][]float64{},
+			}
+		}
+	}
+	bp.AddOutputNodes(ids)
 }
 
 // ApplyScalarActivation applies the specified scalar activation function
@@ -114,55 +173,76 @@ func (bp *Blueprint) ApplyScalarActivation(value float64, activation string) flo
 		return actFunc(value)
 	}
 	// Log a warning and use linear activation
-	if bp.Debug {
-		fmt.Printf("Warning: Undefined activation '%s'. Using linear activation.\n", activation)
-	}
+	bp.debugf("Warning: Undefined activation '%s'. Using linear activation.", activation)
 	return Linear(value)
 }
 
 // Forward propagates inputs through the network
-// Forward propagates inputs through the network
+// Quantum neurons are processed at the start of every timestep, see processQuantumNeurons, and classical
+// neurons connected to them read their measured value.
 func (bp *Blueprint) Forward(inputs map[int]float64, timesteps int) {
-	// Set input neurons
+	bp.setInputValues(inputs)
+
+	// Neurons are processed in ascending ID order, which also covers IDs left sparse by RemoveNeuron
+	neuronIDs := bp.getAllNeuronIDs()
+	sort.Ints(neuronIDs)
+
+	// Process neurons over timesteps
+	for t := 0; t < timesteps; t++ {
+		bp.forwardTimestep(neuronIDs, t)
+	}
+
+	// Apply softmax to output neurons
+	bp.ApplySoftmax()
+}
+
+// setInputValues sets the values of the input neurons
+func (bp *Blueprint) setInputValues(inputs map[int]float64) {
 	for id, value := range inputs {
 		if neuron, exists := bp.Neurons[id]; exists {
 			neuron.Value = value
-			if bp.Debug {
-				fmt.Printf("Input Neuron %d set to %f\n", id, value)
-			}
+			bp.debugf("Input Neuron %d set to %f", id, value)
+		} else if bp.Debug {
+			bp.warnf("Warning: Input %d has no neuron and is ignored. Create it with AddInputNeurons.", id)
 		}
 	}
+}
 
-	// Process neurons over timesteps
-	for t := 0; t < timesteps; t++ {
-		if bp.Debug {
-			fmt.Printf("=== Timestep %d ===\n", t)
-		}
+// forwardTimestep processes every quantum neuron, then every non-input neuron once in the order of neuronIDs,
+// then applies StateClamp to the recurrent neurons
+func (bp *Blueprint) forwardTimestep(neuronIDs []int, t int) {
+	bp.debugf("=== Timestep %d ===", t)
 
-		// Process all neurons, including hidden neurons
-		for id := 1; id <= len(bp.Neurons); id++ {
-			neuron, exists := bp.Neurons[id]
-			if !exists || neuron.Type == "input" { // Skip input neurons
-				continue
-			}
+	// Quantum neurons run first, so classical neurons read this timestep's measurements
+	if len(bp.QuantumNeurons) > 0 {
+		bp.processQuantumNeurons()
+	}
 
-			// Gather inputs from connected neurons
-			inputValues := []float64{}
-			for _, conn := range neuron.Connections {
-				sourceID := int(conn[0])
-				weight := conn[1]
-				if sourceNeuron, exists := bp.Neurons[sourceID]; exists {
-					inputValues = append(inputValues, sourceNeuron.Value*weight)
-				}
-			}
+	// Process all neurons, including hidden neurons
+	for _, id := range neuronIDs {
+		neuron := bp.Neurons[id]
+		if neuron.Type == "input" { // Skip input neurons
+			continue
+		}
 
-			// Process the neuron
-			bp.ProcessNeuron(neuron, inputValues, t)
+		// Gather inputs from connected neurons
+		inputValues := []float64{}
+		for i := 0; i < neuron.numConnections(); i++ {
+			sourceID, weight := neuron.connection(i)
+			if sourceNeuron, exists := bp.Neurons[sourceID]; exists {
+				inputValues = append(inputValues, sourceNeuron.Value*weight)
+			} else if quantumNeuron, exists := bp.QuantumNeurons[sourceID]; exists {
+				inputValues = append(inputValues, quantumNeuron.measuredValue()*weight)
+			}
 		}
+
+		// Process the neuron
+		bp.ProcessNeuron(neuron, inputValues, t)
 	}
 
-	// Apply softmax to output neurons
-	bp.ApplySoftmax()
+	if bp.StateClamp > 0 {
+		bp.clampRecurrentState()
+	}
 }
 
 // RunNetwork runs the neural network with given inputs and timesteps
@@ -170,20 +250,31 @@ func (bp *Blueprint) RunNetwork(inputs map[int]float64, timesteps int) {
 	bp.Forward(inputs, timesteps)
 	if bp.Debug {
 		outputs := bp.GetOutputs()
-		fmt.Println("Final Outputs:")
+		bp.debugf("Final Outputs:")
 		for id, value := range outputs {
-			fmt.Printf("Neuron %d: %f\n", id, value)
+			bp.debugf("Neuron %d: %f", id, value)
 		}
 	}
 }
 
-// GetOutputs retrieves the output values from the network
+// GetOutputs retrieves the output values from the network. Quantum output neurons report the softmaxed
+// value of their last measurement, see ApplySoftmax.
 func (bp *Blueprint) GetOutputs() map[int]float64 {
 	outputs := make(map[int]float64)
 	for _, id := range bp.OutputNodes {
 		if neuron, exists := bp.Neurons[id]; exists {
 			outputs[id] = neuron.Value
+		} else if quantumNeuron, exists := bp.QuantumNeurons[id]; exists {
+			outputs[id] = quantumNeuron.output
 		}
 	}
 	return outputs
 }
+
+// Predict runs the network and returns its outputs, leaving the blueprint's neuron values untouched.
+// It starts from the current values like RunNetwork but discards the resulting state, so repeated calls with
+// the same inputs return the same outputs. Since it only reads the blueprint, Predict may be called from several
+// goroutines at once as long as none of them modifies the blueprint. See ForwardState.
+func (bp *Blueprint) Predict(inputs map[int]float64, timesteps int) map[int]float64 {
+	return bp.ForwardState(inputs, timesteps)
+}
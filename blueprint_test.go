@@ -0,0 +1,68 @@
+package blueprint
+
+import (
+	"math"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestForwardAfterRemovingMiddleNeuron(t *testing.T) {
+	bp := NewBlueprint()
+	bp.AddInputNeurons([]int{1})
+	for id := 2; id <= 4; id++ {
+		bp.Neurons[id] = &Neuron{ID: id, Type: "dense", Activation: "linear", Connections: [][]float64{{1, float64(id)}}}
+	}
+	bp.AddOutputNeurons([]int{5, 6}, "linear")
+	bp.Neurons[5].Connections = [][]float64{{3, 1}, {4, 1}}
+	bp.Neurons[6].Connections = [][]float64{{2, 1}}
+
+	bp.RemoveNeuron(2)
+	// Five neurons remain, so a loop over IDs 1 to len(bp.Neurons) would never process output 6
+	bp.RunNetwork(map[int]float64{1: 1}, 1)
+
+	if got := bp.Neurons[4].Value; got != 4 {
+		t.Errorf("neuron 4 has value %v, want 4", got)
+	}
+	// Output 5 sums 3 + 4 and output 6 has lost its only source, so the softmax favours output 5
+	want5 := math.Exp(7) / (math.Exp(7) + 1)
+	if got := bp.Neurons[5].Value; math.Abs(got-want5) > 1e-12 {
+		t.Errorf("output 5 has value %v, want %v", got, want5)
+	}
+	if got := bp.Neurons[6].Value; math.Abs(got-(1-want5)) > 1e-12 {
+		t.Errorf("output 6 has value %v, want %v", got, 1-want5)
+	}
+}
+
+func TestPredictConcurrently(t *testing.T) {
+	randomSource.Seed(1)
+	bp := NewDenseMLP([]int{4, 8, 3}, "relu")
+	inputs := map[int]float64{1: 0.1, 2: -0.4, 3: 0.7, 4: 1}
+	values := make(map[int]float64, len(bp.Neurons))
+	for id, neuron := range bp.Neurons {
+		values[id] = neuron.Value
+	}
+	want := bp.Predict(inputs, 1)
+
+	var wg sync.WaitGroup
+	results := make([]map[int]float64, 2)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = bp.Predict(inputs, 1)
+		}(i)
+	}
+	wg.Wait()
+
+	for i, got := range results {
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("concurrent Predict %d returned %v, want %v", i, got, want)
+		}
+	}
+	for id, neuron := range bp.Neurons {
+		if neuron.Value != values[id] {
+			t.Errorf("Predict changed neuron %d value from %v to %v", id, values[id], neuron.Value)
+		}
+	}
+}
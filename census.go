@@ -0,0 +1,56 @@
+package blueprint
+
+import "sort"
+
+// NeuronTypeCounts returns the number of neurons of each type, input neurons included.
+// Quantum neurons are kept separately and are not counted.
+func (bp *Blueprint) NeuronTypeCounts() map[string]int {
+	counts := make(map[string]int)
+	for _, neuron := range bp.Neurons {
+		counts[neuron.Type]++
+	}
+	return counts
+}
+
+// TotalConnections returns the number of stored connections across all neurons, duplicates included.
+func (bp *Blueprint) TotalConnections() int {
+	total := 0
+	for _, neuron := range bp.Neurons {
+		total += neuron.numConnections()
+	}
+	return total
+}
+
+// FillModelMetadata sets the structural fields of meta from the model: the neuron, layer and parameter counts,
+// the neuron types in use and whether attention or dropout appear. The other fields are left unchanged.
+// Layer fields are only set for feed-forward models, since ComputeLayers fails on cycles.
+func (bp *Blueprint) FillModelMetadata(meta *ModelMetadata) {
+	counts := bp.NeuronTypeCounts()
+	meta.TotalNeurons = int64(len(bp.Neurons))
+	meta.TotalParameters = bp.ParameterCount()
+	meta.EffectiveParameters = bp.EffectiveParameters()
+
+	meta.NeuronTypes = make([]string, 0, len(counts))
+	for neuronType := range counts {
+		meta.NeuronTypes = append(meta.NeuronTypes, neuronType)
+	}
+	sort.Strings(meta.NeuronTypes)
+	meta.DropoutUsed = counts["dropout"] > 0
+	meta.AttentionMechanisms = counts["attention"] > 0
+	for _, neuron := range bp.Neurons {
+		if neuron.Attention {
+			meta.AttentionMechanisms = true
+			break
+		}
+	}
+
+	if layers, err := bp.ComputeLayers(); err == nil && len(layers) > 0 {
+		meta.TotalLayers = int64(len(layers))
+		meta.LayerRange = [2]int{0, len(layers) - 1}
+		meta.NeuronRange = [2]int{len(layers[0]), len(layers[0])}
+		for _, layer := range layers {
+			meta.NeuronRange[0] = min(meta.NeuronRange[0], len(layer))
+			meta.NeuronRange[1] = max(meta.NeuronRange[1], len(layer))
+		}
+	}
+}
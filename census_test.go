@@ -0,0 +1,48 @@
+package blueprint
+
+import (
+	"maps"
+	"slices"
+	"testing"
+)
+
+func TestCensusOfKnownNetwork(t *testing.T) {
+	bp := NewDenseMLP([]int{3, 4, 2}, "relu") // Inputs 1-3, hidden 4-7, outputs 8-9
+	bp.Neurons[20] = &Neuron{ID: 20, Type: "rnn", Activation: "tanh", Connections: [][]float64{{1, 1}, {2, 1}}}
+	bp.Neurons[21] = &Neuron{ID: 21, Type: "dropout", Activation: "linear", Connections: [][]float64{{20, 1}}}
+
+	wantCounts := map[string]int{"input": 3, "dense": 6, "rnn": 1, "dropout": 1}
+	if counts := bp.NeuronTypeCounts(); !maps.Equal(counts, wantCounts) {
+		t.Errorf("got type counts %v, want %v", counts, wantCounts)
+	}
+	if total := bp.TotalConnections(); total != 3*4+4*2+2+1 {
+		t.Errorf("got %d connections, want 23", total)
+	}
+
+	var meta ModelMetadata
+	bp.FillModelMetadata(&meta)
+	if meta.TotalNeurons != 11 {
+		t.Errorf("TotalNeurons is %d, want 11", meta.TotalNeurons)
+	}
+	// Every non-input neuron has a bias, the RNN a recurrent weight
+	if meta.TotalParameters != 8+23+1 {
+		t.Errorf("TotalParameters is %d, want 32", meta.TotalParameters)
+	}
+	if want := []string{"dense", "dropout", "input", "rnn"}; !slices.Equal(meta.NeuronTypes, want) {
+		t.Errorf("NeuronTypes is %v, want %v", meta.NeuronTypes, want)
+	}
+	if !meta.DropoutUsed || meta.AttentionMechanisms {
+		t.Errorf("DropoutUsed is %v and AttentionMechanisms %v, want true and false", meta.DropoutUsed, meta.AttentionMechanisms)
+	}
+	// Layers [1 2 3], [4 5 6 7 20] and [8 9 21]
+	if meta.TotalLayers != 3 || meta.NeuronRange != [2]int{3, 5} {
+		t.Errorf("got %d layers of %v neurons, want 3 layers of [3 5]", meta.TotalLayers, meta.NeuronRange)
+	}
+
+	if err := bp.ConvertToFloat32Storage(); err != nil {
+		t.Fatal(err)
+	}
+	if total := bp.TotalConnections(); total != 23 {
+		t.Errorf("got %d connections in float32 storage, want 23", total)
+	}
+}
@@ -0,0 +1,236 @@
+package blueprint
+
+import (
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// cmaesInitialSigma is the initial step size of TrainCMAES, matching the [-1, 1] range of initial weights.
+const cmaesInitialSigma = 0.5
+
+// TrainCMAES optimizes every connection weight and bias with the covariance matrix adaptation evolution strategy
+// (CMA-ES). Parameters are flattened into one vector in ascending neuron ID order, each neuron's bias followed by
+// its connection weights, and candidates are scored with the fitness of EvolutionaryTrain, so WithFitnessWeights
+// WithMetricsBuffer and WithProgressCallback apply; WithSpectralNormalization is ignored. Unlike EvolutionaryTrain the architecture
+// stays fixed. The search starts from the current weights, samples populationSize candidates per generation
+// (fewer than 2 uses the default 4 + 3 ln n for n parameters) and writes the best candidate seen back into the
+// blueprint. The covariance matrix takes n² floats and is decomposed in O(n³) every generation, so TrainCMAES
+// suits networks up to a few hundred parameters. Frozen neurons are left out of the search and keep their
+// weights. Requires float64 connection storage.
+func (bp *Blueprint) TrainCMAES(sessions []Session, populationSize, generations int, opts ...EvolutionOption) {
+	if len(sessions) == 0 {
+		bp.infof("No sessions provided for CMA-ES.")
+		return
+	}
+	if bp.LowPrecision {
+		bp.infof("Weight updates require float64 storage. Call ConvertToFloat64Storage first.")
+		return
+	}
+	cfg := evolutionConfig{}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	mean := bp.parameterVector()
+	n := len(mean)
+	if n == 0 {
+		bp.infof("No parameters available for CMA-ES.")
+		return
+	}
+	score := func(x []float64) (float64, NASMetrics) {
+		bp.setParameterVector(x)
+		exact, generous, forgiveness, _, _, _ := bp.EvaluateModelPerformance(sessions)
+		metrics := NASMetrics{ExactAccuracy: exact, GenerousAccuracy: generous, ForgivenessAccuracy: forgiveness, NeuronCount: len(bp.Neurons)}
+		return cfg.fitness(exact, generous, forgiveness), metrics
+	}
+
+	// Strategy parameters from Hansen's CMA-ES tutorial
+	lambda := populationSize
+	if lambda < 2 {
+		lambda = 4 + int(3*math.Log(float64(n)))
+	}
+	mu := max(lambda/2, 1)
+	weights := make([]float64, mu)
+	weightSum, weightSquares := 0.0, 0.0
+	for i := range weights {
+		weights[i] = math.Log(float64(mu)+0.5) - math.Log(float64(i+1))
+		weightSum += weights[i]
+	}
+	for i := range weights {
+		weights[i] /= weightSum
+		weightSquares += weights[i] * weights[i]
+	}
+	nf := float64(n)
+	mueff := 1 / weightSquares
+	cc := (4 + mueff/nf) / (nf + 4 + 2*mueff/nf)
+	cs := (mueff + 2) / (nf + mueff + 5)
+	c1 := 2 / ((nf+1.3)*(nf+1.3) + mueff)
+	cmu := math.Min(1-c1, 2*(mueff-2+1/mueff)/((nf+2)*(nf+2)+mueff))
+	damps := 1 + 2*math.Max(0, math.Sqrt((mueff-1)/(nf+1))-1) + cs
+	chiN := math.Sqrt(nf) * (1 - 1/(4*nf) + 1/(21*nf*nf))
+
+	// State: step size, evolution paths and the covariance C = B diag(D²) Bᵀ
+	sigma := cmaesInitialSigma
+	pc := make([]float64, n)
+	ps := make([]float64, n)
+	C := mat.NewSymDense(n, nil)
+	B := mat.NewDense(n, n, nil)
+	D := make([]float64, n)
+	for i := 0; i < n; i++ {
+		C.SetSym(i, i, 1)
+		B.Set(i, i, 1)
+		D[i] = 1
+	}
+
+	best := append([]float64{}, mean...)
+	bestScore, _ := score(best)
+	bp.infof("CMA-ES over %d parameters, population %d. Initial score: %.2f", n, lambda, bestScore)
+
+	type candidate struct {
+		x, y  []float64 // Sample and its step from the mean in units of sigma
+		score float64
+	}
+	for gen := 1; gen <= generations; gen++ {
+		// Sample x = mean + sigma * B D z with z ~ N(0, I)
+		population := make([]candidate, lambda)
+		var generationBest NASMetrics
+		generationBestScore := 0.0
+		for k := range population {
+			z := make([]float64, n)
+			for i := range z {
+				z[i] = D[i] * random.NormFloat64()
+			}
+			y := make([]float64, n)
+			x := make([]float64, n)
+			for i := 0; i < n; i++ {
+				for j := 0; j < n; j++ {
+					y[i] += B.At(i, j) * z[j]
+				}
+				x[i] = mean[i] + sigma*y[i]
+			}
+			s, metrics := score(x)
+			population[k] = candidate{x: x, y: y, score: s}
+			if k == 0 || s > generationBestScore {
+				generationBestScore = s
+				metrics.Iteration = gen
+				generationBest = metrics
+			}
+		}
+		sort.SliceStable(population, func(a, b int) bool { return population[a].score > population[b].score })
+		generationBest.CandidateSpread = population[0].score - population[lambda-1].score
+		generationBest.Improved = population[0].score > bestScore
+		if generationBest.Improved {
+			bestScore = population[0].score
+			best = append(best[:0], population[0].x...)
+		}
+		cfg.metrics.Push(generationBest)
+		bp.infof("CMA-ES generation %d: best score %.2f, sigma %.4g", gen, population[0].score, sigma)
+		if err := cfg.progress.call(gen, generationBest); err != nil {
+			bp.infof("CMA-ES stopped by the progress callback: %v", err)
+			break
+		}
+
+		// Move the mean to the weighted average of the best mu samples
+		yw := make([]float64, n)
+		for k := 0; k < mu; k++ {
+			for i := 0; i < n; i++ {
+				yw[i] += weights[k] * population[k].y[i]
+			}
+		}
+		for i := 0; i < n; i++ {
+			mean[i] += sigma * yw[i]
+		}
+
+		// Update the evolution paths, using C^(-1/2) = B D⁻¹ Bᵀ for the step size path
+		invSqrtY := make([]float64, n)
+		for j := 0; j < n; j++ {
+			projected := 0.0
+			for i := 0; i < n; i++ {
+				projected += B.At(i, j) * yw[i]
+			}
+			projected /= D[j]
+			for i := 0; i < n; i++ {
+				invSqrtY[i] += B.At(i, j) * projected
+			}
+		}
+		psNorm := 0.0
+		for i := 0; i < n; i++ {
+			ps[i] = (1-cs)*ps[i] + math.Sqrt(cs*(2-cs)*mueff)*invSqrtY[i]
+			psNorm += ps[i] * ps[i]
+		}
+		psNorm = math.Sqrt(psNorm)
+		hsig := 0.0
+		if psNorm/math.Sqrt(1-math.Pow(1-cs, float64(2*gen)))/chiN < 1.4+2/(nf+1) {
+			hsig = 1
+		}
+		for i := 0; i < n; i++ {
+			pc[i] = (1-cc)*pc[i] + hsig*math.Sqrt(cc*(2-cc)*mueff)*yw[i]
+		}
+
+		// Rank-one and rank-mu covariance update
+		for i := 0; i < n; i++ {
+			for j := i; j < n; j++ {
+				rankMu := 0.0
+				for k := 0; k < mu; k++ {
+					rankMu += weights[k] * population[k].y[i] * population[k].y[j]
+				}
+				value := (1-c1-cmu)*C.At(i, j) +
+					c1*(pc[i]*pc[j]+(1-hsig)*cc*(2-cc)*C.At(i, j)) +
+					cmu*rankMu
+				C.SetSym(i, j, value)
+			}
+		}
+		sigma *= math.Exp((cs / damps) * (psNorm/chiN - 1))
+
+		// Decompose C for the next generation's samples
+		var eigen mat.EigenSym
+		if !eigen.Factorize(C, true) {
+			bp.warnf("CMA-ES covariance decomposition failed. Stopping early.")
+			break
+		}
+		eigen.VectorsTo(B)
+		for i, value := range eigen.Values(nil) {
+			D[i] = math.Sqrt(math.Max(value, 1e-20))
+		}
+	}
+
+	bp.setParameterVector(best)
+	bp.infof("CMA-ES completed. Best score: %.2f", bestScore)
+}
+
+// parameterVector returns every bias and float64 connection weight of the non-input, unfrozen neurons in
+// ascending neuron ID order, each neuron's bias followed by its connection weights.
+func (bp *Blueprint) parameterVector() []float64 {
+	params := []float64{}
+	for _, id := range bp.getAllNeuronIDs() {
+		neuron := bp.Neurons[id]
+		if neuron.Type == "input" || bp.isFrozen(id) {
+			continue
+		}
+		params = append(params, neuron.Bias)
+		for _, conn := range neuron.Connections {
+			params = append(params, conn[1])
+		}
+	}
+	return params
+}
+
+// setParameterVector writes a vector laid out like parameterVector back into the neurons.
+func (bp *Blueprint) setParameterVector(params []float64) {
+	p := 0
+	for _, id := range bp.getAllNeuronIDs() {
+		neuron := bp.Neurons[id]
+		if neuron.Type == "input" || bp.isFrozen(id) {
+			continue
+		}
+		neuron.Bias = params[p]
+		p++
+		for i := range neuron.Connections {
+			neuron.Connections[i][1] = params[p]
+			p++
+		}
+	}
+	bp.invalidateCompiled()
+}
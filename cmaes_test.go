@@ -0,0 +1,25 @@
+package blueprint
+
+import "testing"
+
+func TestCMAESConvergesFasterThanEvolutionaryTrain(t *testing.T) {
+	// Both trainers evaluate the same number of candidates: CMA-ES one per sample plus the starting point,
+	// EvolutionaryTrain one per individual every generation plus a final pass over the last population.
+	const population, generations, runs = 8, 15, 5
+	cmaesMAE, evolutionMAE := 0.0, 0.0
+	for seed := int64(1); seed <= runs; seed++ {
+		randomSource.Seed(seed)
+		cmaesBP, sessions := softRegressionTask()
+		cmaesBP.TrainCMAES(sessions, population, generations)
+		cmaesMAE += outputMAE(cmaesBP, sessions) / runs
+
+		evolutionBP, _ := softRegressionTask()
+		evolutionBP.EvolutionaryTrain(sessions, population, generations-1)
+		evolutionMAE += outputMAE(evolutionBP, sessions) / runs
+	}
+	t.Logf("mean MAE after %d evaluations: CMA-ES %.4f, evolutionary %.4f", 1+population*generations, cmaesMAE, evolutionMAE)
+
+	if cmaesMAE >= evolutionMAE/2 {
+		t.Errorf("CMA-ES reached mean MAE %.4f, want under half of EvolutionaryTrain's %.4f", cmaesMAE, evolutionMAE)
+	}
+}
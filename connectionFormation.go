@@ -2,7 +2,6 @@ package blueprint
 
 import (
 	"fmt"
-	"math/rand"
 	"runtime"
 	"sync"
 )
@@ -15,7 +14,7 @@ type ConnectionAttempt struct {
 	ExactAcc    float64
 	GenerousAcc float64
 	ForgiveAcc  float64
-	ModelJSON   string
+	Model       *Blueprint // The model with the connection added
 	Improvement float64
 }
 
@@ -26,7 +25,7 @@ func (bp *Blueprint) TryAddConnections(
 	sessions []Session,
 	maxAttempts int,
 ) {
-	fmt.Println("Starting TryAddConnections phase...")
+	bp.infof("Starting TryAddConnections phase...")
 
 	// Evaluate initial performance
 	initialExact, initialGenerous, initialForgive, _, _, _ :=
@@ -36,13 +35,6 @@ func (bp *Blueprint) TryAddConnections(
 	var bestImprovement float64
 	var mu sync.Mutex // Mutex to protect access to bestAttempt and bestImprovement
 
-	// Serialize the initial model
-	initialModelJSON, err := bp.SerializeToJSON()
-	if err != nil {
-		fmt.Printf("Error serializing model: %v\n", err)
-		return
-	}
-
 	// Channel to distribute unique connection pairs
 	connectionCh := make(chan [2]int, maxAttempts)
 	defer close(connectionCh)
@@ -50,7 +42,7 @@ func (bp *Blueprint) TryAddConnections(
 	// Pre-generate unique connection pairs
 	go func() {
 		neuronIDs := bp.getAllNeuronIDs()
-		rand.Shuffle(len(neuronIDs), func(i, j int) { neuronIDs[i], neuronIDs[j] = neuronIDs[j], neuronIDs[i] })
+		random.Shuffle(len(neuronIDs), func(i, j int) { neuronIDs[i], neuronIDs[j] = neuronIDs[j], neuronIDs[i] })
 		for i := 0; i < len(neuronIDs); i++ {
 			for j := 0; j < len(neuronIDs); j++ {
 				if i == j {
@@ -79,7 +71,7 @@ func (bp *Blueprint) TryAddConnections(
 		attemptsPerWorker = 1
 	}
 
-	fmt.Printf("Launching %d worker(s) with up to %d attempts each.\n", numWorkers, attemptsPerWorker)
+	bp.infof("Launching %d worker(s) with up to %d attempts each.", numWorkers, attemptsPerWorker)
 
 	// WaitGroup to wait for all workers to finish
 	var wg sync.WaitGroup
@@ -98,21 +90,14 @@ func (bp *Blueprint) TryAddConnections(
 				sourceID, targetID := connPair[0], connPair[1]
 
 				// Add a new connection with a random weight
-				weight := rand.Float64()*2 - 1 // random weight between -1 and 1
+				weight := random.Float64()*2 - 1 // random weight between -1 and 1
 
-				// Create a new Blueprint from the serialized model
-				newBP := &Blueprint{}
-				err := newBP.DeserializesFromJSON(initialModelJSON)
-				if err != nil {
-					fmt.Printf("Worker %d: Error deserializing model: %v\n", workerID, err)
-					continue
-				}
-
-				// Add the connection
-				err = newBP.addConnection(sourceID, targetID, weight)
+				// Add the connection to a copy of the model
+				newBP := bp.DeepCopy()
+				err := newBP.addConnection(sourceID, targetID, weight)
 				if err != nil {
 					// Could not add connection, try again
-					fmt.Printf("Worker %d: Error adding connection (%d -> %d): %v\n", workerID, sourceID, targetID, err)
+					bp.warnf("Worker %d: Error adding connection (%d -> %d): %v", workerID, sourceID, targetID, err)
 					continue
 				}
 
@@ -131,13 +116,6 @@ func (bp *Blueprint) TryAddConnections(
 					improvement += newForgive - initialForgive
 				}
 
-				// Serialize the new model
-				newModelJSON, err := newBP.SerializeToJSON()
-				if err != nil {
-					fmt.Printf("Worker %d: Error serializing new model: %v\n", workerID, err)
-					continue
-				}
-
 				// If improvement, check if it's the best so far
 				if improvement > bestImprovement {
 					mu.Lock()
@@ -150,7 +128,7 @@ func (bp *Blueprint) TryAddConnections(
 							ExactAcc:    newExact,
 							GenerousAcc: newGenerous,
 							ForgiveAcc:  newForgive,
-							ModelJSON:   newModelJSON,
+							Model:       newBP,
 							Improvement: improvement,
 						}
 					}
@@ -165,20 +143,16 @@ func (bp *Blueprint) TryAddConnections(
 
 	// Apply the best improvement if any
 	if bestAttempt != nil && bestAttempt.Improvement > 0 {
-		// Deserialize the best model
-		err := bp.DeserializesFromJSON(bestAttempt.ModelJSON)
-		if err != nil {
-			fmt.Printf("Error deserializing best model: %v\n", err)
-			return
-		}
-
-		fmt.Printf("Added connection (%d -> %d) improved accuracy by %.6f!\n",
+		// Only the neurons differ, so the blueprint keeps its settings, Logger and statistics
+		bp.Neurons = bestAttempt.Model.Neurons
+		bp.invalidateCompiled()
+		bp.infof("Added connection (%d -> %d) improved accuracy by %.6f!",
 			bestAttempt.SourceID, bestAttempt.TargetID, bestAttempt.Improvement)
 	} else {
-		fmt.Println("No beneficial connections were found to improve the model.")
+		bp.infof("No beneficial connections were found to improve the model.")
 	}
 
-	fmt.Println("TryAddConnections phase completed.")
+	bp.infof("TryAddConnections phase completed.")
 }
 
 // pickRandomNeuronsForConnection picks two neurons to connect, ensuring we do not form loops
@@ -195,38 +169,93 @@ func (bp *Blueprint) connectionExists(sourceID, targetID int) bool {
 	if !ok {
 		return false
 	}
-	for _, conn := range targetNeuron.Connections {
-		if int(conn[0]) == sourceID {
-			return true
+	return targetNeuron.connectionIndex(sourceID) >= 0
+}
+
+// connectionIndex returns the index of the first connection from sourceID in either storage, or -1.
+func (neuron *Neuron) connectionIndex(sourceID int) int {
+	for i := 0; i < neuron.numConnections(); i++ {
+		if connSource, _ := neuron.connection(i); connSource == sourceID {
+			return i
 		}
 	}
-	return false
+	return -1
 }
 
-// addConnection adds a connection from source to target with given weight.
+// addConnection adds a connection from source to target with given weight. If the connection already
+// exists its weight is replaced instead, so an edge is never duplicated. An LSTM target gets gate weights for a new
+// connection.
 func (bp *Blueprint) addConnection(sourceID, targetID int, weight float64) error {
 	targetNeuron, ok := bp.Neurons[targetID]
 	if !ok {
 		return fmt.Errorf("target neuron %d does not exist", targetID)
 	}
+	if targetNeuron.connectionIndex(sourceID) >= 0 {
+		return bp.setConnectionWeight(sourceID, targetID, weight)
+	}
 
 	// Add the connection
 	targetNeuron.Connections = append(targetNeuron.Connections, []float64{float64(sourceID), weight})
+	bp.alignLSTMGates(targetNeuron)
+	bp.invalidateCompiled()
 	return nil
 }
 
-// removeConnection removes a connection from source to target.
+// GetWeight returns the weight of the connection from sourceID to targetID, which is stored on the target neuron.
+// It reports false when the target neuron does not exist or does not read from sourceID, including when only the
+// reverse connection exists. With duplicate connections the first one's weight is returned.
+func (bp *Blueprint) GetWeight(sourceID, targetID int) (float64, bool) {
+	targetNeuron, ok := bp.Neurons[targetID]
+	if !ok {
+		return 0, false
+	}
+	i := targetNeuron.connectionIndex(sourceID)
+	if i < 0 {
+		return 0, false
+	}
+	_, weight := targetNeuron.connection(i)
+	return weight, true
+}
+
+// SetWeight replaces the weight of the existing connection from sourceID to targetID. It never creates a
+// connection: an error is returned when the target neuron does not exist or does not read from sourceID.
+// Weights stored as float32 in low-precision mode are rounded to float32.
+func (bp *Blueprint) SetWeight(sourceID, targetID int, weight float64) error {
+	return bp.setConnectionWeight(sourceID, targetID, weight)
+}
+
+// setConnectionWeight replaces the weight of the existing connection from source to target, the first
+// one if there are duplicates. It returns an error if there is no such connection.
+func (bp *Blueprint) setConnectionWeight(sourceID, targetID int, weight float64) error {
+	targetNeuron, ok := bp.Neurons[targetID]
+	if !ok {
+		return fmt.Errorf("target neuron %d does not exist", targetID)
+	}
+	i := targetNeuron.connectionIndex(sourceID)
+	if i < 0 {
+		return fmt.Errorf("no connection from neuron %d to neuron %d", sourceID, targetID)
+	}
+	targetNeuron.setConnectionWeight(i, weight)
+	bp.invalidateCompiled()
+	return nil
+}
+
+// removeConnection removes every connection from source to target, in either storage precision, together with
+// the matching LSTM gate weights.
 func (bp *Blueprint) removeConnection(sourceID, targetID int) {
 	targetNeuron, ok := bp.Neurons[targetID]
 	if !ok {
 		return
 	}
 
-	newConnections := [][]float64{}
-	for _, conn := range targetNeuron.Connections {
-		if int(conn[0]) != sourceID {
-			newConnections = append(newConnections, conn)
+	kept := make([]int, 0, targetNeuron.numConnections())
+	for i := 0; i < targetNeuron.numConnections(); i++ {
+		if connSource, _ := targetNeuron.connection(i); connSource != sourceID {
+			kept = append(kept, i)
 		}
 	}
-	targetNeuron.Connections = newConnections
+	if len(kept) < targetNeuron.numConnections() {
+		targetNeuron.keepConnections(kept)
+	}
+	bp.invalidateCompiled()
 }
@@ -0,0 +1,107 @@
+package blueprint
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestAddConnectionTwiceKeepsOneEdgeWithLatestWeight(t *testing.T) {
+	bp := NewBlueprint()
+	bp.AddInputNeurons([]int{1})
+	bp.AddOutputNeurons([]int{2}, "linear")
+
+	if err := bp.addConnection(1, 2, 0.5); err != nil {
+		t.Fatal(err)
+	}
+	if err := bp.addConnection(1, 2, -0.75); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := bp.Neurons[2].Connections; !slices.EqualFunc(got, [][]float64{{1, -0.75}}, slices.Equal) {
+		t.Errorf("got connections %v, want a single edge from 1 with weight -0.75", got)
+	}
+	if weight := bp.getConnectionWeight(1, 2); weight != -0.75 {
+		t.Errorf("getConnectionWeight returned %v, want -0.75", weight)
+	}
+}
+
+func TestRemoveConnectionLowPrecisionAndLSTM(t *testing.T) {
+	bp := NewBlueprint()
+	bp.AddInputNeurons([]int{1, 2})
+	bp.Neurons[3] = &Neuron{
+		ID: 3, Type: "lstm", Activation: "tanh",
+		Connections: [][]float64{{1, 0.5}, {2, 0.25}},
+		GateWeights: map[string][]float64{
+			"input": {1, 2}, "forget": {3, 4}, "output": {5, 6}, "cell": {7, 8},
+		},
+	}
+	bp.AddOutputNodes([]int{3})
+	if err := bp.ConvertToFloat32Storage(); err != nil {
+		t.Fatal(err)
+	}
+
+	bp.removeConnection(1, 3)
+
+	neuron := bp.Neurons[3]
+	if neuron.numConnections() != 1 {
+		t.Fatalf("got %d connections after removing one of two", neuron.numConnections())
+	}
+	if source, weight := neuron.connection(0); source != 2 || weight != 0.25 {
+		t.Errorf("kept connection from %d with weight %v, want from 2 with weight 0.25", source, weight)
+	}
+	for gate, weights := range map[string][]float64{"input": {2}, "forget": {4}, "output": {6}, "cell": {8}} {
+		if !slices.Equal(neuron.GateWeights[gate], weights) {
+			t.Errorf("%s gate weights are %v, want %v", gate, neuron.GateWeights[gate], weights)
+		}
+	}
+}
+
+func TestGetAndSetWeight(t *testing.T) {
+	bp := NewBlueprint()
+	bp.AddInputNeurons([]int{1})
+	bp.AddOutputNeurons([]int{2}, "linear")
+	bp.Neurons[2].Connections = [][]float64{{1, 0.5}}
+
+	// Existing edge
+	if w, ok := bp.GetWeight(1, 2); !ok || w != 0.5 {
+		t.Errorf("GetWeight(1, 2) = %v, %v, want 0.5, true", w, ok)
+	}
+	if err := bp.SetWeight(1, 2, -0.75); err != nil {
+		t.Fatalf("SetWeight(1, 2) failed: %v", err)
+	}
+	if w, ok := bp.GetWeight(1, 2); !ok || w != -0.75 {
+		t.Errorf("GetWeight(1, 2) after SetWeight = %v, %v, want -0.75, true", w, ok)
+	}
+
+	// Reversed edge: neuron 2 reads from 1, not the other way round
+	if w, ok := bp.GetWeight(2, 1); ok {
+		t.Errorf("GetWeight(2, 1) = %v, true for the reverse of an existing edge, want false", w)
+	}
+	if err := bp.SetWeight(2, 1, 1); err == nil {
+		t.Error("SetWeight(2, 1) on the reverse of an existing edge succeeded")
+	}
+
+	// Nonexistent edges and neurons
+	for _, edge := range [][2]int{{2, 2}, {1, 9}, {9, 2}} {
+		if _, ok := bp.GetWeight(edge[0], edge[1]); ok {
+			t.Errorf("GetWeight(%d, %d) reported a nonexistent edge", edge[0], edge[1])
+		}
+		if err := bp.SetWeight(edge[0], edge[1], 1); err == nil {
+			t.Errorf("SetWeight(%d, %d) on a nonexistent edge succeeded", edge[0], edge[1])
+		}
+	}
+	if got := bp.Neurons[2].Connections; !slices.EqualFunc(got, [][]float64{{1, -0.75}}, slices.Equal) {
+		t.Errorf("connections = %v, want only the edited edge", got)
+	}
+
+	// Low-precision storage rounds to float32
+	if err := bp.ConvertToFloat32Storage(); err != nil {
+		t.Fatal(err)
+	}
+	if err := bp.SetWeight(1, 2, 0.1); err != nil {
+		t.Fatalf("SetWeight in low precision failed: %v", err)
+	}
+	if w, ok := bp.GetWeight(1, 2); !ok || w != float64(float32(0.1)) {
+		t.Errorf("GetWeight(1, 2) in low precision = %v, %v, want %v, true", w, ok, float64(float32(0.1)))
+	}
+}
@@ -0,0 +1,57 @@
+package blueprint
+
+import "math"
+
+// kernelShape returns the rows and columns of one of a CNN neuron's kernels: a single row in 1D mode, otherwise
+// KernelShape, or a square when KernelShape is unset. It returns false when the kernel's length does not match.
+func (neuron *Neuron) kernelShape(kernel []float64, is2D bool) ([2]int, bool) {
+	if !is2D {
+		return [2]int{1, len(kernel)}, len(kernel) > 0
+	}
+	shape := neuron.KernelShape
+	if shape[0] <= 0 || shape[1] <= 0 {
+		side := int(math.Round(math.Sqrt(float64(len(kernel)))))
+		shape = [2]int{side, side}
+	}
+	return shape, shape[0] > 0 && shape[0]*shape[1] == len(kernel)
+}
+
+// convolve slides a kernel over an input, both stored row by row with the given shapes, moving stride positions
+// along each axis, and returns bias plus the weighted sum at every position, row by row. Without padding only positions
+// where the kernel lies entirely inside the input are used, so a kernel larger than the input yields nothing.
+// With same padding the input is surrounded by zeros, split as evenly as possible with the extra row or column
+// after it, so every axis has ceil(size / stride) positions.
+func convolve(input []float64, inputShape [2]int, kernel []float64, kernelShape [2]int, bias float64, stride int, same bool) []float64 {
+	var outShape, padBefore [2]int
+	for axis := 0; axis < 2; axis++ {
+		size, kernelSize := inputShape[axis], kernelShape[axis]
+		if same {
+			outShape[axis] = (size + stride - 1) / stride
+			padBefore[axis] = max((outShape[axis]-1)*stride+kernelSize-size, 0) / 2
+		} else if size >= kernelSize {
+			outShape[axis] = (size-kernelSize)/stride + 1
+		}
+	}
+
+	outputs := make([]float64, 0, outShape[0]*outShape[1])
+	for outRow := 0; outRow < outShape[0]; outRow++ {
+		for outCol := 0; outCol < outShape[1]; outCol++ {
+			sum := bias
+			for kr := 0; kr < kernelShape[0]; kr++ {
+				row := outRow*stride + kr - padBefore[0]
+				if row < 0 || row >= inputShape[0] {
+					continue
+				}
+				for kc := 0; kc < kernelShape[1]; kc++ {
+					col := outCol*stride + kc - padBefore[1]
+					if col < 0 || col >= inputShape[1] {
+						continue
+					}
+					sum += input[row*inputShape[1]+col] * kernel[kr*kernelShape[1]+kc]
+				}
+			}
+			outputs = append(outputs, sum)
+		}
+	}
+	return outputs
+}
@@ -0,0 +1,57 @@
+package blueprint
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestConvolve2D(t *testing.T) {
+	input := []float64{1, 2, 3, 4, 5, 6, 7, 8, 9}
+	kernel := []float64{1, 2, 3, 4}
+	for _, c := range []struct {
+		name   string
+		stride int
+		same   bool
+		want   []float64
+	}{
+		{"valid", 1, false, []float64{37, 47, 67, 77}},
+		{"valid stride 2", 2, false, []float64{37}},
+		// Same padding adds the zero row and column after the input
+		{"same", 1, true, []float64{37, 47, 21, 67, 77, 33, 23, 26, 9}},
+	} {
+		if got := convolve(input, [2]int{3, 3}, kernel, [2]int{2, 2}, 0, c.stride, c.same); !slices.Equal(got, c.want) {
+			t.Errorf("%s: convolve = %v, want %v", c.name, got, c.want)
+		}
+	}
+}
+
+func TestCNNNeuronConvolvesInputImage(t *testing.T) {
+	bp := NewBlueprint()
+	inputs := map[int]float64{}
+	connections := [][]float64{}
+	for id := 1; id <= 9; id++ {
+		inputs[id] = float64(id)
+		connections = append(connections, []float64{float64(id), 1})
+	}
+	bp.AddInputNeurons([]int{1, 2, 3, 4, 5, 6, 7, 8, 9})
+	bp.Neurons[10] = &Neuron{
+		ID: 10, Type: "cnn", Activation: "linear", Connections: connections,
+		Kernels: [][]float64{{1, 2, 3, 4}}, InputShape: [2]int{3, 3}, KernelShape: [2]int{2, 2},
+	}
+
+	bp.Forward(inputs, 1)
+
+	// The mean of the valid convolution outputs 37, 47, 67 and 77
+	if got := bp.Neurons[10].Value; got != 57 {
+		t.Errorf("2D CNN neuron value = %v, want 57", got)
+	}
+
+	// Without an InputShape the same kernel slides over the inputs as one row
+	bp.Neurons[10].InputShape = [2]int{}
+	bp.Neurons[10].KernelShape = [2]int{}
+	bp.Neurons[10].Kernels = [][]float64{{1, 1}}
+	bp.Forward(inputs, 1)
+	if got := bp.Neurons[10].Value; got != 10 { // The mean of 3, 5, ..., 17
+		t.Errorf("1D CNN neuron value = %v, want 10", got)
+	}
+}
@@ -0,0 +1,55 @@
+package blueprint
+
+import (
+	"math"
+	"sort"
+)
+
+// minProbability keeps the difficulty finite when the expected class gets no probability at all.
+const minProbability = 1e-12
+
+// SessionDifficulty scores how hard each session is for the current model as the cross-entropy
+// loss of its expected class, -log(p), where p is the softmaxed output of the expected class.
+// A confidently correct session scores close to 0 and the score grows as the model assigns
+// less probability to the right answer.
+func (bp *Blueprint) SessionDifficulty(sessions []Session) []float64 {
+	scores := make([]float64, len(sessions))
+	for i, session := range sessions {
+		bp.RunNetwork(session.InputVariables, session.Timesteps)
+		outputs := bp.GetOutputs()
+
+		probability := outputs[argmaxMap(session.ExpectedOutput)]
+		if probability < minProbability || math.IsNaN(probability) {
+			probability = minProbability
+		}
+		scores[i] = -math.Log(probability)
+	}
+	return scores
+}
+
+// OrderByDifficulty returns a copy of sessions sorted by their difficulty scores, easiest first when
+// easyFirst is set and hardest first otherwise. Sessions with equal scores keep their relative order.
+// If scores does not have one entry per session the sessions are returned in their original order.
+func OrderByDifficulty(sessions []Session, scores []float64, easyFirst bool) []Session {
+	ordered := make([]Session, len(sessions))
+	if len(scores) != len(sessions) {
+		copy(ordered, sessions)
+		return ordered
+	}
+
+	indices := make([]int, len(sessions))
+	for i := range indices {
+		indices[i] = i
+	}
+	sort.SliceStable(indices, func(a, b int) bool {
+		if easyFirst {
+			return scores[indices[a]] < scores[indices[b]]
+		}
+		return scores[indices[a]] > scores[indices[b]]
+	})
+
+	for i, idx := range indices {
+		ordered[i] = sessions[idx]
+	}
+	return ordered
+}
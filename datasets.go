@@ -0,0 +1,224 @@
+package blueprint
+
+import (
+	"encoding/binary"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// LoadSessionsFromCSV reads one session per row of a CSV file. The value in each column of inputCols becomes an
+// input and the value in each column of outputCols an expected output. Columns are counted from 0, and neuron IDs
+// follow the order of the lists, counted from 1 as NewDenseMLP numbers its layers: inputCols[k] feeds input neuron
+// k+1 and outputCols[k] is the expected output of neuron len(inputCols)+k+1. A first row whose listed columns are not all
+// numbers is treated as a header and skipped. Empty values, non-numeric values and rows too short for the listed
+// columns are errors that name the line and column.
+func LoadSessionsFromCSV(path string, inputCols, outputCols []int, timesteps int) ([]Session, error) {
+	if len(inputCols) == 0 || len(outputCols) == 0 {
+		return nil, fmt.Errorf("at least one input and one output column are required")
+	}
+	for _, col := range append(append([]int{}, inputCols...), outputCols...) {
+		if col < 0 {
+			return nil, fmt.Errorf("invalid column index %d", col)
+		}
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open CSV file '%s': %w", path, err)
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1 // Row lengths are checked against the listed columns instead
+	reader.TrimLeadingSpace = true
+
+	sessions := []Session{}
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("malformed CSV in '%s': %w", path, err)
+		}
+		if len(record) == 1 && strings.TrimSpace(record[0]) == "" {
+			continue // Blank line
+		}
+
+		inputs, inputErr := csvValues(record, inputCols, 1)
+		outputs, outputErr := csvValues(record, outputCols, len(inputCols)+1)
+		if inputErr != nil || outputErr != nil {
+			if line == 1 && len(sessions) == 0 {
+				continue // Header row
+			}
+			return nil, fmt.Errorf("line %d of '%s': %w", line, path, errors.Join(inputErr, outputErr))
+		}
+		sessions = append(sessions, Session{InputVariables: inputs, ExpectedOutput: outputs, Timesteps: timesteps})
+	}
+
+	if len(sessions) == 0 {
+		return nil, fmt.Errorf("no sessions found in '%s'", path)
+	}
+	return sessions, nil
+}
+
+// csvValues parses the listed columns of a CSV record, keyed by neuron ID: cols[k] is keyed by firstID+k.
+func csvValues(record []string, cols []int, firstID int) (map[int]float64, error) {
+	values := make(map[int]float64, len(cols))
+	for k, col := range cols {
+		if col >= len(record) {
+			return nil, fmt.Errorf("column %d is missing, the row has %d columns", col, len(record))
+		}
+		field := strings.TrimSpace(record[col])
+		if field == "" {
+			return nil, fmt.Errorf("column %d is empty", col)
+		}
+		value, err := strconv.ParseFloat(field, 64)
+		if err != nil {
+			return nil, fmt.Errorf("column %d is not a number: %q", col, field)
+		}
+		values[firstID+k] = value
+	}
+	return values, nil
+}
+
+// LoadSessionsFromJSON reads a JSON array of sessions, each an object with "InputVariables" and "ExpectedOutput"
+// maps from neuron ID to value and an optional "Timesteps", the format encoding/json produces for []Session.
+// Sessions without inputs or expected outputs are errors.
+func LoadSessionsFromJSON(path string) ([]Session, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JSON file '%s': %w", path, err)
+	}
+
+	var sessions []Session
+	if err := json.Unmarshal(data, &sessions); err != nil {
+		return nil, fmt.Errorf("failed to parse sessions in '%s': %w", path, err)
+	}
+	for i, session := range sessions {
+		if len(session.InputVariables) == 0 || len(session.ExpectedOutput) == 0 {
+			return nil, fmt.Errorf("session %d in '%s' has no inputs or no expected outputs", i, path)
+		}
+	}
+	return sessions, nil
+}
+
+const (
+	// idxUnsignedByte is the IDX data type code of unsigned byte data, the only type MNIST-style files use.
+	idxUnsignedByte = 0x08
+	// idxMaxPixel is the largest unsigned byte pixel value, used to normalize pixels to [0, 1].
+	idxMaxPixel = 255.0
+)
+
+// LoadIDXImages reads an uncompressed IDX image file, such as the MNIST train-images-idx3-ubyte produced by
+// UnzipFile, and returns every image flattened row by row with its raw pixel values from 0 to 255.
+func LoadIDXImages(path string) ([][]float64, error) {
+	dims, data, err := readIDX(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(dims) < 2 {
+		return nil, fmt.Errorf("IDX file '%s' has %d dimensions, images need at least 2", path, len(dims))
+	}
+
+	size := 1
+	for _, d := range dims[1:] {
+		size *= d
+	}
+	images := make([][]float64, dims[0])
+	for i := range images {
+		image := make([]float64, size)
+		for p, pixel := range data[i*size : (i+1)*size] {
+			image[p] = float64(pixel)
+		}
+		images[i] = image
+	}
+	return images, nil
+}
+
+// LoadIDXLabels reads an uncompressed IDX label file, such as the MNIST train-labels-idx1-ubyte produced by
+// UnzipFile, and returns its labels.
+func LoadIDXLabels(path string) ([]int, error) {
+	dims, data, err := readIDX(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(dims) != 1 {
+		return nil, fmt.Errorf("IDX file '%s' has %d dimensions, labels need 1", path, len(dims))
+	}
+
+	labels := make([]int, len(data))
+	for i, label := range data {
+		labels[i] = int(label)
+	}
+	return labels, nil
+}
+
+// readIDX reads an IDX file of unsigned bytes and returns its dimensions and data, checking that the data
+// length matches the dimensions.
+func readIDX(path string) ([]int, []byte, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read IDX file '%s': %w", path, err)
+	}
+	if len(raw) < 4 || raw[0] != 0 || raw[1] != 0 {
+		return nil, nil, fmt.Errorf("'%s' is not an IDX file", path)
+	}
+	if raw[2] != idxUnsignedByte {
+		return nil, nil, fmt.Errorf("IDX file '%s' has data type 0x%02x, only unsigned bytes (0x08) are supported", path, raw[2])
+	}
+
+	numDims := int(raw[3])
+	header := 4 + 4*numDims
+	if numDims == 0 || len(raw) < header {
+		return nil, nil, fmt.Errorf("IDX file '%s' has a truncated header", path)
+	}
+	dims := make([]int, numDims)
+	size := 1
+	for i := range dims {
+		dims[i] = int(binary.BigEndian.Uint32(raw[4+4*i:]))
+		size *= dims[i]
+	}
+	if len(raw)-header != size {
+		return nil, nil, fmt.Errorf("IDX file '%s' holds %d bytes of data, its dimensions %v need %d", path, len(raw)-header, dims, size)
+	}
+	return dims, raw[header:], nil
+}
+
+// SessionsFromImages builds one single-timestep session per image. Pixel p, scaled from [0, 255] to [0, 1], feeds
+// input neuron p+1, and the expected output is a one-hot vector over output neurons len(image)+1 to
+// len(image)+numClasses, the IDs NewDenseMLP([]int{len(image), numClasses}, ...) gives its layers. An error is
+// returned when the numbers of images and labels differ or a label is outside [0, numClasses).
+func SessionsFromImages(images [][]float64, labels []int, numClasses int) ([]Session, error) {
+	if len(images) != len(labels) {
+		return nil, fmt.Errorf("got %d images but %d labels", len(images), len(labels))
+	}
+
+	sessions := make([]Session, 0, len(images))
+	for i, image := range images {
+		if labels[i] < 0 || labels[i] >= numClasses {
+			return nil, fmt.Errorf("label %d of image %d is outside [0, %d)", labels[i], i, numClasses)
+		}
+
+		session := Session{
+			InputVariables: make(map[int]float64, len(image)),
+			ExpectedOutput: make(map[int]float64, numClasses),
+			Timesteps:      1,
+		}
+		for p, pixel := range image {
+			session.InputVariables[p+1] = pixel / idxMaxPixel
+		}
+		for c := 0; c < numClasses; c++ {
+			session.ExpectedOutput[len(image)+1+c] = 0
+		}
+		session.ExpectedOutput[len(image)+1+labels[i]] = 1
+		sessions = append(sessions, session)
+	}
+	return sessions, nil
+}
@@ -0,0 +1,105 @@
+package blueprint
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoadSessionsFromCSVFixture(t *testing.T) {
+	sessions, err := LoadSessionsFromCSV(filepath.Join("testdata", "sessions.csv"), []int{0, 2}, []int{3}, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Session{
+		{InputVariables: map[int]float64{1: 0.5, 2: 1}, ExpectedOutput: map[int]float64{3: 0}, Timesteps: 2},
+		{InputVariables: map[int]float64{1: -2, 2: 3.25}, ExpectedOutput: map[int]float64{3: 1}, Timesteps: 2},
+		{InputVariables: map[int]float64{1: 4, 2: 0}, ExpectedOutput: map[int]float64{3: 1}, Timesteps: 2},
+	}
+	if !reflect.DeepEqual(sessions, want) {
+		t.Errorf("got sessions %v, want %v", sessions, want)
+	}
+}
+
+func TestLoadSessionsFromCSVErrors(t *testing.T) {
+	for name, tc := range map[string]struct {
+		data string
+		want string
+	}{
+		"missing value": {"1,2\n3,\n", "line 2"},
+		"not a number":  {"1,2\nx,4\n", "not a number"},
+		"short row":     {"1,2\n3\n", "column 1 is missing"},
+		"only a header": {"a,b\n", "no sessions"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "data.csv")
+			if err := os.WriteFile(path, []byte(tc.data), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			_, err := LoadSessionsFromCSV(path, []int{0}, []int{1}, 1)
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("got error %v, want one mentioning %q", err, tc.want)
+			}
+		})
+	}
+}
+
+// writeIDX writes an unsigned byte IDX file with the given dimensions and data into dir.
+func writeIDX(t *testing.T, dir, name string, dims []uint32, data []byte) string {
+	t.Helper()
+	buf := []byte{0, 0, idxUnsignedByte, byte(len(dims))}
+	for _, d := range dims {
+		buf = binary.BigEndian.AppendUint32(buf, d)
+	}
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, append(buf, data...), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadIDXImagesAndLabels(t *testing.T) {
+	dir := t.TempDir()
+	imagePath := writeIDX(t, dir, "images", []uint32{2, 2, 2}, []byte{0, 255, 51, 102, 255, 0, 0, 255})
+	labelPath := writeIDX(t, dir, "labels", []uint32{2}, []byte{1, 0})
+
+	images, err := LoadIDXImages(imagePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := [][]float64{{0, 255, 51, 102}, {255, 0, 0, 255}}; !reflect.DeepEqual(images, want) {
+		t.Errorf("got images %v, want %v", images, want)
+	}
+	labels, err := LoadIDXLabels(labelPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{1, 0}; !reflect.DeepEqual(labels, want) {
+		t.Errorf("got labels %v, want %v", labels, want)
+	}
+
+	sessions, err := SessionsFromImages(images, labels, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Session{
+		{InputVariables: map[int]float64{1: 0, 2: 1, 3: 0.2, 4: 0.4}, ExpectedOutput: map[int]float64{5: 0, 6: 1}, Timesteps: 1},
+		{InputVariables: map[int]float64{1: 1, 2: 0, 3: 0, 4: 1}, ExpectedOutput: map[int]float64{5: 1, 6: 0}, Timesteps: 1},
+	}
+	if !reflect.DeepEqual(sessions, want) {
+		t.Errorf("got sessions %v, want %v", sessions, want)
+	}
+
+	if _, err := SessionsFromImages(images, []int{1, 2}, 2); err == nil {
+		t.Error("expected an error for a label outside the classes")
+	}
+	if _, err := SessionsFromImages(images, []int{1}, 2); err == nil {
+		t.Error("expected an error for a missing label")
+	}
+	if _, err := LoadIDXImages(writeIDX(t, dir, "truncated", []uint32{2, 2, 2}, []byte{1, 2, 3})); err == nil {
+		t.Error("expected an error for truncated image data")
+	}
+}
@@ -0,0 +1,119 @@
+package blueprint
+
+// DeepCopy returns an independent copy of the blueprint built field by field instead of through JSON, several
+// times faster than Clone. It keeps what Clone keeps: every serialized field, neuron values and LSTM cell states
+// included, the activation map, training mode, the Logger and the neurons frozen by NAS. Caches, bandits, score
+// history and optimizer state start empty, and Debug is off. Quantum neurons are copied as well.
+func (bp *Blueprint) DeepCopy() *Blueprint {
+	newBP := &Blueprint{
+		Neurons:             make(map[int]*Neuron, len(bp.Neurons)),
+		InputNodes:          copyInts(bp.InputNodes),
+		OutputNodes:         copyInts(bp.OutputNodes),
+		ScalarActivationMap: bp.ScalarActivationMap,
+		Logger:              bp.Logger,
+		TrainingMode:        bp.TrainingMode,
+		LowPrecision:        bp.LowPrecision,
+		StateClamp:          bp.StateClamp,
+		DecileStep:          bp.DecileStep,
+		WeightInit:          bp.WeightInit,
+		frozenNeurons:       bp.frozenNeurons,
+	}
+	if bp.LayerLearningRates != nil {
+		newBP.LayerLearningRates = append([]float64{}, bp.LayerLearningRates...)
+	}
+	for id, neuron := range bp.Neurons {
+		newBP.Neurons[id] = neuron.copy()
+	}
+	if bp.QuantumNeurons != nil {
+		newBP.QuantumNeurons = make(map[int]*QuantumNeuron, len(bp.QuantumNeurons))
+		for id, neuron := range bp.QuantumNeurons {
+			newBP.QuantumNeurons[id] = neuron.copy()
+		}
+	}
+	if newBP.ScalarActivationMap == nil {
+		newBP.InitializeActivationFunctions()
+	}
+	return newBP
+}
+
+// copy returns a copy of the neuron that shares no slices, maps or parameters with it.
+func (neuron *Neuron) copy() *Neuron {
+	c := *neuron
+	c.Connections = copyFloatMatrix(neuron.Connections)
+	c.AttentionWeights = copyFloats(neuron.AttentionWeights)
+	c.Kernels = copyFloatMatrix(neuron.Kernels)
+	c.NeighborhoodIDs = copyInts(neuron.NeighborhoodIDs)
+	c.NCAState = copyFloats(neuron.NCAState)
+	if neuron.BatchNormParams != nil {
+		params := *neuron.BatchNormParams
+		c.BatchNormParams = &params
+	}
+	if neuron.GateWeights != nil {
+		c.GateWeights = make(map[string][]float64, len(neuron.GateWeights))
+		for gate, weights := range neuron.GateWeights {
+			c.GateWeights[gate] = copyFloats(weights)
+		}
+	}
+	if neuron.Connections32 != nil {
+		c.Connections32 = append([][2]float32{}, neuron.Connections32...)
+	}
+	return &c
+}
+
+// copy returns a copy of the quantum neuron that shares no slices with it.
+func (neuron *QuantumNeuron) copy() *QuantumNeuron {
+	c := *neuron
+	if neuron.QuantumGates != nil {
+		c.QuantumGates = make([]QuantumGate, len(neuron.QuantumGates))
+		for i, gate := range neuron.QuantumGates {
+			c.QuantumGates[i] = gate
+			c.QuantumGates[i].Matrix = copyComplexMatrix(gate.Matrix)
+		}
+	}
+	if neuron.Entanglements != nil {
+		c.Entanglements = append([]EntanglementInfo{}, neuron.Entanglements...)
+	}
+	if neuron.Superposition != nil {
+		c.Superposition = append([]complex128{}, neuron.Superposition...)
+	}
+	c.Connections = copyComplexMatrix(neuron.Connections)
+	return &c
+}
+
+func copyInts(s []int) []int {
+	if s == nil {
+		return nil
+	}
+	return append([]int{}, s...)
+}
+
+func copyFloats(s []float64) []float64 {
+	if s == nil {
+		return nil
+	}
+	return append([]float64{}, s...)
+}
+
+func copyFloatMatrix(m [][]float64) [][]float64 {
+	if m == nil {
+		return nil
+	}
+	c := make([][]float64, len(m))
+	for i, row := range m {
+		c[i] = copyFloats(row)
+	}
+	return c
+}
+
+func copyComplexMatrix(m [][]complex128) [][]complex128 {
+	if m == nil {
+		return nil
+	}
+	c := make([][]complex128, len(m))
+	for i, row := range m {
+		if row != nil {
+			c[i] = append([]complex128{}, row...)
+		}
+	}
+	return c
+}
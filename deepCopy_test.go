@@ -0,0 +1,68 @@
+package blueprint
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDeepCopyIsIndependentAndIdentical(t *testing.T) {
+	randomSource.Seed(1)
+	bp := NewBlueprint()
+	bp.AddInputNeurons([]int{1, 2})
+	bp.Neurons[3] = &Neuron{ID: 3, Type: "lstm", Activation: "tanh", Value: 0.4, CellState: 0.25,
+		Connections: [][]float64{{1, 0.5}, {2, -0.25}}}
+	bp.initializeLSTMWeights(bp.Neurons[3])
+	bp.Neurons[4] = &Neuron{ID: 4, Type: "batch_norm", Activation: "linear", Connections: [][]float64{{3, 1}},
+		BatchNormParams: &BatchNormParams{Gamma: 1.5, Beta: 0.1, Mean: 0.2, Var: 0.9, Momentum: defaultBatchNormMomentum}}
+	bp.Neurons[5] = &Neuron{ID: 5, Type: "cnn", Activation: "relu", Connections: [][]float64{{1, 1}, {2, 1}},
+		Kernels: [][]float64{{0.2, 0.5}}}
+	bp.AddOutputNeurons([]int{6}, "linear")
+	bp.Neurons[6].Connections = [][]float64{{4, 1}, {5, -1}}
+	bp.LayerLearningRates = []float64{1, 0.5}
+
+	c := bp.DeepCopy()
+	if c.Hash() != bp.Hash() {
+		t.Fatal("copy hashes differently from the original")
+	}
+	if !reflect.DeepEqual(c.Neurons, bp.Neurons) {
+		t.Fatal("copy's neurons differ from the original's")
+	}
+	if !reflect.DeepEqual(c.InputNodes, bp.InputNodes) || !reflect.DeepEqual(c.OutputNodes, bp.OutputNodes) {
+		t.Fatal("copy's input or output nodes differ from the original's")
+	}
+	if got := c.Neurons[3]; got.CellState != 0.25 || got.Value != 0.4 {
+		t.Errorf("copy's LSTM has value %v and cell state %v, want 0.4 and 0.25", got.Value, got.CellState)
+	}
+
+	original := bp.Hash()
+	c.Neurons[3].Connections[0][1] = 9
+	c.Neurons[3].GateWeights["forget"][1] = 9
+	c.Neurons[4].BatchNormParams.Mean = 9
+	c.Neurons[5].Kernels[0][0] = 9
+	c.InputNodes[0] = 9
+	c.LayerLearningRates[0] = 9
+	c.Neurons[3].CellState = 9
+	if bp.Hash() != original || bp.InputNodes[0] != 1 || bp.LayerLearningRates[0] != 1 || bp.Neurons[3].CellState != 0.25 {
+		t.Error("changing the copy changed the original")
+	}
+}
+
+func BenchmarkDeepCopy(b *testing.B) {
+	randomSource.Seed(1)
+	bp := NewDenseMLP([]int{64, 128, 128, 10}, "relu")
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		bp.DeepCopy()
+	}
+}
+
+func BenchmarkClone(b *testing.B) {
+	randomSource.Seed(1)
+	bp := NewDenseMLP([]int{64, 128, 128, 10}, "relu")
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		bp.Clone()
+	}
+}
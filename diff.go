@@ -0,0 +1,130 @@
+package blueprint
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// diffWeightTolerance is the smallest weight change Diff reports.
+const diffWeightTolerance = 1e-9
+
+// ModelDiff lists the structural and weight changes between two blueprints, see Diff.
+type ModelDiff struct {
+	AddedNeurons       []int              `json:"added_neurons"`
+	RemovedNeurons     []int              `json:"removed_neurons"`
+	AddedConnections   []ConnectionChange `json:"added_connections"`   // Weight holds the new weight
+	RemovedConnections []ConnectionChange `json:"removed_connections"` // Weight holds the old weight
+	WeightChanges      []ConnectionChange `json:"weight_changes"`      // Weight holds the new weight, Delta the change
+}
+
+// ConnectionChange is a connection reported by Diff.
+type ConnectionChange struct {
+	Source int     `json:"source"`
+	Target int     `json:"target"`
+	Weight float64 `json:"weight"`
+	Delta  float64 `json:"delta,omitempty"`
+}
+
+// Diff compares the blueprint, taken as the parent, with other, taken as the child, and returns what the child
+// adds, removes and reweights. Connections of added and removed neurons are listed with them, and weight changes
+// of 1e-9 or less are ignored. Duplicate connections between the same pair of neurons are matched in order.
+// Everything is sorted by neuron ID. Quantum neurons are not compared.
+func (bp *Blueprint) Diff(other *Blueprint) ModelDiff {
+	diff := ModelDiff{
+		AddedNeurons:       []int{},
+		RemovedNeurons:     []int{},
+		AddedConnections:   []ConnectionChange{},
+		RemovedConnections: []ConnectionChange{},
+		WeightChanges:      []ConnectionChange{},
+	}
+	for _, id := range bp.getAllNeuronIDs() {
+		if _, exists := other.Neurons[id]; !exists {
+			diff.RemovedNeurons = append(diff.RemovedNeurons, id)
+		}
+	}
+	for _, id := range other.getAllNeuronIDs() {
+		if _, exists := bp.Neurons[id]; !exists {
+			diff.AddedNeurons = append(diff.AddedNeurons, id)
+		}
+	}
+
+	oldWeights, newWeights := bp.connectionWeights(), other.connectionWeights()
+	for edge, weights := range oldWeights {
+		for i, weight := range weights {
+			change := ConnectionChange{Source: edge[0], Target: edge[1], Weight: weight}
+			if i >= len(newWeights[edge]) {
+				diff.RemovedConnections = append(diff.RemovedConnections, change)
+				continue
+			}
+			change.Weight = newWeights[edge][i]
+			change.Delta = change.Weight - weight
+			if math.Abs(change.Delta) > diffWeightTolerance {
+				diff.WeightChanges = append(diff.WeightChanges, change)
+			}
+		}
+	}
+	for edge, weights := range newWeights {
+		for _, weight := range weights[min(len(oldWeights[edge]), len(weights)):] {
+			diff.AddedConnections = append(diff.AddedConnections, ConnectionChange{Source: edge[0], Target: edge[1], Weight: weight})
+		}
+	}
+	sortConnectionChanges(diff.AddedConnections)
+	sortConnectionChanges(diff.RemovedConnections)
+	sortConnectionChanges(diff.WeightChanges)
+	return diff
+}
+
+// Empty reports whether the diff found no changes.
+func (d ModelDiff) Empty() bool {
+	return len(d.AddedNeurons) == 0 && len(d.RemovedNeurons) == 0 && len(d.AddedConnections) == 0 &&
+		len(d.RemovedConnections) == 0 && len(d.WeightChanges) == 0
+}
+
+// String lists the changes one per line, prefixed with + for additions, - for removals and ~ for weight changes.
+func (d ModelDiff) String() string {
+	if d.Empty() {
+		return "no differences\n"
+	}
+	var b strings.Builder
+	for _, id := range d.AddedNeurons {
+		fmt.Fprintf(&b, "+ neuron %d\n", id)
+	}
+	for _, id := range d.RemovedNeurons {
+		fmt.Fprintf(&b, "- neuron %d\n", id)
+	}
+	for _, c := range d.AddedConnections {
+		fmt.Fprintf(&b, "+ connection %d -> %d (%.4g)\n", c.Source, c.Target, c.Weight)
+	}
+	for _, c := range d.RemovedConnections {
+		fmt.Fprintf(&b, "- connection %d -> %d (%.4g)\n", c.Source, c.Target, c.Weight)
+	}
+	for _, c := range d.WeightChanges {
+		fmt.Fprintf(&b, "~ connection %d -> %d: %.4g -> %.4g (%+.4g)\n", c.Source, c.Target, c.Weight-c.Delta, c.Weight, c.Delta)
+	}
+	return b.String()
+}
+
+// connectionWeights returns the weights of every connection keyed by source and target ID, in storage order.
+func (bp *Blueprint) connectionWeights() map[[2]int][]float64 {
+	weights := make(map[[2]int][]float64)
+	for targetID, neuron := range bp.Neurons {
+		for i := 0; i < neuron.numConnections(); i++ {
+			sourceID, weight := neuron.connection(i)
+			edge := [2]int{sourceID, targetID}
+			weights[edge] = append(weights[edge], weight)
+		}
+	}
+	return weights
+}
+
+// sortConnectionChanges orders changes by target, then source, keeping duplicates in their original order.
+func sortConnectionChanges(changes []ConnectionChange) {
+	sort.SliceStable(changes, func(i, j int) bool {
+		if changes[i].Target != changes[j].Target {
+			return changes[i].Target < changes[j].Target
+		}
+		return changes[i].Source < changes[j].Source
+	})
+}
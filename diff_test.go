@@ -0,0 +1,38 @@
+package blueprint
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffReportsInsertedNeuronAndConnection(t *testing.T) {
+	parent := evalTestBlueprint()
+	child := parent.Clone()
+	child.Neurons[4] = &Neuron{ID: 4, Type: "dense", Activation: "relu", Connections: [][]float64{}}
+	if err := child.addConnection(4, 2, 0.25); err != nil {
+		t.Fatal(err)
+	}
+
+	diff := parent.Diff(child)
+	want := ModelDiff{
+		AddedNeurons:       []int{4},
+		RemovedNeurons:     []int{},
+		AddedConnections:   []ConnectionChange{{Source: 4, Target: 2, Weight: 0.25}},
+		RemovedConnections: []ConnectionChange{},
+		WeightChanges:      []ConnectionChange{},
+	}
+	if !reflect.DeepEqual(diff, want) {
+		t.Fatalf("Diff = %+v, want %+v", diff, want)
+	}
+	if got, want := diff.String(), "+ neuron 4\n+ connection 4 -> 2 (0.25)\n"; got != want {
+		t.Errorf("String = %q, want %q", got, want)
+	}
+
+	reverse := child.Diff(parent)
+	if !reflect.DeepEqual(reverse.RemovedNeurons, []int{4}) || len(reverse.RemovedConnections) != 1 || len(reverse.AddedNeurons) != 0 {
+		t.Errorf("reverse Diff = %+v, want neuron 4 and its connection removed", reverse)
+	}
+	if d := parent.Diff(parent.Clone()); !d.Empty() {
+		t.Errorf("Diff of a plain clone = %v, want no differences", d)
+	}
+}
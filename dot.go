@@ -0,0 +1,50 @@
+package blueprint
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ToDOT returns the network as a GraphViz digraph, ready to be rendered with `dot -Tpng`. Every neuron is a node
+// labeled with its ID, type and activation, input nodes filled green and output nodes filled red. Every connection
+// is an edge from its source to the neuron that stores it, labeled with its weight. Connections from missing
+// neurons are drawn to a node of that ID as well, so broken references stay visible. Quantum neurons are not drawn.
+func (bp *Blueprint) ToDOT() string {
+	var b strings.Builder
+	b.WriteString("digraph blueprint {\n")
+	b.WriteString("\trankdir=LR;\n")
+	b.WriteString("\tnode [shape=circle];\n")
+
+	neuronIDs := bp.getAllNeuronIDs()
+	for _, id := range neuronIDs {
+		neuron := bp.Neurons[id]
+		label := fmt.Sprintf("%d\\n%s", id, dotEscape(neuron.Type))
+		if neuron.Type != "input" && neuron.Activation != "" {
+			label += "\\n" + dotEscape(neuron.Activation)
+		}
+		attributes := fmt.Sprintf("label=\"%s\"", label)
+		switch {
+		case bp.isInputNode(id):
+			attributes += ", style=filled, fillcolor=green"
+		case bp.isOutputNode(id):
+			attributes += ", style=filled, fillcolor=red"
+		}
+		fmt.Fprintf(&b, "\t\"%d\" [%s];\n", id, attributes)
+	}
+
+	for _, id := range neuronIDs {
+		neuron := bp.Neurons[id]
+		for i := 0; i < neuron.numConnections(); i++ {
+			sourceID, weight := neuron.connection(i)
+			fmt.Fprintf(&b, "\t\"%d\" -> \"%d\" [label=\"%.4g\"];\n", sourceID, id, weight)
+		}
+	}
+
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// dotEscape escapes backslashes and quotes for use inside a quoted DOT string.
+func dotEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s)
+}
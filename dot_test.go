@@ -0,0 +1,34 @@
+package blueprint
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestToDOTDeclaresNodesAndEdges(t *testing.T) {
+	bp := NewBlueprint()
+	bp.AddInputNeurons([]int{1})
+	bp.Neurons[2] = &Neuron{ID: 2, Type: "dense", Activation: "relu", Connections: [][]float64{{1, 0.5}}}
+	bp.AddOutputNeurons([]int{3}, "sigmoid")
+	bp.Neurons[3].Connections = [][]float64{{2, -1.25}}
+
+	dot := bp.ToDOT()
+	for _, want := range []string{
+		"digraph blueprint {\n",
+		"\t\"1\" [label=\"1\\ninput\", style=filled, fillcolor=green];\n",
+		"\t\"2\" [label=\"2\\ndense\\nrelu\"];\n",
+		"\t\"3\" [label=\"3\\ndense\\nsigmoid\", style=filled, fillcolor=red];\n",
+		"\t\"1\" -> \"2\" [label=\"0.5\"];\n",
+		"\t\"2\" -> \"3\" [label=\"-1.25\"];\n",
+	} {
+		if !strings.Contains(dot, want) {
+			t.Errorf("DOT output lacks %q:\n%s", want, dot)
+		}
+	}
+	if strings.Count(dot, "->") != 2 {
+		t.Errorf("DOT output has %d edges, want 2:\n%s", strings.Count(dot, "->"), dot)
+	}
+	if !strings.HasSuffix(dot, "}\n") {
+		t.Errorf("DOT output is not closed:\n%s", dot)
+	}
+}
@@ -0,0 +1,92 @@
+package blueprint
+
+import "sort"
+
+// EffectiveParameters returns the number of independent trainable values in the model, counted like
+// ParameterCount after two simplifications that do not change what the model computes:
+// connections from the same source to the same target are merged into one weight, and hidden linear
+// dense neurons with a single source and a single dense consumer are folded into that consumer, whose
+// weight and bias absorb theirs. Connections from missing neurons are not counted. The model is not modified.
+func (bp *Blueprint) EffectiveParameters() int {
+	// Distinct existing sources of every neuron, and the distinct consumers of every source
+	sources := make(map[int]map[int]bool, len(bp.Neurons))
+	consumers := make(map[int]map[int]bool, len(bp.Neurons))
+	for id, neuron := range bp.Neurons {
+		sources[id] = make(map[int]bool)
+		for i := 0; i < neuron.numConnections(); i++ {
+			sourceID, _ := neuron.connection(i)
+			if _, exists := bp.Neurons[sourceID]; !exists {
+				continue
+			}
+			sources[id][sourceID] = true
+			if consumers[sourceID] == nil {
+				consumers[sourceID] = make(map[int]bool)
+			}
+			consumers[sourceID][id] = true
+		}
+	}
+
+	// Fold linear chain links until none are left, in ID order so the result is deterministic
+	neuronIDs := bp.getAllNeuronIDs()
+	sort.Ints(neuronIDs)
+	folded := make(map[int]bool)
+	for changed := true; changed; {
+		changed = false
+		for _, id := range neuronIDs {
+			if folded[id] || !bp.isFoldableLinear(id) || len(sources[id]) != 1 || len(consumers[id]) != 1 {
+				continue
+			}
+			sourceID, consumerID := onlyKey(sources[id]), onlyKey(consumers[id])
+			if sourceID == id || consumerID == id || !isDenseNeuronType(bp.Neurons[consumerID].Type) {
+				continue
+			}
+
+			// The consumer now reads the source directly, merging with any connection it already had
+			delete(sources[consumerID], id)
+			sources[consumerID][sourceID] = true
+			delete(consumers[sourceID], id)
+			consumers[sourceID][consumerID] = true
+			folded[id] = true
+			changed = true
+		}
+	}
+
+	count := 0
+	for id, neuron := range bp.Neurons {
+		if neuron.Type == "input" || folded[id] {
+			continue
+		}
+		count += 1 + len(sources[id]) + len(neuron.AttentionWeights)
+		for _, weights := range neuron.GateWeights {
+			count += len(weights)
+		}
+		for _, kernel := range neuron.Kernels {
+			count += len(kernel)
+		}
+		if neuron.BatchNormParams != nil {
+			count += 4
+		}
+		if neuron.Type == "rnn" {
+			count++
+		}
+	}
+	return count
+}
+
+// isFoldableLinear reports whether a neuron is a hidden dense neuron that passes its weighted sum through unchanged.
+func (bp *Blueprint) isFoldableLinear(id int) bool {
+	neuron := bp.Neurons[id]
+	if bp.isInputNode(id) || bp.isOutputNode(id) || neuron.Type == "input" {
+		return false
+	}
+	return isDenseNeuronType(neuron.Type) && neuron.Activation == "linear" &&
+		!neuron.BatchNorm && !neuron.Attention && neuron.BatchNormParams == nil
+}
+
+// onlyKey returns the key of a single-entry set.
+func onlyKey(set map[int]bool) int {
+	for key := range set {
+		return key
+	}
+	return 0
+}
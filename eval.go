@@ -2,7 +2,9 @@
 package blueprint
 
 import (
+	"fmt"
 	"math"
+	"math/rand"
 )
 
 // Session represents a training or testing session
@@ -12,48 +14,141 @@ type Session struct {
 	Timesteps      int             // Number of timesteps to run (for recurrent networks)
 }
 
+// NewSession builds a Session from parallel slices of neuron IDs and values.
+// Each ID slice must have the same length as its value slice.
+func NewSession(inputIDs []int, inputVals []float64, outputIDs []int, outputVals []float64, timesteps int) (Session, error) {
+	if len(inputIDs) != len(inputVals) {
+		return Session{}, fmt.Errorf("got %d input IDs but %d input values", len(inputIDs), len(inputVals))
+	}
+	if len(outputIDs) != len(outputVals) {
+		return Session{}, fmt.Errorf("got %d output IDs but %d output values", len(outputIDs), len(outputVals))
+	}
+
+	session := Session{
+		InputVariables: make(map[int]float64, len(inputIDs)),
+		ExpectedOutput: make(map[int]float64, len(outputIDs)),
+		Timesteps:      timesteps,
+	}
+	for i, id := range inputIDs {
+		session.InputVariables[id] = inputVals[i]
+	}
+	for i, id := range outputIDs {
+		session.ExpectedOutput[id] = outputVals[i]
+	}
+	return session, nil
+}
+
 // EvaluateModelPerformance evaluates the model's performance over a list of sessions,
 // returning exact accuracy, generous accuracy, decile consistency accuracy, and their associated errors.
+// Generous accuracy compares the softmaxed outputs with the expected outputs normalized to a distribution,
+// see calculateGenerousValue. The three metrics are ExactMetric, GenerousMetric and ForgivenessMetric, scored
+// through EvaluateWithMetrics.
+// Results for stateless models are cached by model and session hash, so re-evaluating an unchanged
+// model on the same sessions returns immediately without running the network.
+// An empty session slice yields zero for every metric rather than NaN. The model is evaluated outside
+// training mode, so dropout is deterministic; TrainingMode is restored afterwards.
 func (bp *Blueprint) EvaluateModelPerformance(sessions []Session) (float64, float64, float64, int, float64, int) {
-	exactCorrectPredictions := 0
-	decileConsistentCount := 0
-	exactErrorCount := 0
-	totalGenerousValue := 0.0
-	totalGenerousError := 0.0
-	decileInconsistentCount := 0
+	defer bp.evalMode()()
+	if len(sessions) == 0 {
+		bp.debugf("Warning: EvaluateModelPerformance called without sessions; returning zero metrics.")
+		return 0, 0, 0, 0, 0, 0
+	}
 
-	for _, session := range sessions {
-		bp.RunNetwork(session.InputVariables, session.Timesteps)
-		predictedOutput := bp.GetOutputs()
+	cacheKey, cached, found := bp.cachedEvaluation(sessions)
+	if found {
+		return cached.ExactAccuracy, cached.GenerousAccuracy, cached.ForgivenessAccuracy,
+			cached.ExactErrorCount, cached.AverageGenerousError, cached.ForgivenessErrorCount
+	}
 
-		probs := softmaxMap(predictedOutput)
-		predClass := argmaxMap(probs)
-		expClass := argmaxMap(session.ExpectedOutput)
+	scores := bp.EvaluateWithMetrics(sessions, bp.defaultMetrics())
+	exactAccuracy := scores["exact"]
+	generousAccuracy := scores["generous"]
+	decileConsistencyAccuracy := scores["forgiveness"]
+
+	// Every session scores 0 or 100 on the exact and forgiveness metrics
+	exactErrorCount := int(math.Round((100 - exactAccuracy) / 100 * float64(len(sessions))))
+	decileInconsistentCount := int(math.Round((100 - decileConsistencyAccuracy) / 100 * float64(len(sessions))))
+	averageGenerousError := getMaxFloat() - generousAccuracy
+
+	storeEvaluation(cacheKey, EvaluationResult{
+		ExactAccuracy:         exactAccuracy,
+		GenerousAccuracy:      generousAccuracy,
+		ForgivenessAccuracy:   decileConsistencyAccuracy,
+		ExactErrorCount:       exactErrorCount,
+		AverageGenerousError:  averageGenerousError,
+		ForgivenessErrorCount: decileInconsistentCount,
+	})
 
-		if predClass == expClass {
-			exactCorrectPredictions++
-		} else {
-			exactErrorCount++
-		}
+	return exactAccuracy, generousAccuracy, decileConsistencyAccuracy, exactErrorCount, averageGenerousError, decileInconsistentCount
+}
 
-		generousValue := calculateGenerousValue(predictedOutput, session.ExpectedOutput)
-		totalGenerousValue += generousValue
-		generousError := getMaxFloat() - generousValue
-		totalGenerousError += generousError
+// evalMode leaves training mode and returns a function that restores the previous mode.
+func (bp *Blueprint) evalMode() func() {
+	training := bp.TrainingMode
+	bp.TrainingMode = false
+	return func() { bp.TrainingMode = training }
+}
 
-		if isDecileConsistent(predictedOutput, session.ExpectedOutput) {
-			decileConsistentCount++
-		} else {
-			decileInconsistentCount++
-		}
+// EvaluationResult bundles the metrics returned by EvaluateModelPerformance.
+type EvaluationResult struct {
+	ExactAccuracy         float64 // Percentage of sessions whose predicted class matches the expected class
+	GenerousAccuracy      float64 // Average closeness, between 0 and 1, of the output probabilities to the normalized expected outputs
+	ForgivenessAccuracy   float64 // Decile consistency accuracy
+	ExactErrorCount       int     // Sessions with the wrong predicted class
+	AverageGenerousError  float64 // Average generous error
+	ForgivenessErrorCount int     // Sessions that were not decile consistent
+}
+
+// Evaluate runs EvaluateModelPerformance and returns its metrics as an EvaluationResult.
+func (bp *Blueprint) Evaluate(sessions []Session) EvaluationResult {
+	exact, generous, forgiveness, exactErrors, generousError, forgivenessErrors := bp.EvaluateModelPerformance(sessions)
+	return EvaluationResult{
+		ExactAccuracy:         exact,
+		GenerousAccuracy:      generous,
+		ForgivenessAccuracy:   forgiveness,
+		ExactErrorCount:       exactErrors,
+		AverageGenerousError:  generousError,
+		ForgivenessErrorCount: forgivenessErrors,
 	}
+}
 
-	exactAccuracy := float64(exactCorrectPredictions) / float64(len(sessions)) * 100.0
-	generousAccuracy := totalGenerousValue / float64(len(sessions))
-	decileConsistencyAccuracy := float64(decileConsistentCount) / float64(len(sessions)) * 100.0
-	averageGenerousError := totalGenerousError / float64(len(sessions))
+// EvaluateOnSample evaluates the model on a random subsample of sampleSize sessions drawn with the given seed.
+// The whole session set is used when sampleSize is not smaller than the number of sessions.
+func (bp *Blueprint) EvaluateOnSample(sessions []Session, sampleSize int, seed int64) EvaluationResult {
+	return bp.Evaluate(sampleSessions(sessions, sampleSize, seed))
+}
 
-	return exactAccuracy, generousAccuracy, decileConsistencyAccuracy, exactErrorCount, averageGenerousError, decileInconsistentCount
+// sampleSessions draws up to n sessions at random without modifying the input slice.
+func sampleSessions(sessions []Session, n int, seed int64) []Session {
+	if n <= 0 || n >= len(sessions) {
+		return sessions
+	}
+	rng := rand.New(rand.NewSource(seed))
+	sample := make([]Session, n)
+	for i, idx := range rng.Perm(len(sessions))[:n] {
+		sample[i] = sessions[idx]
+	}
+	return sample
+}
+
+// SplitSessions shuffles the sessions with a generator seeded by seed and splits them into a training set holding
+// trainFrac of them, rounded to the nearest session, and a validation set holding the rest. The same seed always
+// gives the same split, and the input slice is not modified. trainFrac is clamped to [0, 1].
+func SplitSessions(sessions []Session, trainFrac float64, seed int64) (train, val []Session) {
+	trainFrac = math.Max(0, math.Min(1, trainFrac))
+	numTrain := int(math.Round(trainFrac * float64(len(sessions))))
+
+	rng := rand.New(rand.NewSource(seed))
+	train = make([]Session, 0, numTrain)
+	val = make([]Session, 0, len(sessions)-numTrain)
+	for i, idx := range rng.Perm(len(sessions)) {
+		if i < numTrain {
+			train = append(train, sessions[idx])
+		} else {
+			val = append(val, sessions[idx])
+		}
+	}
+	return train, val
 }
 
 // Helper functions
@@ -106,11 +201,24 @@ func calculateSimilarityScore(predicted, expected map[int]float64) float64 {
 	return similarity
 }
 
-// isDecileConsistent checks if the predicted output falls consistently within the same decile across all expected values.
-func isDecileConsistent(predicted, expected map[int]float64) bool {
-	const decileStep = 0.1 // Deciles: 10%, 20%, ..., 90%
+// defaultDecileStep is the error bucket width of forgiveness accuracy when the blueprint does not set DecileStep.
+const defaultDecileStep = 0.1
+
+// decileStep returns the error bucket width of forgiveness accuracy.
+func (bp *Blueprint) decileStep() float64 {
+	if bp.DecileStep > 0 {
+		return bp.DecileStep
+	}
+	return defaultDecileStep
+}
+
+// isDecileConsistent checks if the absolute error of every predicted output falls in the same bucket of width
+// step, the last bucket also holding every error of 1 or more. With the default step of 0.1 the buckets are
+// deciles: [0, 0.1), [0.1, 0.2), ..., [0.9, ∞).
+func isDecileConsistent(predicted, expected map[int]float64, step float64) bool {
+	lastDecile := max(int(math.Ceil(1/step))-1, 0)
 
-	var referenceDecile int
+	referenceDecile, referenceSet := 0, false
 	for id, expectedValue := range expected {
 		predictedValue, exists := predicted[id]
 		if !exists {
@@ -119,13 +227,13 @@ func isDecileConsistent(predicted, expected map[int]float64) bool {
 
 		// Determine the decile for the current predicted value
 		difference := math.Abs(predictedValue - expectedValue)
-		decile := int(difference / decileStep)
-		if decile > 9 {
-			decile = 9 // Cap at the 90% decile
+		decile := int(difference / step)
+		if decile > lastDecile {
+			decile = lastDecile
 		}
 
-		if referenceDecile == 0 {
-			referenceDecile = decile
+		if !referenceSet {
+			referenceDecile, referenceSet = decile, true
 		} else if referenceDecile != decile {
 			return false
 		}
@@ -134,27 +242,39 @@ func isDecileConsistent(predicted, expected map[int]float64) bool {
 	return true
 }
 
-// calculateGenerousValue calculates a similarity value based on the highest floating-point value.
+// calculateGenerousValue returns 1 minus the mean absolute difference between the predicted and expected
+// outputs, floored at 0. It operates in probability space: predicted holds the outputs after Forward's softmax,
+// so expected is normalized to sum to 1 over the outputs being compared, making a one-hot target and a perfect
+// one-hot prediction score 1 whatever scale the target is written in. Expected outputs that do not sum to a
+// positive value are compared as they are.
 func calculateGenerousValue(predicted, expected map[int]float64) float64 {
 	if len(predicted) == 0 || len(expected) == 0 {
 		return 0.0
 	}
 
-	totalDifference := 0.0
+	expectedTotal := 0.0
 	count := 0
 	for id, expectedValue := range expected {
-		predictedValue, exists := predicted[id]
-		if !exists {
-			continue
+		if _, exists := predicted[id]; exists {
+			expectedTotal += expectedValue
+			count++
 		}
-		difference := math.Abs(predictedValue - expectedValue)
-		totalDifference += difference
-		count++
 	}
-
 	if count == 0 {
 		return 0.0 // Avoid division by zero
 	}
+	if expectedTotal <= 0 || math.IsNaN(expectedTotal) {
+		expectedTotal = 1
+	}
+
+	totalDifference := 0.0
+	for id, expectedValue := range expected {
+		predictedValue, exists := predicted[id]
+		if !exists {
+			continue
+		}
+		totalDifference += math.Abs(predictedValue - expectedValue/expectedTotal)
+	}
 
 	meanDifference := totalDifference / float64(count)
 	generousValue := 1.0 - meanDifference // Ensure the value is bounded between 0 and 1
@@ -213,18 +333,23 @@ func calculateWeightedProximity(predicted, expected map[int]float64) float64 {
 }
 
 func (bp *Blueprint) AdvancedEvaluateModelPerformance(sessions []Session) (float64, float64, map[string]float64, float64, int, float64, int) {
+	defer bp.evalMode()()
 	exactCorrectPredictions := 0
 	totalGenerousValue := 0.0
 	totalAdvancedMetrics := map[string]float64{
-		"weightedProximity":   0.0,
-		"classSensitivity":    0.0,
-		"temporalConsistency": 0.0,
+		"weightedProximity": 0.0,
+		"classSensitivity":  0.0,
 	}
 	totalGenerousError := 0.0
 	exactErrorCount := 0
 	decileConsistentCount := 0
 	decileInconsistentCount := 0
 
+	if len(sessions) == 0 {
+		bp.debugf("Warning: AdvancedEvaluateModelPerformance called without sessions; returning zero metrics.")
+		return 0, 0, totalAdvancedMetrics, 0, 0, 0, 0
+	}
+
 	for _, session := range sessions {
 		bp.RunNetwork(session.InputVariables, session.Timesteps)
 		predictedOutput := bp.GetOutputs()
@@ -248,9 +373,8 @@ func (bp *Blueprint) AdvancedEvaluateModelPerformance(sessions []Session) (float
 		// Advanced generous metrics
 		totalAdvancedMetrics["weightedProximity"] += calculateWeightedProximity(predictedOutput, session.ExpectedOutput)
 		totalAdvancedMetrics["classSensitivity"] += calculateClassSensitivity(predictedOutput, session.ExpectedOutput)
-		totalAdvancedMetrics["temporalConsistency"] += calculateTemporalConsistency(predictedOutput, session.ExpectedOutput)
 
-		if isDecileConsistent(predictedOutput, session.ExpectedOutput) {
+		if isDecileConsistent(predictedOutput, session.ExpectedOutput, bp.decileStep()) {
 			decileConsistentCount++
 		} else {
 			decileInconsistentCount++
@@ -267,43 +391,3 @@ func (bp *Blueprint) AdvancedEvaluateModelPerformance(sessions []Session) (float
 
 	return exactAccuracy, generousAccuracy, totalAdvancedMetrics, decileConsistencyAccuracy, exactErrorCount, averageGenerousError, decileInconsistentCount
 }
-
-// calculateTemporalConsistency evaluates stability across timesteps for recurrent networks.
-func calculateTemporalConsistency(predicted, expected map[int]float64) float64 {
-	if len(predicted) == 0 || len(expected) == 0 {
-		return 0.0
-	}
-
-	// Assuming predicted and expected contain temporal sequences as time-indexed keys.
-	// You can replace this logic with the actual representation of your time-indexed data.
-
-	var prevPredicted float64
-	var prevExpected float64
-	temporalStability := 0.0
-	numComparisons := 0
-
-	for t := 1; t < len(predicted); t++ { // Assuming keys are time indices
-		currentPredicted := predicted[t]
-		currentExpected := expected[t]
-
-		if t > 1 { // Compare with previous values to assess consistency
-			predictedDelta := math.Abs(currentPredicted - prevPredicted)
-			expectedDelta := math.Abs(currentExpected - prevExpected)
-
-			// Penalize large inconsistencies
-			stabilityScore := math.Max(0, 1-math.Abs(predictedDelta-expectedDelta))
-			temporalStability += stabilityScore
-			numComparisons++
-		}
-
-		prevPredicted = currentPredicted
-		prevExpected = currentExpected
-	}
-
-	if numComparisons == 0 {
-		return 0.0
-	}
-
-	// Normalize stability score
-	return temporalStability / float64(numComparisons) * 100.0
-}
@@ -0,0 +1,83 @@
+package blueprint
+
+// Metric scores the prediction of a single session. EvaluateWithMetrics reports the mean score over the sessions
+// under the metric's name. Predicted holds the output values after Forward's softmax and expected the session's
+// expected outputs, both keyed by output neuron ID.
+type Metric interface {
+	Name() string
+	Score(predicted, expected map[int]float64) float64
+}
+
+// ExactMetric scores 100 when the predicted class, the output with the highest value, matches the expected class.
+type ExactMetric struct{}
+
+func (ExactMetric) Name() string { return "exact" }
+
+func (ExactMetric) Score(predicted, expected map[int]float64) float64 {
+	if argmaxMap(softmaxMap(predicted)) == argmaxMap(expected) {
+		return 100
+	}
+	return 0
+}
+
+// GenerousMetric scores the closeness, between 0 and 1, of the predicted to the expected outputs, see
+// calculateGenerousValue.
+type GenerousMetric struct{}
+
+func (GenerousMetric) Name() string { return "generous" }
+
+func (GenerousMetric) Score(predicted, expected map[int]float64) float64 {
+	return calculateGenerousValue(predicted, expected)
+}
+
+// ForgivenessMetric scores 100 when the error of every output falls in the same bucket of width Step
+// (0 uses the default of 0.1), see DecileStep.
+type ForgivenessMetric struct {
+	Step float64
+}
+
+func (ForgivenessMetric) Name() string { return "forgiveness" }
+
+func (m ForgivenessMetric) Score(predicted, expected map[int]float64) float64 {
+	step := m.Step
+	if step <= 0 {
+		step = defaultDecileStep
+	}
+	if isDecileConsistent(predicted, expected, step) {
+		return 100
+	}
+	return 0
+}
+
+// defaultMetrics returns the metrics behind EvaluateModelPerformance, using the blueprint's decile step.
+func (bp *Blueprint) defaultMetrics() []Metric {
+	return []Metric{ExactMetric{}, GenerousMetric{}, ForgivenessMetric{Step: bp.decileStep()}}
+}
+
+// EvaluateWithMetrics runs every session once and returns the mean score of each metric keyed by its name.
+// A nil or empty metrics slice uses the exact, generous and forgiveness metrics of EvaluateModelPerformance.
+// Results are not cached, and a metric whose name repeats an earlier one overwrites its score.
+func (bp *Blueprint) EvaluateWithMetrics(sessions []Session, metrics []Metric) map[string]float64 {
+	defer bp.evalMode()()
+	if len(metrics) == 0 {
+		metrics = bp.defaultMetrics()
+	}
+	totals := make([]float64, len(metrics))
+	for _, session := range sessions {
+		bp.RunNetwork(session.InputVariables, session.Timesteps)
+		predictedOutput := bp.GetOutputs()
+		for i, metric := range metrics {
+			totals[i] += metric.Score(predictedOutput, session.ExpectedOutput)
+		}
+	}
+
+	results := make(map[string]float64, len(metrics))
+	for i, metric := range metrics {
+		if len(sessions) == 0 {
+			results[metric.Name()] = 0
+			continue
+		}
+		results[metric.Name()] = totals[i] / float64(len(sessions))
+	}
+	return results
+}
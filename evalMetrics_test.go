@@ -0,0 +1,55 @@
+package blueprint
+
+import (
+	"math"
+	"testing"
+)
+
+// rmseMetric scores the root mean squared error of a session's outputs.
+type rmseMetric struct{}
+
+func (rmseMetric) Name() string { return "rmse" }
+
+func (rmseMetric) Score(predicted, expected map[int]float64) float64 {
+	sum := 0.0
+	for id, value := range expected {
+		sum += (predicted[id] - value) * (predicted[id] - value)
+	}
+	return math.Sqrt(sum / float64(len(expected)))
+}
+
+func TestEvaluateWithCustomMetric(t *testing.T) {
+	bp := evalTestBlueprint()
+	sessions := []Session{}
+	for _, x := range []float64{-1, 0.5, 2} {
+		sessions = append(sessions, Session{
+			InputVariables: map[int]float64{1: x},
+			ExpectedOutput: map[int]float64{2: 1, 3: 0},
+			Timesteps:      1,
+		})
+	}
+
+	// Output 2 has probability p = e^x / (e^x + e^-x), so both outputs miss their targets by 1 - p
+	want := 0.0
+	for _, session := range sessions {
+		x := session.InputVariables[1]
+		want += (1 - math.Exp(x)/(math.Exp(x)+math.Exp(-x))) / float64(len(sessions))
+	}
+
+	results := bp.EvaluateWithMetrics(sessions, []Metric{rmseMetric{}, ExactMetric{}})
+	if len(results) != 2 {
+		t.Fatalf("EvaluateWithMetrics returned %v, want rmse and exact", results)
+	}
+	if got := results["rmse"]; math.Abs(got-want) > 1e-12 {
+		t.Errorf("rmse = %v, want %v", got, want)
+	}
+	if got := results["exact"]; math.Abs(got-200.0/3) > 1e-9 {
+		t.Errorf("exact = %v, want %v", got, 200.0/3)
+	}
+
+	defaults := bp.EvaluateWithMetrics(sessions, nil)
+	exact, generous, forgiveness, _, _, _ := bp.EvaluateModelPerformance(sessions)
+	if defaults["exact"] != exact || defaults["generous"] != generous || defaults["forgiveness"] != forgiveness {
+		t.Errorf("default metrics %v differ from EvaluateModelPerformance's %v/%v/%v", defaults, exact, generous, forgiveness)
+	}
+}
@@ -0,0 +1,156 @@
+package blueprint
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+// evalTestBlueprint returns a network with one input and two linear outputs, output 2 weighted up and
+// output 3 weighted down.
+func evalTestBlueprint() *Blueprint {
+	bp := NewBlueprint()
+	bp.AddInputNeurons([]int{1})
+	bp.AddOutputNeurons([]int{2, 3}, "linear")
+	bp.Neurons[2].Connections = [][]float64{{1, 1}}
+	bp.Neurons[3].Connections = [][]float64{{1, -1}}
+	return bp
+}
+
+func TestEvaluateEmptySessionsHasNoNaN(t *testing.T) {
+	bp := evalTestBlueprint()
+	check := func(name string, values ...float64) {
+		t.Helper()
+		for i, v := range values {
+			if math.IsNaN(v) {
+				t.Errorf("%s returned NaN as value %d", name, i)
+			}
+		}
+	}
+
+	exact, generous, forgiveness, exactErrors, generousError, forgivenessErrors := bp.EvaluateModelPerformance(nil)
+	check("EvaluateModelPerformance", exact, generous, forgiveness, float64(exactErrors), generousError, float64(forgivenessErrors))
+
+	exact, generous, forgiveness, exactErrors, generousError, forgivenessErrors = bp.EvaluateModelPerformance([]Session{})
+	check("EvaluateModelPerformance", exact, generous, forgiveness, float64(exactErrors), generousError, float64(forgivenessErrors))
+
+	result := bp.Evaluate([]Session{})
+	check("Evaluate", result.ExactAccuracy, result.GenerousAccuracy, result.ForgivenessAccuracy, result.AverageGenerousError)
+
+	advExact, advGenerous, perClass, advGenerousError, advExactErrors, advForgiveness, advForgivenessErrors := bp.AdvancedEvaluateModelPerformance([]Session{})
+	check("AdvancedEvaluateModelPerformance", advExact, advGenerous, advGenerousError, float64(advExactErrors), advForgiveness, float64(advForgivenessErrors))
+	for class, value := range perClass {
+		check("AdvancedEvaluateModelPerformance class "+class, value)
+	}
+
+	for name, value := range bp.EvaluateWithMetrics([]Session{}, bp.defaultMetrics()) {
+		check("EvaluateWithMetrics "+name, value)
+	}
+}
+
+func TestGenerousAccuracyOfPerfectOneHot(t *testing.T) {
+	predicted := map[int]float64{2: 0, 3: 1, 4: 0}
+	for _, expected := range []map[int]float64{
+		{2: 0, 3: 1, 4: 0},
+		{2: 0, 3: 5, 4: 0}, // Targets written on another scale are compared as probabilities
+	} {
+		if got := calculateGenerousValue(predicted, expected); got != 1 {
+			t.Errorf("generous value of %v against %v is %v, want 1", predicted, expected, got)
+		}
+	}
+
+	// A network whose softmax saturates to a one-hot prediction of the middle class
+	bp := NewBlueprint()
+	bp.AddInputNeurons([]int{1})
+	bp.AddOutputNeurons([]int{2, 3, 4}, "linear")
+	bp.Neurons[3].Connections = [][]float64{{1, 1000}}
+	sessions := []Session{{InputVariables: map[int]float64{1: 1}, ExpectedOutput: map[int]float64{2: 0, 3: 1, 4: 0}, Timesteps: 1}}
+
+	exact, generous, _, _, _, _ := bp.EvaluateModelPerformance(sessions)
+	if exact != 100 || generous != 1 {
+		t.Errorf("got exact accuracy %v%% and generous accuracy %v, want 100%% and 1", exact, generous)
+	}
+}
+
+func TestSplitSessionsIsDeterministicAndDisjoint(t *testing.T) {
+	sessions := make([]Session, 10)
+	for i := range sessions {
+		sessions[i] = Session{InputVariables: map[int]float64{1: float64(i)}}
+	}
+	ids := func(split []Session) []int {
+		out := make([]int, len(split))
+		for i, s := range split {
+			out[i] = int(s.InputVariables[1])
+		}
+		return out
+	}
+
+	train, val := SplitSessions(sessions, 0.7, 42)
+	if len(train) != 7 || len(val) != 3 {
+		t.Fatalf("split sizes = %d/%d, want 7/3", len(train), len(val))
+	}
+	seen := map[int]bool{}
+	for _, id := range append(ids(train), ids(val)...) {
+		if seen[id] {
+			t.Fatalf("session %d appears in both partitions", id)
+		}
+		seen[id] = true
+	}
+	if len(seen) != len(sessions) {
+		t.Fatalf("partitions cover %d sessions, want %d", len(seen), len(sessions))
+	}
+
+	train2, val2 := SplitSessions(sessions, 0.7, 42)
+	if fmt.Sprint(ids(train), ids(val)) != fmt.Sprint(ids(train2), ids(val2)) {
+		t.Errorf("same seed gave different splits: %v/%v and %v/%v", ids(train), ids(val), ids(train2), ids(val2))
+	}
+	for i, s := range sessions {
+		if int(s.InputVariables[1]) != i {
+			t.Fatalf("SplitSessions reordered its input")
+		}
+	}
+
+	if train, val := SplitSessions(sessions, 0.25, 1); len(train) != 3 || len(val) != 7 {
+		t.Errorf("0.25 split sizes = %d/%d, want 3/7 (rounded)", len(train), len(val))
+	}
+	if train, val := SplitSessions(sessions, 1.5, 1); len(train) != 10 || len(val) != 0 {
+		t.Errorf("clamped split sizes = %d/%d, want 10/0", len(train), len(val))
+	}
+}
+
+func TestZeroDecilePredictionsAreConsistent(t *testing.T) {
+	cases := []struct {
+		name      string
+		predicted map[int]float64
+		step      float64
+		want      bool
+	}{
+		{"exact", map[int]float64{2: 0.8, 3: 0.2}, 0.1, true},
+		{"all in decile 0", map[int]float64{2: 0.77, 3: 0.25}, 0.1, true},
+		{"decile 0 and 1", map[int]float64{2: 0.79, 3: 0.35}, 0.1, false},
+		{"decile 0 and last", map[int]float64{2: 0.8, 3: 1.5}, 0.1, false},
+		{"coarser step", map[int]float64{2: 0.79, 3: 0.35}, 0.25, true},
+		{"missing output", map[int]float64{2: 0.8}, 0.1, false},
+	}
+	expected := map[int]float64{2: 0.8, 3: 0.2}
+	for _, c := range cases {
+		if got := isDecileConsistent(c.predicted, expected, c.step); got != c.want {
+			t.Errorf("%s: isDecileConsistent = %v, want %v", c.name, got, c.want)
+		}
+	}
+
+	// Near-perfect predictions have every error in decile 0
+	bp := evalTestBlueprint()
+	sessions := []Session{}
+	for _, x := range []float64{-1, 0.5, 2} {
+		p := math.Exp(x) / (math.Exp(x) + math.Exp(-x))
+		sessions = append(sessions, Session{
+			InputVariables: map[int]float64{1: x},
+			ExpectedOutput: map[int]float64{2: p + 0.01, 3: 1 - p - 0.01},
+			Timesteps:      1,
+		})
+	}
+	if result := bp.Evaluate(sessions); result.ForgivenessAccuracy != 100 || result.ForgivenessErrorCount != 0 {
+		t.Errorf("forgiveness accuracy = %v with %d errors, want 100 with none", result.ForgivenessAccuracy, result.ForgivenessErrorCount)
+	}
+}
@@ -0,0 +1,237 @@
+package blueprint
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// reportSchemaVersion is bumped whenever fields of MetricsReport change meaning or are removed.
+const reportSchemaVersion = 1
+
+// calibrationBins is the number of equal-width confidence bins used for the expected calibration error.
+const calibrationBins = 10
+
+// MetricsReport is the document produced by EvaluationReport.
+type MetricsReport struct {
+	SchemaVersion       int     `json:"schema_version"`
+	Sessions            int     `json:"sessions"`
+	ExactAccuracy       float64 `json:"exact_accuracy"`       // Percentage
+	GenerousAccuracy    float64 `json:"generous_accuracy"`    // Score between 0 and 1
+	ForgivenessAccuracy float64 `json:"forgiveness_accuracy"` // Percentage
+
+	Classes         []int          `json:"classes"`          // Output neuron ID of each confusion matrix row and column
+	ConfusionMatrix [][]int        `json:"confusion_matrix"` // Rows are expected classes, columns predicted classes
+	PerClass        []ClassMetrics `json:"per_class"`
+	TopKAccuracy    []TopKAccuracy `json:"top_k_accuracy"`
+
+	// ExpectedCalibrationError is the session-weighted gap between confidence and accuracy over
+	// 10 confidence bins, between 0 (perfectly calibrated) and 1.
+	ExpectedCalibrationError float64 `json:"expected_calibration_error"`
+}
+
+// ClassMetrics holds the precision, recall and F1 score of one class, each between 0 and 1.
+type ClassMetrics struct {
+	Class     int     `json:"class"` // Output neuron ID
+	Support   int     `json:"support"`
+	Precision float64 `json:"precision"`
+	Recall    float64 `json:"recall"`
+	F1        float64 `json:"f1"`
+}
+
+// TopKAccuracy is the percentage of sessions whose expected class is among the K most probable outputs.
+type TopKAccuracy struct {
+	K        int     `json:"k"`
+	Accuracy float64 `json:"accuracy"`
+}
+
+// EvaluationReport evaluates the model on sessions and returns a JSON MetricsReport bundling the aggregate
+// accuracies, the confusion matrix over OutputNodes, per-class precision/recall/F1, top-k accuracy for k up
+// to 5 and the expected calibration error. Classes are output neurons and a session's expected class is its
+// largest expected output; ties in the predicted outputs go to the earlier output node.
+func (bp *Blueprint) EvaluationReport(sessions []Session) ([]byte, error) {
+	if len(sessions) == 0 {
+		return nil, fmt.Errorf("no sessions to evaluate")
+	}
+	if len(bp.OutputNodes) == 0 {
+		return nil, fmt.Errorf("blueprint has no output nodes")
+	}
+
+	exact, generous, forgiveness, _, _, _ := bp.EvaluateModelPerformance(sessions)
+	report := MetricsReport{
+		SchemaVersion:       reportSchemaVersion,
+		Sessions:            len(sessions),
+		ExactAccuracy:       exact,
+		GenerousAccuracy:    generous,
+		ForgivenessAccuracy: forgiveness,
+		Classes:             append([]int{}, bp.OutputNodes...),
+	}
+
+	classIndex := make(map[int]int, len(bp.OutputNodes))
+	for i, id := range bp.OutputNodes {
+		classIndex[id] = i
+	}
+	numClasses := len(bp.OutputNodes)
+	maxK := numClasses
+	if maxK > 5 {
+		maxK = 5
+	}
+
+	report.ConfusionMatrix = make([][]int, numClasses)
+	for i := range report.ConfusionMatrix {
+		report.ConfusionMatrix[i] = make([]int, numClasses)
+	}
+	topKHits := make([]int, maxK+1)
+	binCount := make([]int, calibrationBins)
+	binConfidence := make([]float64, calibrationBins)
+	binCorrect := make([]int, calibrationBins)
+
+	for _, session := range sessions {
+		bp.RunNetwork(session.InputVariables, session.Timesteps)
+		outputs := bp.GetOutputs()
+		probs := make([]float64, numClasses)
+		for i, id := range bp.OutputNodes {
+			probs[i] = outputs[id]
+		}
+
+		// Rank classes by probability, keeping output order on ties
+		ranked := make([]int, numClasses)
+		for i := range ranked {
+			ranked[i] = i
+		}
+		sort.SliceStable(ranked, func(a, b int) bool { return probs[ranked[a]] > probs[ranked[b]] })
+		predicted := ranked[0]
+
+		expected, known := classIndex[argmaxMap(session.ExpectedOutput)]
+		if !known {
+			continue
+		}
+		report.ConfusionMatrix[expected][predicted]++
+		for k := 1; k <= maxK; k++ {
+			if ranked[k-1] == expected {
+				for j := k; j <= maxK; j++ {
+					topKHits[j]++
+				}
+				break
+			}
+		}
+
+		confidence := probs[predicted]
+		if math.IsNaN(confidence) {
+			confidence = 0
+		}
+		bin := int(confidence * calibrationBins)
+		if bin >= calibrationBins {
+			bin = calibrationBins - 1
+		} else if bin < 0 {
+			bin = 0
+		}
+		binCount[bin]++
+		binConfidence[bin] += confidence
+		if predicted == expected {
+			binCorrect[bin]++
+		}
+	}
+
+	precision, recall, f1 := PrecisionRecallF1(report.ConfusionMatrix)
+	for i, id := range bp.OutputNodes {
+		support := 0
+		for _, count := range report.ConfusionMatrix[i] {
+			support += count
+		}
+		report.PerClass = append(report.PerClass, ClassMetrics{
+			Class:     id,
+			Support:   support,
+			Precision: precision[i],
+			Recall:    recall[i],
+			F1:        f1[i],
+		})
+	}
+
+	for k := 1; k <= maxK; k++ {
+		report.TopKAccuracy = append(report.TopKAccuracy, TopKAccuracy{
+			K:        k,
+			Accuracy: float64(topKHits[k]) / float64(len(sessions)) * 100.0,
+		})
+	}
+
+	for bin := range binCount {
+		if binCount[bin] == 0 {
+			continue
+		}
+		gap := math.Abs(binConfidence[bin]/float64(binCount[bin]) - float64(binCorrect[bin])/float64(binCount[bin]))
+		report.ExpectedCalibrationError += gap * float64(binCount[bin]) / float64(len(sessions))
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize evaluation report: %w", err)
+	}
+	return data, nil
+}
+
+// ConfusionMatrix runs every session and tallies a numClasses×numClasses matrix whose rows are expected and
+// columns predicted classes. Class c is output node OutputNodes[c]; a session's expected class is its largest
+// expected output and its predicted class its most probable output, ties going to the earlier output node.
+// Classes beyond the output nodes stay empty, and output nodes beyond numClasses are ignored.
+func (bp *Blueprint) ConfusionMatrix(sessions []Session, numClasses int) [][]int {
+	if numClasses <= 0 {
+		return [][]int{}
+	}
+	cm := make([][]int, numClasses)
+	for i := range cm {
+		cm[i] = make([]int, numClasses)
+	}
+	classes := bp.OutputNodes
+	if len(classes) > numClasses {
+		classes = classes[:numClasses]
+	}
+	if len(classes) == 0 {
+		return cm
+	}
+
+	argmax := func(values map[int]float64) int {
+		best := 0
+		for c, id := range classes {
+			if values[id] > values[classes[best]] {
+				best = c
+			}
+		}
+		return best
+	}
+	defer bp.evalMode()()
+	for _, session := range sessions {
+		bp.RunNetwork(session.InputVariables, session.Timesteps)
+		cm[argmax(session.ExpectedOutput)][argmax(bp.GetOutputs())]++
+	}
+	return cm
+}
+
+// PrecisionRecallF1 computes the per-class precision, recall and F1 score of a confusion matrix whose rows
+// are expected and columns predicted classes, such as the one ConfusionMatrix returns. Classes that are never
+// predicted or never expected score 0.
+func PrecisionRecallF1(cm [][]int) (precision, recall, f1 []float64) {
+	n := len(cm)
+	precision = make([]float64, n)
+	recall = make([]float64, n)
+	f1 = make([]float64, n)
+	for c := 0; c < n; c++ {
+		truePositives := cm[c][c]
+		predicted, expected := 0, 0
+		for other := 0; other < n; other++ {
+			predicted += cm[other][c]
+			expected += cm[c][other]
+		}
+		if predicted > 0 {
+			precision[c] = float64(truePositives) / float64(predicted)
+		}
+		if expected > 0 {
+			recall[c] = float64(truePositives) / float64(expected)
+		}
+		if precision[c]+recall[c] > 0 {
+			f1[c] = 2 * precision[c] * recall[c] / (precision[c] + recall[c])
+		}
+	}
+	return precision, recall, f1
+}
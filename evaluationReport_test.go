@@ -0,0 +1,52 @@
+package blueprint
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestConfusionMatrixOfKnownPredictions(t *testing.T) {
+	bp := evalTestBlueprint()
+	session := func(input float64, class int) Session {
+		expected := map[int]float64{2: 0, 3: 0}
+		expected[bp.OutputNodes[class]] = 1
+		return Session{InputVariables: map[int]float64{1: input}, ExpectedOutput: expected, Timesteps: 1}
+	}
+	// Positive inputs predict class 0 and negative inputs class 1
+	sessions := []Session{
+		session(1, 0), session(2, 0), session(1, 1),
+		session(-1, 1), session(-2, 1), session(-3, 1), session(-1, 0),
+	}
+
+	cm := bp.ConfusionMatrix(sessions, 2)
+	if want := [][]int{{2, 1}, {1, 3}}; !reflect.DeepEqual(cm, want) {
+		t.Fatalf("ConfusionMatrix = %v, want %v", cm, want)
+	}
+	if cm := bp.ConfusionMatrix(sessions, 3); cm[2][0]+cm[2][1]+cm[2][2]+cm[0][2]+cm[1][2] != 0 {
+		t.Errorf("class without an output node was counted: %v", cm)
+	}
+}
+
+func TestPrecisionRecallF1OfKnownMatrix(t *testing.T) {
+	cm := [][]int{
+		{5, 1, 0},
+		{3, 2, 0},
+		{0, 0, 0},
+	}
+	precision, recall, f1 := PrecisionRecallF1(cm)
+
+	want := map[string][2][]float64{
+		"precision": {precision, {5.0 / 8, 2.0 / 3, 0}},
+		"recall":    {recall, {5.0 / 6, 2.0 / 5, 0}},
+		"f1":        {f1, {2 * (5.0 / 8) * (5.0 / 6) / (5.0/8 + 5.0/6), 2 * (2.0 / 3) * (2.0 / 5) / (2.0/3 + 2.0/5), 0}},
+	}
+	for name, pair := range want {
+		got, expected := pair[0], pair[1]
+		for c := range expected {
+			if math.Abs(got[c]-expected[c]) > 1e-12 {
+				t.Errorf("%s[%d] = %v, want %v", name, c, got[c], expected[c])
+			}
+		}
+	}
+}
@@ -1,49 +1,242 @@
 package blueprint
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"math/rand"
 	"sort"
-	"time"
 )
 
+// EvolutionOption configures optional behaviour of EvolutionaryTrain.
+type EvolutionOption func(*evolutionConfig)
+
+// evolutionConfig holds the settings applied by EvolutionOptions.
+type evolutionConfig struct {
+	metrics           *MetricsBuffer
+	progress          ProgressFunc
+	spectralNormalize bool
+	fitnessWeights    [3]float64 // Weights of exact, generous and forgiveness accuracy, see WithFitnessWeights
+	selection         SelectionStrategy
+	tournamentSize    int // Individuals per tournament, 0 uses defaultTournamentSize
+}
+
+// SelectionStrategy chooses how EvolutionaryTrain picks the parents of the next generation. Every strategy fills
+// a pool of half the population, from which parents are drawn uniformly.
+type SelectionStrategy int
+
+const (
+	// TruncationSelection keeps the best half of the population. It is the default.
+	TruncationSelection SelectionStrategy = iota
+	// TournamentSelection fills the pool with the best of k individuals drawn at random, see WithTournamentSize.
+	// Weaker individuals win whenever no stronger one is drawn, which preserves diversity.
+	TournamentSelection
+	// RouletteSelection draws each pool member with probability proportional to its fitness.
+	RouletteSelection
+)
+
+// defaultTournamentSize is the tournament size of TournamentSelection when WithTournamentSize is not used.
+const defaultTournamentSize = 3
+
+// String returns the name of the strategy.
+func (s SelectionStrategy) String() string {
+	switch s {
+	case TruncationSelection:
+		return "truncation"
+	case TournamentSelection:
+		return "tournament"
+	case RouletteSelection:
+		return "roulette"
+	default:
+		return fmt.Sprintf("SelectionStrategy(%d)", int(s))
+	}
+}
+
+// WithSelection makes EvolutionaryTrain pick parents with the given strategy instead of truncation.
+func WithSelection(strategy SelectionStrategy) EvolutionOption {
+	return func(cfg *evolutionConfig) {
+		cfg.selection = strategy
+	}
+}
+
+// WithTournamentSize sets the number of individuals drawn per tournament of TournamentSelection. Sizes below 1
+// use the default of 3; larger sizes favour the best individuals more strongly.
+func WithTournamentSize(k int) EvolutionOption {
+	return func(cfg *evolutionConfig) {
+		cfg.tournamentSize = k
+	}
+}
+
+// WithMetricsBuffer makes EvolutionaryTrain push the best individual's metrics into b after every generation.
+func WithMetricsBuffer(b *MetricsBuffer) EvolutionOption {
+	return func(cfg *evolutionConfig) {
+		cfg.metrics = b
+	}
+}
+
+// WithProgressCallback makes EvolutionaryTrain call fn with the best individual's metrics after every generation.
+// Returning an error from fn stops training as cancellation does, see EvolutionaryTrainWithContext.
+func WithProgressCallback(fn ProgressFunc) EvolutionOption {
+	return func(cfg *evolutionConfig) {
+		cfg.progress = fn
+	}
+}
+
+// WithSpectralNormalization makes EvolutionaryTrain call SpectralNormalize on every new individual after its
+// weights and architecture are mutated. Individuals whose mutations introduced a cycle are left as they are.
+func WithSpectralNormalization() EvolutionOption {
+	return func(cfg *evolutionConfig) {
+		cfg.spectralNormalize = true
+	}
+}
+
+// WithFitnessWeights makes EvolutionaryTrain score individuals by a weighted average of their exact, generous
+// and forgiveness accuracy, in that order, instead of the plain average. Generous accuracy, which
+// EvaluateModelPerformance reports as a fraction from 0 to 1, is scaled to a percentage first, so all three
+// metrics range from 0 to 100 and the weights alone set their importance. The weights are normalized to sum to
+// 1; negative weights count as 0, and if none is positive the plain average is used.
+func WithFitnessWeights(weights [3]float64) EvolutionOption {
+	return func(cfg *evolutionConfig) {
+		for i, w := range weights {
+			cfg.fitnessWeights[i] = max(w, 0)
+		}
+	}
+}
+
+// selectParents fills the parent pool of num individuals with the configured selection strategy.
+func (cfg evolutionConfig) selectParents(population []*Blueprint, scores []float64, num int) []*Blueprint {
+	switch cfg.selection {
+	case TournamentSelection:
+		k := cfg.tournamentSize
+		if k < 1 {
+			k = defaultTournamentSize
+		}
+		return selectByTournament(population, scores, num, k)
+	case RouletteSelection:
+		return selectByRoulette(population, scores, num)
+	default:
+		return selectBestIndividuals(population, scores, num)
+	}
+}
+
+// fitness combines the three accuracies of an individual into its score, from 0 to 100. Generous accuracy is
+// given as a fraction and scaled to a percentage like the other two.
+func (cfg evolutionConfig) fitness(exact, generous, forgiveness float64) float64 {
+	generous *= 100
+	w := cfg.fitnessWeights
+	total := w[0] + w[1] + w[2]
+	if total <= 0 {
+		return (exact + generous + forgiveness) / 3.0
+	}
+	return (w[0]*exact + w[1]*generous + w[2]*forgiveness) / total
+}
+
+// normalize applies the per-individual normalization selected by the options.
+func (cfg evolutionConfig) normalize(individual *Blueprint) {
+	if !cfg.spectralNormalize {
+		return
+	}
+	if err := individual.SpectralNormalize(); err != nil {
+		individual.debugf("Skipping spectral normalization: %v", err)
+	}
+}
+
 // EvolutionaryTrain performs evolutionary training using neuroevolution.
-func (bp *Blueprint) EvolutionaryTrain(sessions []Session, populationSize int, generations int) {
-	rand.Seed(time.Now().UnixNano())
+func (bp *Blueprint) EvolutionaryTrain(sessions []Session, populationSize int, generations int, opts ...EvolutionOption) {
+	_ = bp.EvolutionaryTrainWithContext(context.Background(), sessions, populationSize, generations, opts...)
+}
+
+// EvolutionaryTrainWithContext is EvolutionaryTrain that stops once ctx is cancelled. Cancellation is checked
+// before every generation and between the evaluations of its individuals. The blueprint is then replaced by the
+// best individual evaluated so far, or left untouched if none was, and ctx.Err() is returned. A progress callback
+// that returns an error stops training the same way, and its error is returned. Training that runs to the end
+// returns nil.
+func (bp *Blueprint) EvolutionaryTrainWithContext(
+	ctx context.Context,
+	sessions []Session,
+	populationSize int,
+	generations int,
+	opts ...EvolutionOption,
+) error {
+	cfg := evolutionConfig{}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
 
 	// Initialize the population
 	population := make([]*Blueprint, populationSize)
 	for i := 0; i < populationSize; i++ {
 		// Clone the blueprint and apply random mutations to weights and architecture
-		individual := bp.Clone()
+		individual := bp.DeepCopy()
 		individual.RandomizeWeights()
 		individual.MutateArchitecture()
+		cfg.normalize(individual)
 		population[i] = individual
 	}
 
+	// Best individual evaluated so far, which replaces the blueprint when training stops early
+	var bestSoFar *Blueprint
+	bestSoFarScore := 0.0
+	stop := func(err error) error {
+		if bestSoFar != nil {
+			*bp = *bestSoFar
+		}
+		bp.infof("Evolutionary training stopped: %v. Best score so far: %v", err, bestSoFarScore)
+		return err
+	}
+
+	previousBestScore := 0.0
 	for gen := 1; gen <= generations; gen++ {
-		fmt.Printf("Generation %d\n", gen)
+		if err := ctx.Err(); err != nil {
+			return stop(err)
+		}
+		bp.infof("Generation %d", gen)
 
 		// Evaluate each individual
 		scores := make([]float64, populationSize)
+		generationBest := NASMetrics{Iteration: gen}
+		bestIndex, worstIndex := 0, 0
 		for i, individual := range population {
+			if err := ctx.Err(); err != nil {
+				return stop(err)
+			}
 			exactAccuracy, generousAccuracy, forgivenessAccuracy, _, _, _ := individual.EvaluateModelPerformance(sessions)
 			// Use a weighted sum of the accuracies as the fitness score
-			scores[i] = (exactAccuracy + generousAccuracy + forgivenessAccuracy) / 3.0
+			scores[i] = cfg.fitness(exactAccuracy, generousAccuracy, forgivenessAccuracy)
+			if bestSoFar == nil || scores[i] > bestSoFarScore {
+				bestSoFar, bestSoFarScore = individual, scores[i]
+			}
+			if scores[i] < scores[worstIndex] {
+				worstIndex = i
+			}
+			if i == 0 || scores[i] > scores[bestIndex] {
+				bestIndex = i
+				generationBest.ExactAccuracy = exactAccuracy
+				generationBest.GenerousAccuracy = generousAccuracy
+				generationBest.ForgivenessAccuracy = forgivenessAccuracy
+				generationBest.NeuronCount = len(individual.Neurons)
+			}
+		}
+		if populationSize > 0 {
+			generationBest.CandidateSpread = scores[bestIndex] - scores[worstIndex]
+			generationBest.Improved = gen == 1 || scores[bestIndex] > previousBestScore
+			previousBestScore = scores[bestIndex]
+			cfg.metrics.Push(generationBest)
+			if err := cfg.progress.call(gen, generationBest); err != nil {
+				return stop(err)
+			}
 		}
 
-		// Select the best individuals
-		bestIndividuals := selectBestIndividuals(population, scores, populationSize/2)
+		// Select the parents of the next generation
+		bestIndividuals := cfg.selectParents(population, scores, populationSize/2)
 
 		// Generate new population through crossover and mutation
 		newPopulation := make([]*Blueprint, populationSize)
 		for i := 0; i < populationSize; i++ {
-			parent1 := bestIndividuals[rand.Intn(len(bestIndividuals))]
-			parent2 := bestIndividuals[rand.Intn(len(bestIndividuals))]
+			parent1 := bestIndividuals[random.Intn(len(bestIndividuals))]
+			parent2 := bestIndividuals[random.Intn(len(bestIndividuals))]
 			child := parent1.Crossover(parent2)
 			child.MutateWeights()
 			child.MutateArchitecture()
+			cfg.normalize(child)
 			newPopulation[i] = child
 		}
 
@@ -55,7 +248,7 @@ func (bp *Blueprint) EvolutionaryTrain(sessions []Session, populationSize int, g
 	bestScore := 0.0
 	for _, individual := range population {
 		exactAccuracy, generousAccuracy, forgivenessAccuracy, _, _, _ := individual.EvaluateModelPerformance(sessions)
-		score := (exactAccuracy + generousAccuracy + forgivenessAccuracy) / 3.0
+		score := cfg.fitness(exactAccuracy, generousAccuracy, forgivenessAccuracy)
 		if score > bestScore {
 			bestScore = score
 			bestIndividual = individual
@@ -65,30 +258,38 @@ func (bp *Blueprint) EvolutionaryTrain(sessions []Session, populationSize int, g
 	// Update the original blueprint with the best found
 	*bp = *bestIndividual
 
-	fmt.Println("Evolutionary training completed. Best score:", bestScore)
+	bp.infof("Evolutionary training completed. Best score: %v", bestScore)
+	return nil
 }
 
-// RandomizeWeights initializes weights and biases with random values
+// RandomizeWeights initializes weights and biases with random values, leaving frozen neurons unchanged
 func (bp *Blueprint) RandomizeWeights() {
-	for _, neuron := range bp.Neurons {
-		// Skip input neurons
-		if neuron.Type == "input" {
+	for _, id := range bp.getAllNeuronIDs() {
+		neuron := bp.Neurons[id]
+		// Skip input and frozen neurons
+		if neuron.Type == "input" || bp.isFrozen(id) {
 			continue
 		}
 
 		// Randomize biases
-		neuron.Bias = rand.Float64()*2 - 1 // Random value between -1 and 1
+		neuron.Bias = random.Float64()*2 - 1 // Random value between -1 and 1
 
 		// Randomize connection weights
 		for _, conn := range neuron.Connections {
-			conn[1] = rand.Float64()*2 - 1 // Random value between -1 and 1
+			conn[1] = random.Float64()*2 - 1 // Random value between -1 and 1
+		}
+
+		// Randomize the recurrent weight for RNN neurons
+		if neuron.Type == "rnn" {
+			neuron.RecurrentWeight = random.Float64()*2 - 1
 		}
 
 		// Randomize gate weights for LSTM neurons
 		if neuron.Type == "lstm" && neuron.GateWeights != nil {
-			for gate, weights := range neuron.GateWeights {
+			for _, gate := range sortedGateNames(neuron.GateWeights) {
+				weights := neuron.GateWeights[gate]
 				for i := range weights {
-					weights[i] = rand.Float64()*2 - 1
+					weights[i] = random.Float64()*2 - 1
 				}
 				neuron.GateWeights[gate] = weights
 			}
@@ -96,33 +297,40 @@ func (bp *Blueprint) RandomizeWeights() {
 	}
 }
 
-// MutateWeights applies random perturbations to weights and biases
+// MutateWeights applies random perturbations to weights and biases, leaving frozen neurons unchanged
 func (bp *Blueprint) MutateWeights() {
 	mutationRate := 0.1 // Adjust as needed
-	for _, neuron := range bp.Neurons {
-		// Skip input neurons
-		if neuron.Type == "input" {
+	for _, id := range bp.getAllNeuronIDs() {
+		neuron := bp.Neurons[id]
+		// Skip input and frozen neurons
+		if neuron.Type == "input" || bp.isFrozen(id) {
 			continue
 		}
 
 		// Mutate biases
-		if rand.Float64() < mutationRate {
-			neuron.Bias += rand.NormFloat64() * 0.1
+		if random.Float64() < mutationRate {
+			neuron.Bias += random.NormFloat64() * 0.1
 		}
 
 		// Mutate connection weights
 		for _, conn := range neuron.Connections {
-			if rand.Float64() < mutationRate {
-				conn[1] += rand.NormFloat64() * 0.1
+			if random.Float64() < mutationRate {
+				conn[1] += random.NormFloat64() * 0.1
 			}
 		}
 
+		// Mutate the recurrent weight for RNN neurons
+		if neuron.Type == "rnn" && random.Float64() < mutationRate {
+			neuron.RecurrentWeight = neuron.recurrentWeight() + random.NormFloat64()*0.1
+		}
+
 		// Mutate gate weights for LSTM neurons
 		if neuron.Type == "lstm" && neuron.GateWeights != nil {
-			for gate, weights := range neuron.GateWeights {
+			for _, gate := range sortedGateNames(neuron.GateWeights) {
+				weights := neuron.GateWeights[gate]
 				for i := range weights {
-					if rand.Float64() < mutationRate {
-						weights[i] += rand.NormFloat64() * 0.1
+					if random.Float64() < mutationRate {
+						weights[i] += random.NormFloat64() * 0.1
 					}
 				}
 				neuron.GateWeights[gate] = weights
@@ -131,88 +339,162 @@ func (bp *Blueprint) MutateWeights() {
 	}
 }
 
-// MutateArchitecture randomly adds or removes neurons
+// sortedGateNames returns the gate names of an LSTM neuron in a fixed order.
+func sortedGateNames(gateWeights map[string][]float64) []string {
+	gates := make([]string, 0, len(gateWeights))
+	for gate := range gateWeights {
+		gates = append(gates, gate)
+	}
+	sort.Strings(gates)
+	return gates
+}
+
+// MutateArchitecture randomly adds or removes neurons. Frozen neurons are never removed.
 func (bp *Blueprint) MutateArchitecture() {
 	mutationRate := 0.05 // Adjust as needed
 
 	// Possible neuron types to add
-	neuronTypes := []string{"dense", "rnn", "lstm", "cnn", "dropout", "batch_norm", "attention", "nca"}
+	neuronTypes := []string{"dense", "rnn", "lstm", "cnn", "maxpool", "avgpool", "dropout", "batch_norm", "attention", "nca"}
 
-	if rand.Float64() < mutationRate {
+	if random.Float64() < mutationRate {
 		// Add a new neuron
-		neuronType := neuronTypes[rand.Intn(len(neuronTypes))]
+		neuronType := neuronTypes[random.Intn(len(neuronTypes))]
 		err := bp.InsertNeuronOfTypeBetweenInputsAndOutputs(neuronType)
 		if err != nil {
-			fmt.Printf("Error adding neuron of type '%s': %v\n", neuronType, err)
+			bp.warnf("Error adding neuron of type '%s': %v", neuronType, err)
 		}
 	}
 
 	// Optionally remove a neuron
-	if rand.Float64() < mutationRate && len(bp.Neurons) > len(bp.InputNodes)+len(bp.OutputNodes) {
-		// Remove a random neuron that's not an input or output
+	if random.Float64() < mutationRate && len(bp.Neurons) > len(bp.InputNodes)+len(bp.OutputNodes) {
+		// Remove a random neuron that's not an input, output or frozen
 		neuronIDs := []int{}
-		for id := range bp.Neurons {
-			if !bp.isInputNode(id) && !bp.isOutputNode(id) {
+		for _, id := range bp.getAllNeuronIDs() {
+			if !bp.isInputNode(id) && !bp.isOutputNode(id) && !bp.isFrozen(id) {
 				neuronIDs = append(neuronIDs, id)
 			}
 		}
 		if len(neuronIDs) > 0 {
-			neuronIDToRemove := neuronIDs[rand.Intn(len(neuronIDs))]
+			neuronIDToRemove := neuronIDs[random.Intn(len(neuronIDs))]
 			bp.RemoveNeuron(neuronIDToRemove)
-			fmt.Printf("Removed Neuron with ID %d from the architecture.\n", neuronIDToRemove)
+			bp.infof("Removed Neuron with ID %d from the architecture.", neuronIDToRemove)
 		}
 	}
 }
 
-// RemoveNeuron removes a neuron and its associated connections
+// RemoveNeuron removes a neuron and every reference to it. Its incoming connections are stored on the neuron
+// and go with it; its outgoing connections are the entries with the neuron as source in other neurons'
+// connection lists, in either storage precision, and are deleted together with the matching LSTM gate weights.
+// The ID is also removed from InputNodes, OutputNodes and the neighborhoods of NCA neurons.
 func (bp *Blueprint) RemoveNeuron(neuronID int) {
 	delete(bp.Neurons, neuronID)
+	bp.InputNodes = removeID(bp.InputNodes, neuronID)
+	bp.OutputNodes = removeID(bp.OutputNodes, neuronID)
+	bp.invalidateCompiled()
 
-	// Remove connections to and from this neuron
+	// Remove connections from this neuron
 	for _, neuron := range bp.Neurons {
-		newConnections := [][]float64{}
-		for _, conn := range neuron.Connections {
-			sourceID := int(conn[0])
-			if sourceID != neuronID {
-				newConnections = append(newConnections, conn)
+		kept := make([]int, 0, neuron.numConnections())
+		for i := 0; i < neuron.numConnections(); i++ {
+			if sourceID, _ := neuron.connection(i); sourceID != neuronID {
+				kept = append(kept, i)
 			}
 		}
-		neuron.Connections = newConnections
+		if len(kept) < neuron.numConnections() {
+			neuron.keepConnections(kept)
+		}
+		if neuron.NeighborhoodIDs != nil {
+			neuron.NeighborhoodIDs = removeID(neuron.NeighborhoodIDs, neuronID)
+		}
 	}
 }
 
-// Crossover combines two parent blueprints to create a child blueprint
+// removeID returns ids without any occurrence of id, in a new slice.
+func removeID(ids []int, id int) []int {
+	kept := []int{}
+	for _, other := range ids {
+		if other != id {
+			kept = append(kept, other)
+		}
+	}
+	return kept
+}
+
+// Crossover combines two parent blueprints into a child. Every neuron ID of either parent is inherited from a
+// parent chosen at random, so a neuron only one parent has is kept half of the time; input and output neurons
+// are always kept. The child's input and output lists are bp's followed by the IDs only other has. Connections and
+// NCA neighbourhoods that refer to a neuron the child lacks are dropped, along with the matching LSTM gate weights.
 func (bp *Blueprint) Crossover(other *Blueprint) *Blueprint {
-	child := bp.Clone()
+	child := bp.DeepCopy()
+	child.InputNodes = unionIDs(bp.InputNodes, other.InputNodes)
+	child.OutputNodes = unionIDs(bp.OutputNodes, other.OutputNodes)
 
 	// For each neuron, randomly choose from parent1 or parent2
-	for neuronID := range child.Neurons {
-		if rand.Float64() < 0.5 {
-			if neuron, exists := other.Neurons[neuronID]; exists {
-				// Serialize the neuron to JSON
-				data, err := json.Marshal(neuron)
-				if err != nil {
-					fmt.Printf("Error serializing neuron %d: %v\n", neuronID, err)
-					continue
-				}
+	for _, neuronID := range unionIDs(bp.getAllNeuronIDs(), other.getAllNeuronIDs()) {
+		_, inParent1 := bp.Neurons[neuronID]
+		neuron, inParent2 := other.Neurons[neuronID]
+		required := child.isInputNode(neuronID) || child.isOutputNode(neuronID)
+		fromParent2 := random.Float64() < 0.5
+		switch {
+		case inParent2 && (fromParent2 || !inParent1):
+			if fromParent2 || required {
+				child.Neurons[neuronID] = neuron.copy()
+			}
+		case inParent1 && !inParent2 && fromParent2 && !required:
+			delete(child.Neurons, neuronID)
+		}
+	}
+	child.dropDanglingReferences()
 
-				// Deserialize back into a new Neuron object
-				var newNeuron Neuron
-				err = json.Unmarshal(data, &newNeuron)
-				if err != nil {
-					fmt.Printf("Error deserializing neuron %d: %v\n", neuronID, err)
-					continue
-				}
+	return child
+}
 
-				child.Neurons[neuronID] = &newNeuron
+// dropDanglingReferences removes the connections and NCA neighbours that refer to neurons the blueprint does not
+// have. LSTM gate weights are kept aligned with the remaining connections.
+func (bp *Blueprint) dropDanglingReferences() {
+	exists := func(id int) bool {
+		if _, ok := bp.Neurons[id]; ok {
+			return true
+		}
+		_, ok := bp.QuantumNeurons[id]
+		return ok
+	}
+	for _, neuron := range bp.Neurons {
+		kept := make([]int, 0, neuron.numConnections())
+		for i := 0; i < neuron.numConnections(); i++ {
+			if sourceID, _ := neuron.connection(i); exists(sourceID) {
+				kept = append(kept, i)
 			}
 		}
+		if len(kept) < neuron.numConnections() {
+			neuron.keepConnections(kept)
+		}
+		if neuron.NeighborhoodIDs != nil {
+			neighbours := []int{}
+			for _, id := range neuron.NeighborhoodIDs {
+				if exists(id) {
+					neighbours = append(neighbours, id)
+				}
+			}
+			neuron.NeighborhoodIDs = neighbours
+		}
 	}
+	bp.invalidateCompiled()
+}
 
-	// Reinitialize activation functions for neurons if needed
-	// Since neurons use activation function names, ensure the Blueprint's activation map is available
-
-	return child
+// unionIDs returns the IDs of a followed by the IDs of b that a lacks, without duplicates.
+func unionIDs(a, b []int) []int {
+	seen := make(map[int]bool, len(a)+len(b))
+	union := []int{}
+	for _, ids := range [][]int{a, b} {
+		for _, id := range ids {
+			if !seen[id] {
+				seen[id] = true
+				union = append(union, id)
+			}
+		}
+	}
+	return union
 }
 
 // Helper function to select the best individuals based on scores
@@ -237,6 +519,50 @@ func selectBestIndividuals(population []*Blueprint, scores []float64, num int) [
 	return bestIndividuals
 }
 
+// selectByTournament selects num individuals, each the best of k drawn at random with replacement.
+func selectByTournament(population []*Blueprint, scores []float64, num, k int) []*Blueprint {
+	selected := make([]*Blueprint, num)
+	for i := range selected {
+		winner := random.Intn(len(population))
+		for j := 1; j < k; j++ {
+			if contender := random.Intn(len(population)); scores[contender] > scores[winner] {
+				winner = contender
+			}
+		}
+		selected[i] = population[winner]
+	}
+	return selected
+}
+
+// selectByRoulette selects num individuals with replacement, each with probability proportional to its score.
+// Negative scores count as 0, and if no score is positive every individual is equally likely.
+func selectByRoulette(population []*Blueprint, scores []float64, num int) []*Blueprint {
+	total := 0.0
+	for _, score := range scores {
+		total += max(score, 0)
+	}
+	selected := make([]*Blueprint, num)
+	for i := range selected {
+		if total <= 0 {
+			selected[i] = population[random.Intn(len(population))]
+			continue
+		}
+		target := random.Float64() * total
+		chosen := -1
+		for j, score := range scores {
+			if score <= 0 {
+				continue
+			}
+			chosen = j // Rounding may leave target just above 0 after the last positive score
+			if target -= score; target < 0 {
+				break
+			}
+		}
+		selected[i] = population[chosen]
+	}
+	return selected
+}
+
 func (bp *Blueprint) isOutputNode(neuronID int) bool {
 	for _, id := range bp.OutputNodes {
 		if id == neuronID {
@@ -0,0 +1,201 @@
+package blueprint
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestRemoveOutputNeuronDropsEveryReference(t *testing.T) {
+	bp := NewBlueprint()
+	bp.AddInputNeurons([]int{1, 2})
+	bp.AddOutputNeurons([]int{3, 4}, "linear")
+	bp.Neurons[3].Connections = [][]float64{{1, 0.5}, {2, 0.25}}
+	// Neuron 5 reads from output 3 between two other connections, and so do its LSTM gates
+	bp.Neurons[5] = &Neuron{
+		ID: 5, Type: "lstm", Activation: "tanh",
+		Connections: [][]float64{{1, 0.1}, {3, 0.2}, {2, 0.3}},
+		GateWeights: map[string][]float64{
+			"input": {1, 2, 3}, "forget": {4, 5, 6}, "output": {7, 8, 9}, "cell": {10, 11, 12},
+		},
+	}
+	bp.Neurons[4].Connections = [][]float64{{5, 1}, {3, -1}}
+
+	bp.RemoveNeuron(3)
+
+	if slices.Contains(bp.OutputNodes, 3) {
+		t.Errorf("OutputNodes still lists the removed neuron: %v", bp.OutputNodes)
+	}
+	if _, exists := bp.Neurons[3]; exists {
+		t.Error("the removed neuron is still in Neurons")
+	}
+	for id, neuron := range bp.Neurons {
+		for i := 0; i < neuron.numConnections(); i++ {
+			if source, _ := neuron.connection(i); source == 3 {
+				t.Errorf("neuron %d still has a connection from the removed neuron", id)
+			}
+		}
+	}
+
+	lstm := bp.Neurons[5]
+	if got := lstm.Connections; !slices.EqualFunc(got, [][]float64{{1, 0.1}, {2, 0.3}}, slices.Equal) {
+		t.Errorf("LSTM neuron kept connections %v", got)
+	}
+	want := map[string][]float64{"input": {1, 3}, "forget": {4, 6}, "output": {7, 9}, "cell": {10, 12}}
+	for gate, weights := range want {
+		if !slices.Equal(lstm.GateWeights[gate], weights) {
+			t.Errorf("LSTM %s gate weights are %v, want %v", gate, lstm.GateWeights[gate], weights)
+		}
+	}
+	if errs := bp.Validate(); len(errs) != 0 {
+		t.Errorf("Validate reported problems after RemoveNeuron: %v", errs)
+	}
+}
+
+func TestExactFitnessWeightSelectsExactChampion(t *testing.T) {
+	// Accuracies as returned by EvaluateModelPerformance: exact and forgiveness in percent, generous as a fraction
+	type accuracies struct{ exact, generous, forgiveness float64 }
+	candidates := []accuracies{
+		{exact: 50, generous: 0.99, forgiveness: 100},
+		{exact: 100, generous: 0.2, forgiveness: 0},
+		{exact: 75, generous: 0.5, forgiveness: 50},
+	}
+	population := make([]*Blueprint, len(candidates))
+	for i := range population {
+		population[i] = NewBlueprint()
+	}
+
+	champion := func(opts ...EvolutionOption) int {
+		cfg := evolutionConfig{}
+		for _, opt := range opts {
+			opt(&cfg)
+		}
+		scores := make([]float64, len(candidates))
+		for i, c := range candidates {
+			scores[i] = cfg.fitness(c.exact, c.generous, c.forgiveness)
+		}
+		return slices.Index(population, selectBestIndividuals(population, scores, 1)[0])
+	}
+
+	if got := champion(WithFitnessWeights([3]float64{1, 0, 0})); got != 1 {
+		t.Errorf("exact-only weights selected candidate %d, want the exact champion 1", got)
+	}
+	if got := champion(); got != 0 {
+		t.Errorf("the plain average selected candidate %d, want 0", got)
+	}
+	if got := champion(WithFitnessWeights([3]float64{0, 1, 0})); got != 0 {
+		t.Errorf("generous-only weights selected candidate %d, want 0", got)
+	}
+
+	cfg := evolutionConfig{}
+	if got := cfg.fitness(100, 1, 100); got != 100 {
+		t.Errorf("perfect accuracies scored %v, want 100", got)
+	}
+}
+
+func TestTournamentSelectionPicksNonTopIndividuals(t *testing.T) {
+	randomSource.Seed(7)
+	const size = 10
+	population := make([]*Blueprint, size)
+	scores := make([]float64, size)
+	rank := map[*Blueprint]int{}
+	for i := range population {
+		population[i] = NewBlueprint()
+		scores[i] = float64(i) * 10
+		rank[population[i]] = i
+	}
+	// selectedRanks runs a selection many times and counts how often each individual joins the pool
+	selectedRanks := func(opts ...EvolutionOption) []int {
+		cfg := evolutionConfig{}
+		for _, opt := range opts {
+			opt(&cfg)
+		}
+		counts := make([]int, size)
+		for trial := 0; trial < 200; trial++ {
+			pool := cfg.selectParents(population, scores, size/2)
+			if len(pool) != size/2 {
+				t.Fatalf("pool has %d individuals, want %d", len(pool), size/2)
+			}
+			for _, individual := range pool {
+				counts[rank[individual]]++
+			}
+		}
+		return counts
+	}
+
+	truncation := selectedRanks()
+	for i, count := range truncation {
+		if (i >= size/2) != (count > 0) {
+			t.Fatalf("truncation selected individuals %v times by rank, want only the top half", truncation)
+		}
+	}
+
+	tournament := selectedRanks(WithSelection(TournamentSelection), WithTournamentSize(3))
+	bottomHalf := 0
+	for _, count := range tournament[:size/2] {
+		bottomHalf += count
+	}
+	if bottomHalf == 0 {
+		t.Errorf("tournament selection never picked an individual from the bottom half: %v", tournament)
+	}
+	if tournament[size-1] <= tournament[0] {
+		t.Errorf("tournament selection picked the best individual %d times and the worst %d times, want the best more often",
+			tournament[size-1], tournament[0])
+	}
+
+	// Larger tournaments favour the best individuals more strongly
+	strict := selectedRanks(WithSelection(TournamentSelection), WithTournamentSize(size*3))
+	if strict[size-1] <= tournament[size-1] {
+		t.Errorf("tournaments of %d picked the best individual %d times, want more than the %d of tournaments of 3",
+			size*3, strict[size-1], tournament[size-1])
+	}
+}
+
+func TestCrossoverOfDifferentlySizedParentsIsRunnable(t *testing.T) {
+	small := NewDenseMLP([]int{2, 3, 2}, "relu")    // Hidden 3-5, outputs 6 and 7
+	large := NewDenseMLP([]int{2, 4, 3, 2}, "tanh") // Hidden 3-9, outputs 10 and 11
+	session := Session{InputVariables: map[int]float64{1: 0.5, 2: -0.25}, Timesteps: 1}
+
+	sawLargeOnlyNeuron := false
+	for seed := int64(0); seed < 20; seed++ {
+		randomSource.Seed(seed)
+		for _, parents := range [][2]*Blueprint{{small, large}, {large, small}} {
+			child := parents[0].Crossover(parents[1])
+
+			if errs := child.Validate(); len(errs) > 0 {
+				t.Fatalf("seed %d: child is invalid: %v", seed, errs)
+			}
+			if !slices.Equal(child.InputNodes, []int{1, 2}) {
+				t.Errorf("seed %d: InputNodes = %v, want [1 2]", seed, child.InputNodes)
+			}
+			for _, id := range []int{6, 7, 10, 11} {
+				if !child.isOutputNode(id) {
+					t.Errorf("seed %d: output %d of a parent is not an output of the child %v", seed, id, child.OutputNodes)
+				}
+			}
+			for id, neuron := range child.Neurons {
+				for _, conn := range neuron.Connections {
+					if _, exists := child.Neurons[int(conn[0])]; !exists {
+						t.Errorf("seed %d: neuron %d has a dangling connection from %d", seed, id, int(conn[0]))
+					}
+				}
+			}
+			if _, exists := child.Neurons[8]; exists {
+				sawLargeOnlyNeuron = true
+			}
+
+			outputs := child.Predict(session.InputVariables, session.Timesteps)
+			for _, id := range child.OutputNodes {
+				if v, ok := outputs[id]; !ok || math.IsNaN(v) || math.IsInf(v, 0) {
+					t.Errorf("seed %d: output %d = %v (present %v), want a finite value", seed, id, v, ok)
+				}
+			}
+		}
+	}
+	if !sawLargeOnlyNeuron {
+		t.Error("no child inherited neuron 8, which only the larger parent has")
+	}
+	if len(small.Neurons) != 7 || len(large.Neurons) != 11 {
+		t.Errorf("crossover changed the parents: %d and %d neurons", len(small.Neurons), len(large.Neurons))
+	}
+}
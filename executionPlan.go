@@ -0,0 +1,184 @@
+package blueprint
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+)
+
+// planOp evaluates a single dense neuron inside an ExecutionPlan.
+type planOp struct {
+	slot       int            // Value slot written by the op
+	neuron     *Neuron        // Source of the bias and the live connection weights
+	sources    []int          // Value slot of each connection source (-1 if the source does not exist)
+	activation ActivationFunc // Activation resolved at compile time
+}
+
+// ExecutionPlan is a flat, dependency-ordered forward pass compiled from a Blueprint.
+// Neuron values live in plan-local slots instead of on the Neuron structs, so running a
+// plan never touches the Blueprint's state. Weights and biases are read from the
+// neurons on every run, so in-place weight updates are picked up without recompiling.
+type ExecutionPlan struct {
+	inputSlots  []int // Slot of each entry in InputNodes
+	outputSlots []int // Slot of each entry in OutputNodes
+	ops         []planOp
+	numSlots    int
+	values      []float64 // Scratch buffer used by Run
+}
+
+// Compile produces an ExecutionPlan for the blueprint, or returns the cached plan if the
+// topology has not changed since the last call. The plan is invalidated by structural
+// mutations made through Blueprint methods; edits made directly to the Neurons map
+// require calling InvalidatePlan. Only dense neurons are supported and the graph must be acyclic. Blueprints
+// with quantum neurons cannot be compiled, since Forward feeds their measurements into classical neurons.
+func (bp *Blueprint) Compile() (*ExecutionPlan, error) {
+	if bp.compiledPlan != nil {
+		return bp.compiledPlan, nil
+	}
+	if len(bp.QuantumNeurons) > 0 {
+		return nil, fmt.Errorf("quantum neurons are only processed by Forward")
+	}
+
+	layers, err := bp.ComputeLayers()
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &ExecutionPlan{}
+	slots := make(map[int]int, len(bp.Neurons)+len(bp.InputNodes))
+	slotFor := func(id int) int {
+		if slot, exists := slots[id]; exists {
+			return slot
+		}
+		slots[id] = plan.numSlots
+		plan.numSlots++
+		return slots[id]
+	}
+
+	for _, id := range bp.InputNodes {
+		plan.inputSlots = append(plan.inputSlots, slotFor(id))
+	}
+
+	for _, layer := range layers[1:] {
+		for _, id := range layer {
+			neuron := bp.Neurons[id]
+			if neuron.Type == "input" {
+				slotFor(id)
+				continue
+			}
+			if !isDenseNeuronType(neuron.Type) {
+				return nil, fmt.Errorf("neuron %d has unsupported type '%s'", id, neuron.Type)
+			}
+
+			op := planOp{
+				neuron:     neuron,
+				sources:    make([]int, neuron.numConnections()),
+				activation: Linear,
+			}
+			if actFunc, exists := bp.ScalarActivationMap[neuron.Activation]; exists {
+				op.activation = actFunc
+			}
+			for i := range op.sources {
+				sourceID, _ := neuron.connection(i)
+				if _, exists := bp.Neurons[sourceID]; !exists {
+					op.sources[i] = -1
+					continue
+				}
+				op.sources[i] = slotFor(sourceID)
+			}
+			op.slot = slotFor(id)
+			plan.ops = append(plan.ops, op)
+		}
+	}
+
+	for _, id := range bp.OutputNodes {
+		if _, exists := bp.Neurons[id]; !exists {
+			return nil, fmt.Errorf("output neuron %d does not exist", id)
+		}
+		plan.outputSlots = append(plan.outputSlots, slotFor(id))
+	}
+
+	plan.values = make([]float64, plan.numSlots)
+	bp.compiledPlan = plan
+	return plan, nil
+}
+
+// InvalidatePlan discards the cached ExecutionPlan and layer matrices.
+// Call it after editing the Neurons map or connection lists directly.
+func (bp *Blueprint) InvalidatePlan() {
+	bp.invalidateCompiled()
+}
+
+// Run executes the plan for one input vector and returns the softmaxed outputs.
+// Inputs are matched positionally to InputNodes and outputs follow the order of OutputNodes;
+// missing trailing inputs are treated as zero. Run reuses an internal scratch buffer and
+// must not be called concurrently on the same plan.
+func (p *ExecutionPlan) Run(inputs []float64) []float64 {
+	return p.run(inputs, p.values)
+}
+
+// RunBatch executes the plan for every input vector in parallel and returns the outputs in input order.
+// Each worker owns its own scratch buffer, so the batch can be processed concurrently without
+// touching the Blueprint. A non-positive worker count uses one worker per CPU core.
+func (p *ExecutionPlan) RunBatch(inputs [][]float64, workers int) [][]float64 {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	if workers > len(inputs) {
+		workers = len(inputs)
+	}
+
+	results := make([][]float64, len(inputs))
+	indexCh := make(chan int, len(inputs))
+	for i := range inputs {
+		indexCh <- i
+	}
+	close(indexCh)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			values := make([]float64, p.numSlots)
+			for i := range indexCh {
+				results[i] = p.run(inputs[i], values)
+			}
+		}()
+	}
+	wg.Wait()
+
+	return results
+}
+
+// run executes the plan using the given scratch buffer for neuron values.
+func (p *ExecutionPlan) run(inputs []float64, values []float64) []float64 {
+	for i := range values {
+		values[i] = 0
+	}
+	for i, slot := range p.inputSlots {
+		if i < len(inputs) {
+			values[slot] = inputs[i]
+		}
+	}
+
+	for _, op := range p.ops {
+		sum := op.neuron.Bias
+		for i, source := range op.sources {
+			if source >= 0 {
+				_, weight := op.neuron.connection(i)
+				sum += values[source] * weight
+			}
+		}
+		values[op.slot] = op.activation(sum)
+	}
+
+	outputs := make([]float64, len(p.outputSlots))
+	if len(outputs) == 0 {
+		return outputs
+	}
+	for i, slot := range p.outputSlots {
+		outputs[i] = values[slot]
+	}
+	return Softmax(outputs)
+}
@@ -0,0 +1,122 @@
+package blueprint
+
+// activationState holds the values, and the LSTM cell states, of a forward pass that runs outside the neurons,
+// keyed by neuron ID.
+type activationState struct {
+	values     map[int]float64
+	cellStates map[int]float64
+}
+
+// newActivationState copies the current values and cell states of every neuron, so a pass over the state starts
+// where Forward would.
+func (bp *Blueprint) newActivationState() *activationState {
+	state := &activationState{
+		values:     make(map[int]float64, len(bp.Neurons)),
+		cellStates: make(map[int]float64),
+	}
+	for id, neuron := range bp.Neurons {
+		state.values[id] = neuron.Value
+		if neuron.Type == "lstm" {
+			state.cellStates[id] = neuron.CellState
+		}
+	}
+	return state
+}
+
+// ForwardState runs the network like Forward and returns the softmaxed output values, but keeps every value in
+// a state local to the call instead of writing it to the neurons. The pass starts from the neurons' current
+// values, as Forward does, and the blueprint is only read, so any number of goroutines may call ForwardState on
+// the same blueprint at once as long as none of them modifies it. Batch normalization uses the running
+// statistics without updating them, even in training mode. Quantum neurons are not processed, since measuring
+// them changes them; classical neurons and quantum output nodes use their last measured values instead.
+func (bp *Blueprint) ForwardState(inputs map[int]float64, timesteps int) map[int]float64 {
+	state := bp.newActivationState()
+	for id, value := range inputs {
+		if _, exists := bp.Neurons[id]; exists {
+			state.values[id] = value
+		}
+	}
+
+	neuronIDs := bp.getAllNeuronIDs()
+	for t := 0; t < timesteps; t++ {
+		bp.stateTimestep(state, neuronIDs)
+	}
+
+	outputIDs := []int{}
+	outputValues := []float64{}
+	for _, id := range bp.OutputNodes {
+		if _, exists := bp.Neurons[id]; exists {
+			outputIDs = append(outputIDs, id)
+			outputValues = append(outputValues, state.values[id])
+		} else if quantumNeuron, exists := bp.QuantumNeurons[id]; exists {
+			outputIDs = append(outputIDs, id)
+			outputValues = append(outputValues, quantumNeuron.measuredValue())
+		}
+	}
+	outputs := make(map[int]float64, len(outputIDs))
+	for i, value := range Softmax(outputValues) {
+		outputs[outputIDs[i]] = value
+	}
+	return outputs
+}
+
+// stateTimestep is forwardTimestep over an activationState: it computes every non-input neuron once, in the
+// order of neuronIDs, then applies StateClamp to the recurrent neurons.
+func (bp *Blueprint) stateTimestep(state *activationState, neuronIDs []int) {
+	valueOf := func(neuron *Neuron) float64 { return state.values[neuron.ID] }
+	for _, id := range neuronIDs {
+		neuron := bp.Neurons[id]
+		if neuron.Type == "input" {
+			continue
+		}
+
+		// Gather inputs from connected neurons
+		inputValues := make([]float64, 0, neuron.numConnections())
+		for i := 0; i < neuron.numConnections(); i++ {
+			sourceID, weight := neuron.connection(i)
+			if _, exists := bp.Neurons[sourceID]; exists {
+				inputValues = append(inputValues, state.values[sourceID]*weight)
+			} else if quantumNeuron, exists := bp.QuantumNeurons[sourceID]; exists {
+				inputValues = append(inputValues, quantumNeuron.measuredValue()*weight)
+			}
+		}
+
+		switch neuron.Type {
+		case "nca":
+			if value, ok := bp.ncaValue(neuron, valueOf); ok {
+				state.values[id] = value
+			}
+		case "rnn":
+			state.values[id] = bp.rnnValue(neuron, inputValues, state.values[id])
+		case "lstm":
+			state.values[id], state.cellStates[id] = lstmValue(neuron, inputValues, state.cellStates[id])
+		case "cnn":
+			state.values[id] = bp.cnnValue(neuron, inputValues)
+		case "maxpool", "avgpool":
+			state.values[id] = poolValue(neuron, inputValues)
+		case "dropout":
+			state.values[id] = bp.dropoutValue(neuron, bp.denseValue(neuron, inputValues))
+		case "batch_norm":
+			state.values[id] = bp.denseValue(neuron, inputValues)
+			if neuron.BatchNormParams != nil {
+				state.values[id] = batchNormValue(neuron.BatchNormParams, state.values[id])
+			}
+		case "attention":
+			// Attention neurons keep their value, as in ProcessNeuron
+		default:
+			state.values[id] = bp.denseValue(neuron, inputValues)
+		}
+	}
+
+	if bp.StateClamp > 0 {
+		for id, neuron := range bp.Neurons {
+			if !isRecurrentNeuron(neuron) {
+				continue
+			}
+			state.values[id] = clampState(state.values[id], bp.StateClamp)
+			if neuron.Type == "lstm" {
+				state.cellStates[id] = clampState(state.cellStates[id], bp.StateClamp)
+			}
+		}
+	}
+}
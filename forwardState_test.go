@@ -0,0 +1,60 @@
+package blueprint
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestForwardStateFromManyGoroutines(t *testing.T) {
+	randomSource.Seed(1)
+	bp := NewBlueprint()
+	bp.AddInputNeurons([]int{1, 2})
+	bp.Neurons[3] = &Neuron{ID: 3, Type: "rnn", Activation: "tanh", Connections: [][]float64{{1, 0.5}, {2, -0.3}}}
+	bp.Neurons[4] = &Neuron{ID: 4, Type: "lstm", Activation: "tanh", Connections: [][]float64{{1, 0.2}, {3, 0.7}}}
+	bp.initializeLSTMWeights(bp.Neurons[4])
+	bp.Neurons[5] = &Neuron{ID: 5, Type: "batch_norm", Activation: "linear", Connections: [][]float64{{3, 1}},
+		BatchNormParams: &BatchNormParams{Gamma: 1, Var: 1, Momentum: defaultBatchNormMomentum}}
+	bp.Neurons[6] = &Neuron{ID: 6, Type: "dropout", Activation: "relu", DropoutRate: 0.5, Connections: [][]float64{{4, 1}, {5, 1}}}
+	bp.AddOutputNeurons([]int{7, 8}, "linear")
+	bp.Neurons[7].Connections = [][]float64{{4, 1}, {6, -1}}
+	bp.Neurons[8].Connections = [][]float64{{5, 0.5}, {6, 2}}
+
+	inputs := []map[int]float64{{1: 1, 2: 0}, {1: -0.5, 2: 2}, {1: 0.3, 2: 0.3}}
+	want := make([]map[int]float64, len(inputs))
+	for i, in := range inputs {
+		want[i] = bp.ForwardState(in, 3)
+	}
+	before := bp.Hash()
+
+	const goroutines = 16
+	var wg sync.WaitGroup
+	errs := make(chan string, goroutines)
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for iter := 0; iter < 50; iter++ {
+				i := (g + iter) % len(inputs)
+				if got := bp.ForwardState(inputs[i], 3); !reflect.DeepEqual(got, want[i]) {
+					errs <- "concurrent ForwardState returned different outputs"
+					return
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+
+	if after := bp.Hash(); after != before {
+		t.Errorf("ForwardState changed the model")
+	}
+	for id, neuron := range bp.Neurons {
+		if neuron.Value != 0 || neuron.CellState != 0 {
+			t.Errorf("ForwardState wrote state into neuron %d: value %v, cell state %v", id, neuron.Value, neuron.CellState)
+		}
+	}
+}
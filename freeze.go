@@ -0,0 +1,40 @@
+package blueprint
+
+import "fmt"
+
+// FreezeNeurons marks the given neurons as frozen, so training leaves their incoming weights and bias unchanged,
+// for example to fine-tune only part of a pretrained network. Every trainer honours the flag: TrainBackprop,
+// AdamWeightUpdate, TrainCMAES, HillClimbWeightUpdate, the evolutionary and single-item searches,
+// TargetedMicroRefinement, RandomizeWeights and InitializeWeights; neurons inserted by NAS do not connect into a
+// frozen neuron either. The flag is saved with the model. An error is returned, and no neuron is frozen, when
+// any ID does not exist.
+func (bp *Blueprint) FreezeNeurons(ids ...int) error {
+	for _, id := range ids {
+		if _, ok := bp.Neurons[id]; !ok {
+			return fmt.Errorf("neuron ID %d does not exist", id)
+		}
+	}
+	for _, id := range ids {
+		bp.Neurons[id].Frozen = true
+	}
+	bp.debugf("Froze %d neurons.", len(ids))
+	return nil
+}
+
+// isFrozen reports whether training must leave the neuron's incoming weights and bias unchanged, because it was
+// frozen with FreezeNeurons or NAS keeps it fixed through NASConfig.MutableNeuronIDs.
+func (bp *Blueprint) isFrozen(id int) bool {
+	if bp.frozenNeurons[id] {
+		return true
+	}
+	neuron, ok := bp.Neurons[id]
+	return ok && neuron.Frozen
+}
+
+// UnfreezeAll clears the frozen flag of every neuron. Neurons NAS keeps fixed through
+// NASConfig.MutableNeuronIDs are not affected.
+func (bp *Blueprint) UnfreezeAll() {
+	for _, neuron := range bp.Neurons {
+		neuron.Frozen = false
+	}
+}
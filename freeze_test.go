@@ -0,0 +1,114 @@
+package blueprint
+
+import (
+	"slices"
+	"testing"
+)
+
+// freezeTestBlueprint returns a 2-3-2 network with every neuron connected to the previous layer.
+func freezeTestBlueprint() *Blueprint {
+	bp := NewBlueprint()
+	bp.AddInputNeurons([]int{1, 2})
+	for id := 3; id <= 5; id++ {
+		bp.Neurons[id] = &Neuron{ID: id, Type: "dense", Activation: "tanh", Bias: 0.1 * float64(id),
+			Connections: [][]float64{{1, 0.2 * float64(id)}, {2, -0.1 * float64(id)}}}
+	}
+	bp.AddOutputNeurons([]int{6, 7}, "linear")
+	for _, id := range []int{6, 7} {
+		bp.Neurons[id].Connections = [][]float64{{3, 0.3}, {4, -0.4}, {5, 0.5}}
+	}
+	return bp
+}
+
+func neuronWeights(neuron *Neuron) []float64 {
+	weights := []float64{neuron.Bias}
+	for i := 0; i < neuron.numConnections(); i++ {
+		_, weight := neuron.connection(i)
+		weights = append(weights, weight)
+	}
+	return weights
+}
+
+func TestFrozenNeuronsKeepTheirWeights(t *testing.T) {
+	randomSource.Seed(7)
+	sessions := []Session{
+		{InputVariables: map[int]float64{1: 0, 2: 1}, ExpectedOutput: map[int]float64{6: 1, 7: 0}, Timesteps: 1},
+		{InputVariables: map[int]float64{1: 1, 2: 0}, ExpectedOutput: map[int]float64{6: 0, 7: 1}, Timesteps: 1},
+	}
+
+	trainers := map[string]func(bp *Blueprint){
+		"HillClimbWeightUpdate": func(bp *Blueprint) {
+			for i := 0; i < 200; i++ {
+				bp.HillClimbWeightUpdate(sessions)
+			}
+		},
+		"MutateWeights": func(bp *Blueprint) {
+			for i := 0; i < 200; i++ {
+				bp.MutateWeights()
+			}
+		},
+		"RandomizeWeights": func(bp *Blueprint) { bp.RandomizeWeights() },
+		"InitializeWeights": func(bp *Blueprint) {
+			if err := bp.InitializeWeights("he"); err != nil {
+				t.Fatal(err)
+			}
+		},
+		"TrainBackprop": func(bp *Blueprint) {
+			if err := bp.TrainBackprop(sessions, 0.1, 20); err != nil {
+				t.Fatal(err)
+			}
+		},
+		"AdamWeightUpdate": func(bp *Blueprint) {
+			for i := 0; i < 20; i++ {
+				bp.AdamWeightUpdate(sessions, 0.05, 0.9, 0.999)
+			}
+		},
+		"TrainCMAES": func(bp *Blueprint) { bp.TrainCMAES(sessions, 8, 10) },
+	}
+
+	for name, train := range trainers {
+		t.Run(name, func(t *testing.T) {
+			bp := freezeTestBlueprint()
+			if err := bp.FreezeNeurons(3, 6); err != nil {
+				t.Fatal(err)
+			}
+			bp.frozenNeurons = map[int]bool{4: true} // Kept fixed by NAS
+			before := make(map[int][]float64)
+			for id, neuron := range bp.Neurons {
+				before[id] = neuronWeights(neuron)
+			}
+
+			train(bp)
+
+			for _, id := range []int{3, 4, 6} {
+				if got := neuronWeights(bp.Neurons[id]); !slices.Equal(got, before[id]) {
+					t.Errorf("frozen neuron %d changed from %v to %v", id, before[id], got)
+				}
+			}
+			moved := false
+			for _, id := range []int{5, 7} {
+				moved = moved || !slices.Equal(neuronWeights(bp.Neurons[id]), before[id])
+			}
+			if !moved {
+				t.Error("no unfrozen neuron changed")
+			}
+		})
+	}
+}
+
+func TestFreezeNeuronsRejectsUnknownIDs(t *testing.T) {
+	bp := freezeTestBlueprint()
+	if err := bp.FreezeNeurons(3, 99); err == nil {
+		t.Fatal("expected an error for a missing neuron")
+	}
+	if bp.Neurons[3].Frozen {
+		t.Error("neuron 3 was frozen although the call failed")
+	}
+	if err := bp.FreezeNeurons(3); err != nil {
+		t.Fatal(err)
+	}
+	bp.UnfreezeAll()
+	if bp.isFrozen(3) {
+		t.Error("neuron 3 is still frozen after UnfreezeAll")
+	}
+}
@@ -0,0 +1,180 @@
+package blueprint
+
+import (
+	"fmt"
+	"go/format"
+	"go/token"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// GenerateGoCode emits a self-contained Go source file for the given package that implements the forward
+// pass of this model with its current weights baked in. The generated Predict function takes the inputs
+// in InputNodes order and returns the softmaxed outputs in OutputNodes order, computing every neuron as a
+// straight-line expression in layer order, with no maps, loops or dependencies beyond the math package.
+// Neurons that cannot reach an output are left out.
+// Like Compile it requires an acyclic network of dense neurons; other neuron types and activations
+// without a built-in implementation return an error.
+func (bp *Blueprint) GenerateGoCode(packageName string) (string, error) {
+	if !token.IsIdentifier(packageName) {
+		return "", fmt.Errorf("'%s' is not a valid package name", packageName)
+	}
+	if len(bp.OutputNodes) == 0 {
+		return "", fmt.Errorf("blueprint has no output nodes")
+	}
+
+	layers, err := bp.ComputeLayers()
+	if err != nil {
+		return "", err
+	}
+
+	// Only neurons an output depends on need to be computed
+	live := make(map[int]bool, len(bp.Neurons))
+	pending := append([]int{}, bp.OutputNodes...)
+	for len(pending) > 0 {
+		id := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		neuron, exists := bp.Neurons[id]
+		if !exists || live[id] {
+			continue
+		}
+		live[id] = true
+		for i := 0; i < neuron.numConnections(); i++ {
+			sourceID, _ := neuron.connection(i)
+			pending = append(pending, sourceID)
+		}
+	}
+
+	var body strings.Builder
+	defined := make(map[int]bool, len(live))
+	for i, id := range bp.InputNodes {
+		if !live[id] || defined[id] {
+			continue
+		}
+		fmt.Fprintf(&body, "\tn%d := in[%d]\n", id, i)
+		defined[id] = true
+	}
+
+	for l, layer := range layers[1:] {
+		header := fmt.Sprintf("\n\t// Layer %d\n", l+1)
+		for _, id := range layer {
+			if !live[id] {
+				continue
+			}
+			body.WriteString(header)
+			header = ""
+
+			neuron := bp.Neurons[id]
+			if neuron.Type == "input" {
+				return "", fmt.Errorf("input neuron %d is not listed in InputNodes", id)
+			}
+			if !isDenseNeuronType(neuron.Type) {
+				return "", fmt.Errorf("neuron %d has unsupported type '%s'", id, neuron.Type)
+			}
+
+			if math.IsNaN(neuron.Bias) || math.IsInf(neuron.Bias, 0) {
+				return "", fmt.Errorf("neuron %d has a non-finite bias", id)
+			}
+			expr := formatGoFloat(neuron.Bias)
+			for i := 0; i < neuron.numConnections(); i++ {
+				sourceID, weight := neuron.connection(i)
+				if _, exists := bp.Neurons[sourceID]; !exists {
+					continue
+				}
+				if math.IsNaN(weight) || math.IsInf(weight, 0) {
+					return "", fmt.Errorf("neuron %d has a non-finite weight from neuron %d", id, sourceID)
+				}
+				if weight < 0 {
+					expr += fmt.Sprintf(" - n%d*%s", sourceID, formatGoFloat(-weight))
+				} else {
+					expr += fmt.Sprintf(" + n%d*%s", sourceID, formatGoFloat(weight))
+				}
+			}
+
+			activation, err := goActivation(fmt.Sprintf("n%d", id), neuron.Activation)
+			if err != nil {
+				return "", fmt.Errorf("neuron %d: %w", id, err)
+			}
+			fmt.Fprintf(&body, "\tn%d := %s\n%s", id, expr, activation)
+			defined[id] = true
+		}
+	}
+
+	outputs := make([]string, len(bp.OutputNodes))
+	for i, id := range bp.OutputNodes {
+		if !defined[id] {
+			return "", fmt.Errorf("output neuron %d does not exist", id)
+		}
+		outputs[i] = fmt.Sprintf("n%d", id)
+	}
+
+	var src strings.Builder
+	src.WriteString("// Code generated by Blueprint.GenerateGoCode. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&src, "package %s\n\nimport \"math\"\n\n", packageName)
+	fmt.Fprintf(&src, "// NumInputs is the length of the input vector, ordered by neuron ID %v.\n", bp.InputNodes)
+	fmt.Fprintf(&src, "const NumInputs = %d\n\n", len(bp.InputNodes))
+	fmt.Fprintf(&src, "// NumOutputs is the length of the output vector, ordered by neuron ID %v.\n", bp.OutputNodes)
+	fmt.Fprintf(&src, "const NumOutputs = %d\n\n", len(bp.OutputNodes))
+	src.WriteString("// Predict runs the forward pass and returns the softmaxed outputs.\n")
+	src.WriteString("func Predict(in [NumInputs]float64) [NumOutputs]float64 {\n")
+	src.WriteString(body.String())
+	fmt.Fprintf(&src, "\n\treturn softmax([NumOutputs]float64{%s})\n}\n\n", strings.Join(outputs, ", "))
+	src.WriteString(`func softmax(x [NumOutputs]float64) [NumOutputs]float64 {
+	max := x[0]
+	for _, v := range x {
+		if v > max {
+			max = v
+		}
+	}
+	sum := 0.0
+	for i, v := range x {
+		x[i] = math.Exp(v - max)
+		sum += x[i]
+	}
+	for i := range x {
+		x[i] /= sum
+	}
+	return x
+}
+`)
+
+	formatted, err := format.Source([]byte(src.String()))
+	if err != nil {
+		return "", fmt.Errorf("failed to format generated code: %w", err)
+	}
+	return string(formatted), nil
+}
+
+// goActivation returns the statements applying the named activation to the variable v in place.
+// An empty activation is linear, as in ApplyScalarActivation.
+func goActivation(v, activation string) (string, error) {
+	switch activation {
+	case "linear", "":
+		return "", nil
+	case "relu":
+		return fmt.Sprintf("\t%s = math.Max(0, %s)\n", v, v), nil
+	case "sigmoid":
+		return fmt.Sprintf("\t%s = 1 / (1 + math.Exp(-%s))\n", v, v), nil
+	case "tanh":
+		return fmt.Sprintf("\t%s = math.Tanh(%s)\n", v, v), nil
+	case "leaky_relu":
+		return fmt.Sprintf("\tif !(%s > 0) {\n\t\t%s *= 0.01\n\t}\n", v, v), nil
+	case "elu":
+		return fmt.Sprintf("\tif !(%s >= 0) {\n\t\t%s = math.Exp(%s) - 1\n\t}\n", v, v, v), nil
+	case "gelu":
+		return fmt.Sprintf("\t%s = 0.5 * %s * (1 + math.Erf(%s/math.Sqrt2))\n", v, v, v), nil
+	case "swish":
+		return fmt.Sprintf("\t%s = %s * (1 / (1 + math.Exp(-%s)))\n", v, v, v), nil
+	}
+	return "", fmt.Errorf("activation '%s' cannot be generated", activation)
+}
+
+// formatGoFloat formats f as a Go float literal that parses back to exactly the same value.
+func formatGoFloat(f float64) string {
+	s := strconv.FormatFloat(f, 'g', -1, 64)
+	if !strings.ContainsAny(s, ".eEn") {
+		s += ".0"
+	}
+	return s
+}
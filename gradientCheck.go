@@ -0,0 +1,64 @@
+package blueprint
+
+// defaultGradientEpsilon is the weight offset NumericalGradient uses when none is given.
+const defaultGradientEpsilon = 1e-5
+
+// ConnectionKey identifies a connection by the IDs of its source and target neurons.
+type ConnectionKey struct {
+	Source int
+	Target int
+}
+
+// NumericalGradient returns the gradient of the session's squared error loss with respect to every connection
+// weight, estimated by central finite differences: each weight is moved by +epsilon and -epsilon in turn and the
+// loss difference divided by 2 epsilon. The loss is the mean over output nodes of the squared difference between
+// the output, as GetOutputs returns it after softmax, and the expected output. The network runs outside training
+// mode through Predict, so neuron values are left as they were and every pass starts from the same state. Of
+// duplicate connections only the first is reported, like AdamWeightUpdate. A non-positive epsilon uses 1e-5.
+// Comparing the result with an analytic gradient verifies a backpropagation implementation. Requires float64
+// connection storage, since float32 weights cannot resolve small offsets.
+func (bp *Blueprint) NumericalGradient(session Session, epsilon float64) map[ConnectionKey]float64 {
+	if bp.LowPrecision {
+		bp.infof("Numerical gradients require float64 storage. Call ConvertToFloat64Storage first.")
+		return nil
+	}
+	if epsilon <= 0 {
+		epsilon = defaultGradientEpsilon
+	}
+	defer bp.evalMode()()
+
+	gradient := make(map[ConnectionKey]float64)
+	for _, targetID := range bp.getAllNeuronIDs() {
+		neuron := bp.Neurons[targetID]
+		for i := 0; i < neuron.numConnections(); i++ {
+			sourceID, weight := neuron.connection(i)
+			key := ConnectionKey{Source: sourceID, Target: targetID}
+			if _, seen := gradient[key]; seen {
+				continue
+			}
+
+			neuron.setConnectionWeight(i, weight+epsilon)
+			lossUp := bp.squaredErrorLoss(session)
+			neuron.setConnectionWeight(i, weight-epsilon)
+			lossDown := bp.squaredErrorLoss(session)
+			neuron.setConnectionWeight(i, weight)
+			gradient[key] = (lossUp - lossDown) / (2 * epsilon)
+		}
+	}
+	bp.invalidateCompiled()
+	return gradient
+}
+
+// squaredErrorLoss returns the mean over output nodes of the squared error of the session's outputs.
+func (bp *Blueprint) squaredErrorLoss(session Session) float64 {
+	if len(bp.OutputNodes) == 0 {
+		return 0
+	}
+	outputs := bp.Predict(session.InputVariables, session.Timesteps)
+	total := 0.0
+	for _, id := range bp.OutputNodes {
+		diff := outputs[id] - session.ExpectedOutput[id]
+		total += diff * diff
+	}
+	return total / float64(len(bp.OutputNodes))
+}
@@ -0,0 +1,44 @@
+package blueprint
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNumericalGradientMatchesLinearNeuron(t *testing.T) {
+	bp := NewBlueprint()
+	bp.AddInputNeurons([]int{1, 2})
+	bp.AddOutputNeurons([]int{3, 4}, "linear")
+	// Outputs are softmaxed, so output 4 has no inputs and stays at 0 as a reference for the linear neuron 3
+	bp.Neurons[3].Bias = 0.1
+	bp.Neurons[3].Connections = [][]float64{{1, 0.5}, {2, -0.25}}
+	session := Session{
+		InputVariables: map[int]float64{1: 2, 2: 4},
+		ExpectedOutput: map[int]float64{3: 1, 4: 0},
+		Timesteps:      1,
+	}
+
+	gradient := bp.NumericalGradient(session, 1e-6)
+
+	// With z = w1*x1 + w2*x2 + b, p3 = softmax(z, 0) and p4 = 1 - p3, the loss ((p3-y3)^2 + (p4-y4)^2) / 2
+	// has the derivative ((p3-y3) - (p4-y4)) * p3*p4 * xi for weight i
+	z := 0.5*2 - 0.25*4 + 0.1
+	p3 := 1 / (1 + math.Exp(-z))
+	p4 := 1 - p3
+	for _, c := range []struct {
+		source int
+		input  float64
+	}{{1, 2}, {2, 4}} {
+		want := ((p3 - 1) - p4) * p3 * p4 * c.input
+		got, ok := gradient[ConnectionKey{Source: c.source, Target: 3}]
+		if !ok || math.Abs(got-want) > 1e-6 {
+			t.Errorf("gradient of weight %d->3 = %v (present %v), want %v", c.source, got, ok, want)
+		}
+	}
+	if len(gradient) != 2 {
+		t.Errorf("gradient has %d entries, want 2: %v", len(gradient), gradient)
+	}
+	if w := bp.Neurons[3].Connections; w[0][1] != 0.5 || w[1][1] != -0.25 {
+		t.Errorf("weights after NumericalGradient = %v, want them restored", w)
+	}
+}
@@ -0,0 +1,321 @@
+package blueprint
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// RPCRequest is the body accepted by ServeHTTP: a Blueprint method name and its positional arguments.
+type RPCRequest struct {
+	Method string            `json:"method"`
+	Args   []json.RawMessage `json:"args"`
+}
+
+// RPCResponse carries the non-error return values of the invoked method, or the error that occurred.
+type RPCResponse struct {
+	Results []interface{} `json:"results,omitempty"`
+	Error   string        `json:"error,omitempty"`
+}
+
+// rpcMethods lists the Blueprint methods that ServeHTTP may invoke. None of them changes weights or topology, but
+// RunNetwork, Forward, ForwardMatrix and evaluation write neuron values and recurrent state on the shared
+// blueprint, so calls are serialized by rpcLock.
+// Training, deserialization, file system and network methods are deliberately not exposed.
+var rpcMethods = map[string]bool{
+	// Evaluation
+	"Evaluate":                         true,
+	"EvaluateModelPerformance":         true,
+	"EvaluateOnSample":                 true,
+	"AdvancedEvaluateModelPerformance": true,
+	// Prediction
+	"RunNetwork":    true,
+	"Forward":       true,
+	"ForwardMatrix": true,
+	"GetOutputs":    true,
+	// Serialization
+	"SerializeToJSON":       true,
+	"SerializeToGob":        true,
+	"SerializationFidelity": true,
+	// Introspection
+	"ComputeLayers":       true,
+	"GetBlueprintMethods": true,
+}
+
+// rpcMutatingMethods lists the methods that replace the model. Only handlers returned by RPCHandler expose them,
+// and only to authorized requests.
+var rpcMutatingMethods = map[string]bool{
+	"DeserializesFromJSON": true,
+	"DeserializeFromGob":   true,
+}
+
+// rpcLock returns the blueprint's own mutex that serializes HTTP calls, since Blueprint methods are not safe for
+// concurrent use, creating it on first use.
+func (bp *Blueprint) rpcLock() *sync.Mutex {
+	blueprintLockInit.Lock()
+	defer blueprintLockInit.Unlock()
+	if bp.rpcMu == nil {
+		bp.rpcMu = &sync.Mutex{}
+	}
+	return bp.rpcMu
+}
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// ServeHTTP exposes the Blueprint as a read-only JSON service.
+// A GET request returns the metadata of every method that can be invoked.
+// A POST request with an RPCRequest body invokes the named method via reflection and returns an RPCResponse.
+// Methods that replace the model, such as DeserializeFromGob, are only available through RPCHandler.
+func (bp *Blueprint) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	bp.serveRPC(w, r, false)
+}
+
+// RPCHandler returns a handler that works like ServeHTTP but also lets requests replace the model through
+// DeserializesFromJSON and DeserializeFromGob. Every request must pass authorize, typically a check of a token or
+// client certificate, or it is rejected with 401 Unauthorized; a nil authorize rejects every request.
+func (bp *Blueprint) RPCHandler(authorize func(r *http.Request) bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if authorize == nil || !authorize(r) {
+			bp.writeRPCResponse(w, http.StatusUnauthorized, RPCResponse{Error: "unauthorized"})
+			return
+		}
+		bp.serveRPC(w, r, true)
+	}
+}
+
+// rpcAllowed reports whether the named method may be invoked, including the methods that replace the model only
+// if allowMutating is set.
+func rpcAllowed(name string, allowMutating bool) bool {
+	return rpcMethods[name] || (allowMutating && rpcMutatingMethods[name])
+}
+
+// serveRPC handles a request for ServeHTTP or RPCHandler.
+func (bp *Blueprint) serveRPC(w http.ResponseWriter, r *http.Request, allowMutating bool) {
+	switch r.Method {
+	case http.MethodGet:
+		methods, err := bp.GetBlueprintMethods()
+		if err != nil {
+			bp.writeRPCResponse(w, http.StatusInternalServerError, RPCResponse{Error: err.Error()})
+			return
+		}
+		exposed := []MethodInfo{}
+		for _, method := range methods {
+			if rpcAllowed(method.MethodName, allowMutating) {
+				exposed = append(exposed, method)
+			}
+		}
+		bp.writeJSON(w, http.StatusOK, exposed)
+
+	case http.MethodPost:
+		var req RPCRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			bp.writeRPCResponse(w, http.StatusBadRequest, RPCResponse{Error: fmt.Sprintf("invalid request body: %v", err)})
+			return
+		}
+
+		mu := bp.rpcLock()
+		mu.Lock()
+		results, status, err := bp.callMethod(req.Method, req.Args, allowMutating)
+		mu.Unlock()
+		if err != nil {
+			bp.writeRPCResponse(w, status, RPCResponse{Error: err.Error()})
+			return
+		}
+		bp.writeRPCResponse(w, http.StatusOK, RPCResponse{Results: results})
+
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		bp.writeRPCResponse(w, http.StatusMethodNotAllowed, RPCResponse{Error: "only GET and POST are supported"})
+	}
+}
+
+// callMethod decodes the arguments, invokes the named method and returns its non-error results
+// along with the HTTP status to report if an error occurred.
+func (bp *Blueprint) callMethod(name string, args []json.RawMessage, allowMutating bool) ([]interface{}, int, error) {
+	if !rpcAllowed(name, allowMutating) {
+		return nil, http.StatusBadRequest, fmt.Errorf("method '%s' is not available", name)
+	}
+	method := reflect.ValueOf(bp).MethodByName(name)
+	if !method.IsValid() {
+		return nil, http.StatusBadRequest, fmt.Errorf("method '%s' does not exist", name)
+	}
+
+	methodType := method.Type()
+	if len(args) != methodType.NumIn() {
+		return nil, http.StatusBadRequest, fmt.Errorf("method '%s' expects %d arguments, got %d", name, methodType.NumIn(), len(args))
+	}
+
+	in := make([]reflect.Value, len(args))
+	for i, raw := range args {
+		arg := reflect.New(methodType.In(i))
+		if err := json.Unmarshal(raw, arg.Interface()); err != nil {
+			return nil, http.StatusBadRequest, fmt.Errorf("argument %d of '%s': %v", i+1, name, err)
+		}
+		in[i] = arg.Elem()
+	}
+
+	results := []interface{}{}
+	for i, out := range method.Call(in) {
+		if methodType.Out(i) == errorType {
+			if !out.IsNil() {
+				return nil, http.StatusInternalServerError, out.Interface().(error)
+			}
+			continue
+		}
+		results = append(results, out.Interface())
+	}
+	return results, http.StatusOK, nil
+}
+
+// writeRPCResponse writes resp as JSON. Results that JSON cannot encode directly, such as the
+// infinite errors produced by the evaluation metrics, are sent with non-finite floats as strings.
+func (bp *Blueprint) writeRPCResponse(w http.ResponseWriter, status int, resp RPCResponse) {
+	if _, err := json.Marshal(resp.Results); err != nil {
+		for i, result := range resp.Results {
+			resp.Results[i] = jsonSafeValue(reflect.ValueOf(result))
+		}
+	}
+	bp.writeJSON(w, status, resp)
+}
+
+// writeJSON writes v as a JSON response body with the given status code.
+func (bp *Blueprint) writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		bp.warnf("Error writing JSON response: %v", err)
+	}
+}
+
+// jsonSafeValue converts v into plain maps, slices and scalars, replacing NaN and infinite floats
+// with their string form so the result can always be encoded as JSON.
+func jsonSafeValue(v reflect.Value) interface{} {
+	if !v.IsValid() {
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Float32, reflect.Float64:
+		f := v.Float()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return fmt.Sprint(f)
+		}
+		return f
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return jsonSafeValue(v.Elem())
+	case reflect.Slice:
+		if v.IsNil() {
+			return nil
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return v.Interface() // Keep the base64 encoding of []byte
+		}
+		fallthrough
+	case reflect.Array:
+		out := make([]interface{}, v.Len())
+		for i := range out {
+			out[i] = jsonSafeValue(v.Index(i))
+		}
+		return out
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+		out := make(map[string]interface{}, v.Len())
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j]) })
+		for _, key := range keys {
+			out[fmt.Sprint(key.Interface())] = jsonSafeValue(v.MapIndex(key))
+		}
+		return out
+	case reflect.Struct:
+		out := make(map[string]interface{})
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			name := field.Name
+			if tag := field.Tag.Get("json"); tag != "" {
+				tagName := strings.Split(tag, ",")[0]
+				if tagName == "-" {
+					continue
+				}
+				if tagName != "" {
+					name = tagName
+				}
+			}
+			out[name] = jsonSafeValue(v.Field(i))
+		}
+		return out
+	}
+	return v.Interface()
+}
+
+// PredictRequest is the body accepted by PredictHandler.
+type PredictRequest struct {
+	Inputs    map[int]float64 `json:"inputs"`    // Input values keyed by input neuron ID
+	Timesteps int             `json:"timesteps"` // Number of timesteps to run (defaults to 1)
+}
+
+// PredictResponse is returned by PredictHandler.
+type PredictResponse struct {
+	Class         int             `json:"class"`         // Output neuron ID with the highest probability
+	Probabilities map[int]float64 `json:"probabilities"` // Softmaxed output values keyed by output neuron ID
+}
+
+// PredictHandler returns an HTTP handler that runs inference for a POSTed PredictRequest
+// and responds with a PredictResponse. Requests whose input IDs are not input nodes, or
+// whose timesteps are negative, are rejected with 400 Bad Request.
+func (bp *Blueprint) PredictHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", "POST")
+			bp.writeJSON(w, http.StatusMethodNotAllowed, RPCResponse{Error: "only POST is supported"})
+			return
+		}
+
+		var req PredictRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			bp.writeJSON(w, http.StatusBadRequest, RPCResponse{Error: fmt.Sprintf("invalid request body: %v", err)})
+			return
+		}
+		if len(req.Inputs) == 0 {
+			bp.writeJSON(w, http.StatusBadRequest, RPCResponse{Error: "no inputs given"})
+			return
+		}
+		for id := range req.Inputs {
+			if !bp.isInputNode(id) {
+				bp.writeJSON(w, http.StatusBadRequest, RPCResponse{Error: fmt.Sprintf("neuron %d is not an input node", id)})
+				return
+			}
+		}
+		if req.Timesteps < 0 {
+			bp.writeJSON(w, http.StatusBadRequest, RPCResponse{Error: fmt.Sprintf("timesteps must not be negative, got %d", req.Timesteps)})
+			return
+		}
+		if req.Timesteps == 0 {
+			req.Timesteps = 1
+		}
+
+		mu := bp.rpcLock()
+		mu.Lock()
+		bp.RunNetwork(req.Inputs, req.Timesteps)
+		probabilities := bp.GetOutputs()
+		mu.Unlock()
+
+		bp.writeJSON(w, http.StatusOK, PredictResponse{
+			Class:         argmaxMap(probabilities),
+			Probabilities: probabilities,
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package blueprint
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func postRPC(t *testing.T, handler http.Handler, body string, header http.Header) (*httptest.ResponseRecorder, RPCResponse) {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	var resp RPCResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decoding response %q: %v", rec.Body.String(), err)
+	}
+	return rec, resp
+}
+
+func TestServeHTTPRejectsDeserialization(t *testing.T) {
+	bp := NewBlueprint()
+	bp.AddInputNeurons([]int{1})
+	bp.AddOutputNeurons([]int{2}, "linear")
+	before := bp.Hash()
+
+	for _, method := range []string{"DeserializesFromJSON", "DeserializeFromGob"} {
+		rec, resp := postRPC(t, bp, `{"method":"`+method+`","args":["{}"]}`, nil)
+		if rec.Code != http.StatusBadRequest || resp.Error == "" {
+			t.Errorf("%s over ServeHTTP returned %d %+v, want 400 with an error", method, rec.Code, resp)
+		}
+	}
+	if bp.Hash() != before {
+		t.Error("the model changed")
+	}
+
+	if rec, resp := postRPC(t, bp, `{"method":"SerializeToJSON","args":[]}`, nil); rec.Code != http.StatusOK || len(resp.Results) != 1 {
+		t.Errorf("SerializeToJSON returned %d %+v", rec.Code, resp)
+	}
+}
+
+func TestRPCHandlerRequiresAuthorization(t *testing.T) {
+	source := NewBlueprint()
+	source.AddInputNeurons([]int{1})
+	source.AddOutputNeurons([]int{2, 3}, "linear")
+	data, err := source.SerializeToJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	arg, _ := json.Marshal(data)
+	body := `{"method":"DeserializesFromJSON","args":[` + string(arg) + `]}`
+
+	bp := NewBlueprint()
+	handler := bp.RPCHandler(func(r *http.Request) bool { return r.Header.Get("Authorization") == "Bearer secret" })
+
+	if rec, _ := postRPC(t, handler, body, nil); rec.Code != http.StatusUnauthorized {
+		t.Errorf("request without a token returned %d, want 401", rec.Code)
+	}
+	if len(bp.Neurons) != 0 {
+		t.Fatal("an unauthorized request changed the model")
+	}
+
+	rec, resp := postRPC(t, handler, body, http.Header{"Authorization": {"Bearer secret"}})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("authorized request returned %d %+v", rec.Code, resp)
+	}
+	if bp.Hash() != source.Hash() {
+		t.Error("the model was not replaced")
+	}
+
+	if rec, _ := postRPC(t, bp.RPCHandler(nil), body, nil); rec.Code != http.StatusUnauthorized {
+		t.Errorf("handler without authorize returned %d, want 401", rec.Code)
+	}
+}
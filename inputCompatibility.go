@@ -0,0 +1,99 @@
+package blueprint
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// CheckInputCompatibility verifies that a sample provides a value for every input node and does not
+// contain IDs that are not input nodes. Mismatched layouts otherwise fail silently, since Forward
+// ignores unknown inputs and leaves missing ones at their previous value.
+func (bp *Blueprint) CheckInputCompatibility(sample map[int]float64) error {
+	var missing, unknown []int
+	for _, id := range bp.InputNodes {
+		if _, exists := sample[id]; !exists {
+			missing = append(missing, id)
+		}
+	}
+	for id := range sample {
+		if !bp.isInputNode(id) {
+			unknown = append(unknown, id)
+		}
+	}
+	if len(missing) == 0 && len(unknown) == 0 {
+		return nil
+	}
+
+	sort.Ints(unknown)
+	var problems []string
+	if len(missing) > 0 {
+		problems = append(problems, fmt.Sprintf("missing input nodes %v", missing))
+	}
+	if len(unknown) > 0 {
+		problems = append(problems, fmt.Sprintf("unknown input IDs %v", unknown))
+	}
+	return fmt.Errorf("sample does not match the model inputs %v: %s", bp.InputNodes, strings.Join(problems, ", "))
+}
+
+// RemapInputs relabels input nodes so the model can be fed data keyed by different IDs. Every key of
+// oldToNew must be an input node; its neuron, its entry in InputNodes and every connection reading from
+// it move to the new ID. New IDs may not collide with neurons that keep their ID, but inputs may swap IDs.
+// Nothing is changed if an error is returned.
+func (bp *Blueprint) RemapInputs(oldToNew map[int]int) error {
+	targets := make(map[int]int, len(oldToNew))
+	for oldID, newID := range oldToNew {
+		if !bp.isInputNode(oldID) {
+			return fmt.Errorf("neuron %d is not an input node", oldID)
+		}
+		if previous, taken := targets[newID]; taken {
+			return fmt.Errorf("input nodes %d and %d are both mapped to %d", previous, oldID, newID)
+		}
+		targets[newID] = oldID
+	}
+	for newID, oldID := range targets {
+		if _, remapped := oldToNew[newID]; remapped {
+			continue
+		}
+		if _, exists := bp.Neurons[newID]; exists || bp.isInputNode(newID) {
+			return fmt.Errorf("cannot map input %d to %d, which is already in use", oldID, newID)
+		}
+	}
+	if bp.LowPrecision {
+		for newID := range targets {
+			if newID > 1<<24 {
+				return fmt.Errorf("input ID %d cannot be represented in float32 connection storage", newID)
+			}
+		}
+	}
+
+	// Move the neurons, then rewrite the references to them
+	moved := make(map[int]*Neuron, len(oldToNew))
+	for oldID, newID := range oldToNew {
+		if neuron, exists := bp.Neurons[oldID]; exists {
+			neuron.ID = newID
+			moved[newID] = neuron
+			delete(bp.Neurons, oldID)
+		}
+	}
+	for newID, neuron := range moved {
+		bp.Neurons[newID] = neuron
+	}
+
+	for i, id := range bp.InputNodes {
+		if newID, remapped := oldToNew[id]; remapped {
+			bp.InputNodes[i] = newID
+		}
+	}
+	for _, neuron := range bp.Neurons {
+		for i := 0; i < neuron.numConnections(); i++ {
+			sourceID, _ := neuron.connection(i)
+			if newID, remapped := oldToNew[sourceID]; remapped {
+				neuron.setConnectionSource(i, newID)
+			}
+		}
+	}
+
+	bp.invalidateCompiled()
+	return nil
+}
@@ -8,14 +8,16 @@ import (
 
 // MethodInfo represents metadata about a method, including its name, parameters, and parameter types.
 type MethodInfo struct {
-	MethodName string          `json:"method_name"`
-	Parameters []ParameterInfo `json:"parameters"`
+	MethodName  string          `json:"method_name"`
+	Description string          `json:"description,omitempty"`
+	Parameters  []ParameterInfo `json:"parameters"`
 }
 
 // ParameterInfo represents metadata about a parameter, including its name and type.
 type ParameterInfo struct {
-	Name string `json:"name"`
-	Type string `json:"type"`
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Description string `json:"description,omitempty"`
 }
 
 // GetBlueprintMethodsJSON returns a JSON string containing all methods attached to the Blueprint struct,
@@ -37,6 +39,7 @@ func (bp *Blueprint) GetBlueprintMethodsJSON() (string, error) {
 }
 
 // GetBlueprintMethods retrieves all methods of the Blueprint struct, including their names, parameters, and types.
+// Parameter names and descriptions come from RegisterMethod; unregistered parameters are named param1, param2, ...
 func (bp *Blueprint) GetBlueprintMethods() ([]MethodInfo, error) {
 	var methods []MethodInfo
 
@@ -44,23 +47,33 @@ func (bp *Blueprint) GetBlueprintMethods() ([]MethodInfo, error) {
 	bpType := reflect.TypeOf(bp)
 	for i := 0; i < bpType.NumMethod(); i++ {
 		method := bpType.Method(i)
+		doc, registered := lookupMethod(method.Name)
 
 		// Collect parameter information for each method
 		var params []ParameterInfo
 		methodType := method.Type
+		useDoc := registered && len(doc.Parameters) == methodType.NumIn()-1
+		if registered && !useDoc && bp.Debug {
+			bp.debugf("Warning: Registered parameters of %s do not match its signature.", method.Name)
+		}
 		for j := 1; j < methodType.NumIn(); j++ { // Start from 1 to skip the receiver
 			paramType := methodType.In(j)
 			param := ParameterInfo{
 				Name: fmt.Sprintf("param%d", j),
 				Type: paramType.String(),
 			}
+			if useDoc {
+				param.Name = doc.Parameters[j-1].Name
+				param.Description = doc.Parameters[j-1].Description
+			}
 			params = append(params, param)
 		}
 
 		// Append method information
 		methods = append(methods, MethodInfo{
-			MethodName: method.Name,
-			Parameters: params,
+			MethodName:  method.Name,
+			Description: doc.Description,
+			Parameters:  params,
 		})
 	}
 
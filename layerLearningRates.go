@@ -0,0 +1,47 @@
+package blueprint
+
+// SetLayerLearningRates assigns a learning-rate multiplier to each layer returned by ComputeLayers,
+// so multipliers[0] belongs to the input layer and multipliers[1] to the first hidden layer.
+// Trainers scale every weight update of a neuron by the multiplier of its layer, which lets
+// earlier layers of a pretrained model update more slowly than the later ones during fine-tuning.
+// Layers without a multiplier use 1. A nil slice removes all multipliers.
+func (bp *Blueprint) SetLayerLearningRates(multipliers []float64) {
+	if multipliers == nil {
+		bp.LayerLearningRates = nil
+		return
+	}
+	bp.LayerLearningRates = append([]float64{}, multipliers...)
+}
+
+// layerLearningRates returns the learning-rate multiplier of every neuron, keyed by neuron ID.
+// It returns nil when no multipliers are set or the layers cannot be computed, in which case
+// every neuron uses a multiplier of 1.
+func (bp *Blueprint) layerLearningRates() map[int]float64 {
+	if len(bp.LayerLearningRates) == 0 {
+		return nil
+	}
+	layers, err := bp.ComputeLayers()
+	if err != nil {
+		return nil
+	}
+
+	rates := make(map[int]float64, len(bp.Neurons))
+	for l, layer := range layers {
+		rate := 1.0
+		if l < len(bp.LayerLearningRates) {
+			rate = bp.LayerLearningRates[l]
+		}
+		for _, id := range layer {
+			rates[id] = rate
+		}
+	}
+	return rates
+}
+
+// learningRateMultiplier returns the multiplier for a single neuron from the map built by layerLearningRates.
+func learningRateMultiplier(rates map[int]float64, neuronID int) float64 {
+	if rate, exists := rates[neuronID]; exists {
+		return rate
+	}
+	return 1.0
+}
@@ -0,0 +1,121 @@
+package blueprint
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// LayeredModel is a layer-oriented description of a feed-forward Blueprint, modelled on a
+// Keras Sequential model spec.
+type LayeredModel struct {
+	ClassName string             `json:"class_name"`
+	Config    LayeredModelConfig `json:"config"`
+}
+
+// LayeredModelConfig holds the layers of a LayeredModel in order.
+type LayeredModelConfig struct {
+	Layers []LayerSpec `json:"layers"`
+}
+
+// LayerSpec describes one layer. Kernel has one row per unit of the previous layer and one column
+// per unit of this layer, matching the (input_dim, units) layout Keras uses for Dense kernels.
+type LayerSpec struct {
+	ClassName  string      `json:"class_name"` // InputLayer, Dense or Softmax
+	Units      int         `json:"units,omitempty"`
+	Activation string      `json:"activation,omitempty"`
+	NeuronIDs  []int       `json:"neuron_ids,omitempty"` // Blueprint neuron ID of each unit
+	Kernel     [][]float64 `json:"kernel,omitempty"`
+	Bias       []float64   `json:"bias,omitempty"`
+}
+
+// ToLayeredJSON converts a clean feed-forward network into a layer-oriented JSON description.
+// The network must split into layers (via ComputeLayers) where every neuron is dense, only receives
+// connections from the previous layer, and shares its layer's activation, and where the last layer
+// is exactly the output nodes. A trailing Softmax layer mirrors the softmax Forward applies to the outputs.
+// An error describes the first reason the network cannot be expressed this way.
+func (bp *Blueprint) ToLayeredJSON() (string, error) {
+	layers, err := bp.ComputeLayers()
+	if err != nil {
+		return "", err
+	}
+	if len(layers) < 2 {
+		return "", fmt.Errorf("network has no layers beyond the inputs")
+	}
+
+	layerOf := make(map[int]int, len(bp.Neurons))
+	for l, layer := range layers {
+		for _, id := range layer {
+			layerOf[id] = l
+		}
+	}
+
+	model := LayeredModel{ClassName: "Sequential"}
+	model.Config.Layers = append(model.Config.Layers, LayerSpec{
+		ClassName: "InputLayer",
+		Units:     len(layers[0]),
+		NeuronIDs: layers[0],
+	})
+
+	for l := 1; l < len(layers); l++ {
+		previous := layers[l-1]
+		column := make(map[int]int, len(previous))
+		for i, id := range previous {
+			column[id] = i
+		}
+
+		spec := LayerSpec{
+			ClassName: "Dense",
+			Units:     len(layers[l]),
+			NeuronIDs: layers[l],
+			Kernel:    make([][]float64, len(previous)),
+			Bias:      make([]float64, len(layers[l])),
+		}
+		for i := range spec.Kernel {
+			spec.Kernel[i] = make([]float64, len(layers[l]))
+		}
+
+		for unit, id := range layers[l] {
+			neuron := bp.Neurons[id]
+			if neuron.Type == "input" || !isDenseNeuronType(neuron.Type) {
+				return "", fmt.Errorf("neuron %d in layer %d has type '%s', which is not a dense layer unit", id, l, neuron.Type)
+			}
+			if unit == 0 {
+				spec.Activation = neuron.Activation
+			} else if neuron.Activation != spec.Activation {
+				return "", fmt.Errorf("layer %d mixes activations '%s' and '%s'", l, spec.Activation, neuron.Activation)
+			}
+
+			spec.Bias[unit] = neuron.Bias
+			for i := 0; i < neuron.numConnections(); i++ {
+				sourceID, weight := neuron.connection(i)
+				if _, exists := bp.Neurons[sourceID]; !exists {
+					continue
+				}
+				row, fromPrevious := column[sourceID]
+				if !fromPrevious {
+					return "", fmt.Errorf("neuron %d in layer %d skips from neuron %d in layer %d", id, l, sourceID, layerOf[sourceID])
+				}
+				spec.Kernel[row][unit] += weight
+			}
+		}
+		model.Config.Layers = append(model.Config.Layers, spec)
+	}
+
+	// The outputs must be exactly the last layer
+	last := layers[len(layers)-1]
+	if len(last) != len(bp.OutputNodes) {
+		return "", fmt.Errorf("last layer has %d neurons but there are %d output nodes", len(last), len(bp.OutputNodes))
+	}
+	for _, id := range bp.OutputNodes {
+		if _, exists := bp.Neurons[id]; !exists || layerOf[id] != len(layers)-1 {
+			return "", fmt.Errorf("output neuron %d is not in the last layer", id)
+		}
+	}
+	model.Config.Layers = append(model.Config.Layers, LayerSpec{ClassName: "Softmax"})
+
+	data, err := json.MarshalIndent(model, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to serialize layered model: %w", err)
+	}
+	return string(data), nil
+}
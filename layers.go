@@ -0,0 +1,129 @@
+package blueprint
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ErrCycle is returned, wrapped with the neurons forming the cycle, by TopologicalOrder when the network is not acyclic.
+var ErrCycle = errors.New("network contains a cycle")
+
+// TopologicalOrder returns every neuron ID ordered so that each neuron comes after all the neurons it reads
+// from. Input nodes are treated as sources whatever connections they hold, since Forward never computes them.
+// Disconnected components are included and ties follow ascending IDs. If the network has a cycle, the error
+// wraps ErrCycle and lists the neurons along it in the direction values flow, e.g. "network contains a cycle:
+// 4 -> 7 -> 4" when 7 reads from 4 and 4 reads from 7.
+func (bp *Blueprint) TopologicalOrder() ([]int, error) {
+	neuronIDs := bp.getAllNeuronIDs()
+	order := make([]int, 0, len(neuronIDs))
+	done := make(map[int]bool, len(neuronIDs))
+	onPath := make(map[int]int) // Position of each neuron on the current DFS path
+	path := []int{}
+
+	var visit func(id int) error
+	visit = func(id int) error {
+		if done[id] {
+			return nil
+		}
+		if start, cycling := onPath[id]; cycling {
+			// The path runs from targets to their sources, so list it backwards to follow the data flow
+			cycle := append(append([]int{}, path[start:]...), id)
+			steps := make([]string, len(cycle))
+			for i, cycleID := range cycle {
+				steps[len(cycle)-1-i] = fmt.Sprint(cycleID)
+			}
+			return fmt.Errorf("%w: %s", ErrCycle, strings.Join(steps, " -> "))
+		}
+
+		if !bp.isInputNode(id) {
+			onPath[id] = len(path)
+			path = append(path, id)
+			neuron := bp.Neurons[id]
+			for i := 0; i < neuron.numConnections(); i++ {
+				sourceID, _ := neuron.connection(i)
+				if _, exists := bp.Neurons[sourceID]; !exists {
+					continue
+				}
+				if err := visit(sourceID); err != nil {
+					return err
+				}
+			}
+			path = path[:len(path)-1]
+			delete(onPath, id)
+		}
+
+		done[id] = true
+		order = append(order, id)
+		return nil
+	}
+
+	for _, id := range neuronIDs {
+		if err := visit(id); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
+
+// ComputeLayers groups the neurons of the blueprint into feed-forward layers.
+// Input nodes form layer 0 and every other neuron is placed one layer above the deepest
+// neuron feeding into it, so a neuron without any existing sources lands in layer 1.
+// Neuron IDs inside each layer are sorted. An error is returned if the graph contains a cycle.
+func (bp *Blueprint) ComputeLayers() ([][]int, error) {
+	depth := make(map[int]int, len(bp.Neurons))
+	visiting := make(map[int]bool)
+
+	var visit func(id int) (int, error)
+	visit = func(id int) (int, error) {
+		if d, done := depth[id]; done {
+			return d, nil
+		}
+		if bp.isInputNode(id) {
+			depth[id] = 0
+			return 0, nil
+		}
+		if visiting[id] {
+			return 0, fmt.Errorf("cycle detected at neuron %d", id)
+		}
+		visiting[id] = true
+
+		d := 1
+		neuron := bp.Neurons[id]
+		for i := 0; i < neuron.numConnections(); i++ {
+			sourceID, _ := neuron.connection(i)
+			if _, exists := bp.Neurons[sourceID]; !exists {
+				continue
+			}
+			sourceDepth, err := visit(sourceID)
+			if err != nil {
+				return 0, err
+			}
+			if sourceDepth+1 > d {
+				d = sourceDepth + 1
+			}
+		}
+
+		visiting[id] = false
+		depth[id] = d
+		return d, nil
+	}
+
+	neuronIDs := bp.getAllNeuronIDs()
+	sort.Ints(neuronIDs)
+
+	layers := [][]int{{}}
+	for _, id := range neuronIDs {
+		d, err := visit(id)
+		if err != nil {
+			return nil, err
+		}
+		for len(layers) <= d {
+			layers = append(layers, []int{})
+		}
+		layers[d] = append(layers[d], id)
+	}
+
+	return layers, nil
+}
@@ -0,0 +1,65 @@
+package blueprint
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// graphBlueprint builds a blueprint with input 1 and a dense neuron for each key of sources, reading from the
+// listed neurons.
+func graphBlueprint(sources map[int][]int) *Blueprint {
+	bp := NewBlueprint()
+	bp.AddInputNeurons([]int{1})
+	for id, ids := range sources {
+		neuron := &Neuron{ID: id, Type: "dense", Activation: "linear"}
+		for _, sourceID := range ids {
+			neuron.Connections = append(neuron.Connections, []float64{float64(sourceID), 1})
+		}
+		bp.Neurons[id] = neuron
+	}
+	return bp
+}
+
+func TestTopologicalOrder(t *testing.T) {
+	for name, tc := range map[string]struct {
+		sources map[int][]int
+		want    []int
+	}{
+		// IDs against the data flow: 1 -> 5 -> 3 -> 4
+		"chain":   {map[int][]int{5: {1}, 3: {5}, 4: {3}}, []int{1, 5, 3, 4}},
+		"diamond": {map[int][]int{2: {1}, 3: {1}, 4: {2, 3}}, []int{1, 2, 3, 4}},
+		// Neuron 9 is a separate component and neuron 7 reads from a missing neuron
+		"disconnected": {map[int][]int{2: {1}, 7: {8}, 9: {}}, []int{1, 2, 7, 9}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			order, err := graphBlueprint(tc.sources).TopologicalOrder()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(order, tc.want) {
+				t.Errorf("got order %v, want %v", order, tc.want)
+			}
+		})
+	}
+}
+
+func TestTopologicalOrderReportsCycle(t *testing.T) {
+	// 2 -> 3 -> 4 -> 2, fed by input 1
+	bp := graphBlueprint(map[int][]int{2: {1, 4}, 3: {2}, 4: {3}})
+	order, err := bp.TopologicalOrder()
+	if !errors.Is(err, ErrCycle) {
+		t.Fatalf("got order %v and error %v, want ErrCycle", order, err)
+	}
+	if !strings.HasSuffix(err.Error(), "2 -> 3 -> 4 -> 2") {
+		t.Errorf("error %q does not list the cycle 2 -> 3 -> 4 -> 2", err)
+	}
+
+	// Connections held by an input node never make a cycle
+	bp = graphBlueprint(map[int][]int{2: {1}})
+	bp.Neurons[1].Connections = [][]float64{{2, 1}}
+	if order, err := bp.TopologicalOrder(); err != nil || !slices.Equal(order, []int{1, 2}) {
+		t.Errorf("got order %v and error %v, want [1 2]", order, err)
+	}
+}
@@ -0,0 +1,52 @@
+package blueprint
+
+import "fmt"
+
+// Logger receives the messages a blueprint reports while it runs: progress of training and search at the Info
+// level, problems it recovers from at the Warn level, and per-neuron tracing at the Debug level, which is only
+// sent when the blueprint's Debug flag is set. Formats follow fmt.Printf and carry no trailing newline.
+// A blueprint without a Logger discards every message; set it to StdoutLogger{} to print them.
+type Logger interface {
+	Debugf(format string, args ...any)
+	Infof(format string, args ...any)
+	Warnf(format string, args ...any)
+}
+
+// StdoutLogger prints every message to standard output on its own line.
+type StdoutLogger struct{}
+
+func (StdoutLogger) Debugf(format string, args ...any) { fmt.Printf(format+"\n", args...) }
+func (StdoutLogger) Infof(format string, args ...any)  { fmt.Printf(format+"\n", args...) }
+func (StdoutLogger) Warnf(format string, args ...any)  { fmt.Printf(format+"\n", args...) }
+
+// noopLogger discards every message.
+type noopLogger struct{}
+
+func (noopLogger) Debugf(string, ...any) {}
+func (noopLogger) Infof(string, ...any)  {}
+func (noopLogger) Warnf(string, ...any)  {}
+
+// logger returns the blueprint's Logger, or one that discards every message when none is set.
+func (bp *Blueprint) logger() Logger {
+	if bp.Logger == nil {
+		return noopLogger{}
+	}
+	return bp.Logger
+}
+
+// debugf sends a debug message to the logger when Debug is set.
+func (bp *Blueprint) debugf(format string, args ...any) {
+	if bp.Debug {
+		bp.logger().Debugf(format, args...)
+	}
+}
+
+// infof sends an informational message to the logger.
+func (bp *Blueprint) infof(format string, args ...any) {
+	bp.logger().Infof(format, args...)
+}
+
+// warnf sends a warning to the logger.
+func (bp *Blueprint) warnf(format string, args ...any) {
+	bp.logger().Warnf(format, args...)
+}
@@ -0,0 +1,72 @@
+package blueprint
+
+import (
+	"io"
+	"os"
+	"testing"
+)
+
+// captureStdout returns what fn prints to standard output.
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+	fn()
+	w.Close()
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(out)
+}
+
+func TestLoggerReceivesMessagesAndDebugIsGated(t *testing.T) {
+	// Output 4 has no sources, so validation warns about it
+	bp := evalTestBlueprint()
+	bp.AddOutputNeurons([]int{4}, "linear")
+	path := t.TempDir() + "/model.json"
+	run := func() {
+		bp.RunNetwork(map[int]float64{1: 1}, 1)
+		bp.ValidateConnections()
+		if err := bp.SaveToJSON(path); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	logger := &captureLogger{}
+	bp.Logger = logger
+	bp.Debug = true
+	if out := captureStdout(t, run); out != "" {
+		t.Errorf("blueprint printed %q with a Logger set", out)
+	}
+	if logger.count("debug: Dense Neuron 2") != 1 || logger.count("debug: Final Outputs") != 1 {
+		t.Errorf("debug messages were not routed to the logger: %q", logger.messages)
+	}
+	if logger.count("warn: Output Neuron 4 is not connected") != 1 {
+		t.Errorf("warning was not routed to the logger: %q", logger.messages)
+	}
+	if logger.count("info: Blueprint saved successfully") != 1 {
+		t.Errorf("info message was not routed to the logger: %q", logger.messages)
+	}
+
+	logger.messages = nil
+	bp.Debug = false
+	run()
+	if n := logger.count("debug:"); n != 0 {
+		t.Errorf("logger received %d debug messages with Debug off: %q", n, logger.messages)
+	}
+	if logger.count("warn:") != 1 || logger.count("info:") != 1 {
+		t.Errorf("with Debug off the logger got %q, want the warning and the info message", logger.messages)
+	}
+
+	bp.Logger = nil
+	bp.Debug = true
+	if out := captureStdout(t, run); out != "" {
+		t.Errorf("blueprint without a Logger printed %q", out)
+	}
+}
@@ -0,0 +1,225 @@
+package blueprint
+
+import (
+	"fmt"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// denseLayer holds the assembled weight matrix for one layer of dense neurons.
+type denseLayer struct {
+	neurons []*Neuron
+	slots   []int   // Value slot of each neuron in the layer
+	sources []int   // Value slots gathered into the input vector, one per matrix column
+	columns [][]int // Matrix column of each connection, per neuron (-1 if the source does not exist)
+	weights *mat.Dense
+	input   *mat.VecDense
+	output  *mat.VecDense
+}
+
+// matrixPlan caches the layer structure used by ForwardMatrix.
+type matrixPlan struct {
+	slots   map[int]int // Neuron ID to value slot
+	neurons []*Neuron   // Neuron stored in each value slot
+	values  []float64
+	layers  []*denseLayer
+}
+
+// isDenseNeuronType reports whether ProcessNeuron handles the type with the default dense computation.
+func isDenseNeuronType(neuronType string) bool {
+	switch neuronType {
+	case "nca", "rnn", "lstm", "cnn", "maxpool", "avgpool", "dropout", "batch_norm", "attention":
+		return false
+	}
+	return true
+}
+
+// invalidateCompiled drops any cached structure derived from the network topology.
+// It must be called whenever neurons or connections are added, removed or rewired.
+func (bp *Blueprint) invalidateCompiled() {
+	bp.compiledMatrix = nil
+	bp.compiledPlan = nil
+}
+
+// buildMatrixPlan assembles the per-layer weight matrices for a purely dense feed-forward network.
+func (bp *Blueprint) buildMatrixPlan() (*matrixPlan, error) {
+	if len(bp.QuantumNeurons) > 0 {
+		return nil, fmt.Errorf("quantum neurons are only processed by Forward")
+	}
+	layers, err := bp.ComputeLayers()
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &matrixPlan{slots: make(map[int]int, len(bp.Neurons))}
+	for _, layer := range layers {
+		for _, id := range layer {
+			plan.slots[id] = len(plan.neurons)
+			plan.neurons = append(plan.neurons, bp.Neurons[id])
+		}
+	}
+	plan.values = make([]float64, len(plan.neurons))
+
+	for _, layer := range layers[1:] {
+		dl := &denseLayer{}
+		sourceColumns := make(map[int]int)
+		for _, id := range layer {
+			neuron := bp.Neurons[id]
+			if neuron.Type == "input" {
+				continue
+			}
+			if !isDenseNeuronType(neuron.Type) {
+				return nil, fmt.Errorf("neuron %d has non-dense type '%s'", id, neuron.Type)
+			}
+
+			columns := make([]int, neuron.numConnections())
+			for i := range columns {
+				sourceID, _ := neuron.connection(i)
+				sourceSlot, exists := plan.slots[sourceID]
+				if !exists {
+					columns[i] = -1
+					continue
+				}
+				column, seen := sourceColumns[sourceSlot]
+				if !seen {
+					column = len(dl.sources)
+					sourceColumns[sourceSlot] = column
+					dl.sources = append(dl.sources, sourceSlot)
+				}
+				columns[i] = column
+			}
+
+			dl.neurons = append(dl.neurons, neuron)
+			dl.slots = append(dl.slots, plan.slots[id])
+			dl.columns = append(dl.columns, columns)
+		}
+		if len(dl.neurons) == 0 {
+			continue
+		}
+
+		// gonum refuses zero-sized matrices, so keep at least one (always zero) column
+		cols := len(dl.sources)
+		if cols == 0 {
+			cols = 1
+		}
+		dl.weights = mat.NewDense(len(dl.neurons), cols, nil)
+		dl.input = mat.NewVecDense(cols, nil)
+		dl.output = mat.NewVecDense(len(dl.neurons), nil)
+		plan.layers = append(plan.layers, dl)
+	}
+
+	return plan, nil
+}
+
+// loadWeights copies the current connection weights of the layer into its matrix.
+// Weights are re-read on every pass so in-place weight updates made by training are picked up.
+func (dl *denseLayer) loadWeights() {
+	raw := dl.weights.RawMatrix()
+	for i := range raw.Data {
+		raw.Data[i] = 0
+	}
+	for row, neuron := range dl.neurons {
+		offset := row * raw.Stride
+		for i, column := range dl.columns[row] {
+			if column < 0 {
+				continue
+			}
+			_, weight := neuron.connection(i)
+			raw.Data[offset+column] += weight
+		}
+	}
+}
+
+// ForwardMatrix runs a single forward pass using one matrix-vector product per layer
+// instead of the per-neuron loop in Forward. Neurons are evaluated layer by layer as
+// returned by ComputeLayers, so the result matches Forward with one timestep whenever
+// neuron IDs already follow the dependency order.
+// Networks that contain non-dense neuron types, quantum neurons or cycles fall back to Forward.
+func (bp *Blueprint) ForwardMatrix(inputs map[int]float64) (map[int]float64, error) {
+	for id := range inputs {
+		if _, exists := bp.Neurons[id]; !exists {
+			return nil, fmt.Errorf("input neuron %d does not exist", id)
+		}
+	}
+
+	if bp.compiledMatrix == nil {
+		plan, err := bp.buildMatrixPlan()
+		if err != nil {
+			bp.debugf("ForwardMatrix: %v. Falling back to Forward.", err)
+			bp.Forward(inputs, 1)
+			return bp.GetOutputs(), nil
+		}
+		bp.compiledMatrix = plan
+	}
+	plan := bp.compiledMatrix
+
+	for slot, neuron := range plan.neurons {
+		plan.values[slot] = neuron.Value
+	}
+	for id, value := range inputs {
+		plan.values[plan.slots[id]] = value
+		bp.Neurons[id].Value = value
+	}
+
+	for _, dl := range plan.layers {
+		dl.loadWeights()
+		for column, slot := range dl.sources {
+			dl.input.SetVec(column, plan.values[slot])
+		}
+		dl.output.MulVec(dl.weights, dl.input)
+
+		for row, neuron := range dl.neurons {
+			value := bp.ApplyScalarActivation(neuron.Bias+dl.output.AtVec(row), neuron.Activation)
+			plan.values[dl.slots[row]] = value
+			neuron.Value = value
+		}
+	}
+
+	bp.ApplySoftmax()
+	return bp.GetOutputs(), nil
+}
+
+// NewDenseMLP builds a fully connected feed-forward network with the given layer sizes.
+// The first size is the number of input neurons and the last the number of outputs.
+// Neuron IDs are assigned layer by layer starting at 1 and weights are drawn uniformly from [-1, 1].
+func NewDenseMLP(layerSizes []int, activation string) *Blueprint {
+	bp := NewBlueprint()
+	if len(layerSizes) == 0 {
+		return bp
+	}
+
+	nextID := 1
+	previous := []int{}
+	for l, size := range layerSizes {
+		current := make([]int, size)
+		for i := range current {
+			neuron := &Neuron{
+				ID:          nextID,
+				Type:        "dense",
+				Activation:  activation,
+				Connections: [][]float64{},
+			}
+			if l == 0 {
+				neuron.Type = "input"
+				neuron.Activation = "linear"
+			} else {
+				neuron.Bias = random.Float64()*2 - 1
+			}
+			for _, sourceID := range previous {
+				neuron.Connections = append(neuron.Connections, []float64{float64(sourceID), random.Float64()*2 - 1})
+			}
+			bp.Neurons[nextID] = neuron
+			current[i] = nextID
+			nextID++
+		}
+		if l == 0 {
+			bp.AddInputNodes(current)
+		}
+		previous = current
+	}
+	if len(layerSizes) > 1 {
+		bp.AddOutputNodes(previous)
+	}
+
+	return bp
+}
@@ -0,0 +1,54 @@
+package blueprint
+
+import (
+	"math"
+	"testing"
+)
+
+// wideMLP returns a 256-512-512-10 ReLU network and inputs for all its input neurons.
+func wideMLP() (*Blueprint, map[int]float64) {
+	randomSource.Seed(1)
+	bp := NewDenseMLP([]int{256, 512, 512, 10}, "relu")
+	inputs := make(map[int]float64, len(bp.InputNodes))
+	for i, id := range bp.InputNodes {
+		inputs[id] = float64(i%17) / 17
+	}
+	return bp, inputs
+}
+
+func TestForwardMatrixMatchesForward(t *testing.T) {
+	bp, inputs := wideMLP()
+	bp.Forward(inputs, 1)
+	want := bp.GetOutputs()
+
+	got, err := bp.DeepCopy().ForwardMatrix(inputs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for id, value := range want {
+		if math.Abs(got[id]-value) > 1e-9 {
+			t.Errorf("output %d is %v, Forward gives %v", id, got[id], value)
+		}
+	}
+}
+
+func BenchmarkForwardWideMLP(b *testing.B) {
+	bp, inputs := wideMLP()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		bp.Forward(inputs, 1)
+	}
+}
+
+func BenchmarkForwardMatrixWideMLP(b *testing.B) {
+	bp, inputs := wideMLP()
+	if _, err := bp.ForwardMatrix(inputs); err != nil {
+		b.Fatal(err)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := bp.ForwardMatrix(inputs); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package blueprint
+
+// MCDropoutPredict estimates the model's uncertainty on inputs with Monte Carlo dropout. The forward pass is run
+// samples times in training mode, so dropout neurons drop values at random, drawing from the package generator so
+// the result is reproducible under SetRandomSeed. TrainingMode is restored afterwards. It returns the mean and the
+// population variance of every output probability across the samples. A model without dropout neurons returns
+// zero variance.
+func (bp *Blueprint) MCDropoutPredict(inputs map[int]float64, timesteps, samples int) (meanProbs map[int]float64, variance map[int]float64) {
+	meanProbs = make(map[int]float64)
+	variance = make(map[int]float64)
+	if samples <= 0 {
+		bp.infof("MCDropoutPredict needs at least one sample.")
+		return meanProbs, variance
+	}
+	if bp.Debug && !bp.hasDropoutNeurons() {
+		bp.debugf("Warning: MCDropoutPredict on a model without dropout neurons; every sample is identical.")
+	}
+
+	training := bp.TrainingMode
+	bp.TrainingMode = true
+	defer func() { bp.TrainingMode = training }()
+
+	// Welford's online mean and variance
+	sumSquares := make(map[int]float64)
+	for s := 1; s <= samples; s++ {
+		bp.Forward(inputs, timesteps)
+		for id, p := range bp.GetOutputs() {
+			delta := p - meanProbs[id]
+			meanProbs[id] += delta / float64(s)
+			sumSquares[id] += delta * (p - meanProbs[id])
+		}
+	}
+	for id, total := range sumSquares {
+		variance[id] = total / float64(samples)
+	}
+	return meanProbs, variance
+}
+
+// hasDropoutNeurons reports whether the model contains a dropout neuron.
+func (bp *Blueprint) hasDropoutNeurons() bool {
+	for _, neuron := range bp.Neurons {
+		if neuron.Type == "dropout" && neuron.DropoutRate > 0 {
+			return true
+		}
+	}
+	return false
+}
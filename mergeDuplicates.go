@@ -0,0 +1,133 @@
+package blueprint
+
+import "math"
+
+// MergeDuplicateNeurons merges hidden neurons that compute nearly the same function and returns how many were
+// removed. Two hidden dense neurons are duplicates when they share their type and activation and the cosine
+// similarity of their incoming weights, with the bias as an extra component, is above cosineThreshold. The
+// neuron with the higher ID is removed and every connection reading from it is added onto the connection from
+// the neuron that is kept, so the downstream sums are unchanged when the two computed the same value.
+// Neurons connected to each other are never merged. Requires float64 connection storage.
+func (bp *Blueprint) MergeDuplicateNeurons(cosineThreshold float64) int {
+	if bp.LowPrecision {
+		bp.infof("Merging neurons requires float64 storage. Call ConvertToFloat64Storage first.")
+		return 0
+	}
+
+	candidates := []int{}
+	incoming := make(map[int]map[int]float64)
+	for _, id := range bp.getAllNeuronIDs() {
+		neuron := bp.Neurons[id]
+		if bp.isInputNode(id) || bp.isOutputNode(id) || neuron.Type == "input" || !isDenseNeuronType(neuron.Type) {
+			continue
+		}
+		weights := make(map[int]float64)
+		for _, conn := range neuron.Connections {
+			weights[int(conn[0])] += conn[1]
+		}
+		candidates = append(candidates, id)
+		incoming[id] = weights
+	}
+
+	merged := 0
+	removed := make(map[int]bool)
+	for i, keepID := range candidates {
+		if removed[keepID] {
+			continue
+		}
+		keep := bp.Neurons[keepID]
+		for _, dupID := range candidates[i+1:] {
+			if removed[dupID] {
+				continue
+			}
+			dup := bp.Neurons[dupID]
+			if dup.Type != keep.Type || dup.Activation != keep.Activation || dup.BatchNorm != keep.BatchNorm || dup.Attention != keep.Attention {
+				continue
+			}
+			if _, linked := incoming[keepID][dupID]; linked {
+				continue
+			}
+			if _, linked := incoming[dupID][keepID]; linked {
+				continue
+			}
+			if incomingCosine(incoming[keepID], keep.Bias, incoming[dupID], dup.Bias) <= cosineThreshold {
+				continue
+			}
+
+			bp.redirectOutgoing(dupID, keepID)
+			delete(bp.Neurons, dupID)
+			removed[dupID] = true
+			merged++
+			bp.debugf("Merged Neuron %d into Neuron %d.", dupID, keepID)
+		}
+	}
+
+	if merged > 0 {
+		bp.invalidateCompiled()
+	}
+	return merged
+}
+
+// redirectOutgoing moves every connection reading from fromID onto toID, adding its weight to an existing
+// connection from toID when the target already has one. The first connection from fromID becomes the one from
+// toID otherwise; the others are dropped together with their LSTM gate weights.
+func (bp *Blueprint) redirectOutgoing(fromID, toID int) {
+	for _, neuron := range bp.Neurons {
+		fromIndices := []int{}
+		existing := -1
+		movedWeight := 0.0
+		for i := 0; i < neuron.numConnections(); i++ {
+			sourceID, weight := neuron.connection(i)
+			switch {
+			case sourceID == fromID:
+				fromIndices = append(fromIndices, i)
+				movedWeight += weight
+			case sourceID == toID && existing < 0:
+				existing = i
+			}
+		}
+		if len(fromIndices) == 0 {
+			continue
+		}
+
+		dropped := fromIndices
+		if existing >= 0 {
+			_, weight := neuron.connection(existing)
+			neuron.setConnectionWeight(existing, weight+movedWeight)
+		} else {
+			neuron.setConnectionSource(fromIndices[0], toID)
+			neuron.setConnectionWeight(fromIndices[0], movedWeight)
+			dropped = fromIndices[1:]
+		}
+		if len(dropped) == 0 {
+			continue
+		}
+		kept := make([]int, 0, neuron.numConnections()-len(dropped))
+		for i, d := 0, 0; i < neuron.numConnections(); i++ {
+			if d < len(dropped) && dropped[d] == i {
+				d++
+				continue
+			}
+			kept = append(kept, i)
+		}
+		neuron.keepConnections(kept)
+	}
+}
+
+// incomingCosine returns the cosine similarity of two incoming weight vectors keyed by source ID, each extended
+// with its bias. It is 0 if either vector is zero.
+func incomingCosine(a map[int]float64, biasA float64, b map[int]float64, biasB float64) float64 {
+	dot := biasA * biasB
+	normA, normB := biasA*biasA, biasB*biasB
+	for source, wa := range a {
+		dot += wa * b[source]
+		normA += wa * wa
+	}
+	for _, wb := range b {
+		normB += wb * wb
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / math.Sqrt(normA*normB)
+}
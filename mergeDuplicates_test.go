@@ -0,0 +1,45 @@
+package blueprint
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestMergeDuplicateNeuronsKeepsLSTMGatesAligned(t *testing.T) {
+	bp := NewBlueprint()
+	bp.AddInputNeurons([]int{1})
+	bp.Neurons[2] = &Neuron{ID: 2, Type: "dense", Activation: "relu", Connections: [][]float64{{1, 0.5}}}
+	bp.Neurons[3] = &Neuron{ID: 3, Type: "dense", Activation: "relu", Connections: [][]float64{{1, 0.5}}}
+	bp.Neurons[4] = &Neuron{
+		ID: 4, Type: "lstm", Activation: "tanh",
+		Connections: [][]float64{{3, 0.25}, {1, 1}, {2, 0.5}},
+		GateWeights: map[string][]float64{
+			"input": {1, 2, 3}, "forget": {1, 2, 3}, "output": {1, 2, 3}, "cell": {1, 2, 3},
+		},
+	}
+	bp.Neurons[5] = &Neuron{ID: 5, Type: "dense", Activation: "linear", Connections: [][]float64{{3, 2}, {3, 1}}}
+	bp.AddOutputNodes([]int{4, 5})
+
+	if merged := bp.MergeDuplicateNeurons(0.99); merged != 1 {
+		t.Fatalf("merged %d neurons, want 1", merged)
+	}
+	if _, exists := bp.Neurons[3]; exists {
+		t.Fatal("the duplicate with the higher ID was kept")
+	}
+
+	lstm := bp.Neurons[4]
+	if want := [][]float64{{1, 1}, {2, 0.75}}; !slices.EqualFunc(lstm.Connections, want, slices.Equal) {
+		t.Errorf("LSTM connections are %v, want %v", lstm.Connections, want)
+	}
+	for gate, weights := range lstm.GateWeights {
+		if !slices.Equal(weights, []float64{2, 3}) {
+			t.Errorf("%s gate weights are %v, want [2 3]", gate, weights)
+		}
+	}
+	if want := [][]float64{{2, 3}}; !slices.EqualFunc(bp.Neurons[5].Connections, want, slices.Equal) {
+		t.Errorf("output connections are %v, want %v", bp.Neurons[5].Connections, want)
+	}
+	if problems := bp.Validate(); len(problems) > 0 {
+		t.Errorf("Validate reported %v", problems)
+	}
+}
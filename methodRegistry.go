@@ -0,0 +1,290 @@
+package blueprint
+
+import "sync"
+
+// methodDoc holds the documentation registered for a Blueprint method.
+type methodDoc struct {
+	Description string
+	Parameters  []ParameterInfo // Parameter types are filled in by reflection
+}
+
+var (
+	methodRegistryMu sync.RWMutex
+	methodRegistry   = map[string]methodDoc{}
+)
+
+// RegisterMethod annotates a Blueprint method with a description and the real names and descriptions
+// of its parameters, which reflection cannot see. GetBlueprintMethods uses the registered names
+// whenever their count matches the method signature and falls back to param1, param2, ... otherwise.
+// Registering a method again replaces its previous entry.
+func RegisterMethod(name, description string, params ...ParameterInfo) {
+	methodRegistryMu.Lock()
+	defer methodRegistryMu.Unlock()
+	methodRegistry[name] = methodDoc{Description: description, Parameters: params}
+}
+
+// Param builds a ParameterInfo for RegisterMethod.
+func Param(name, description string) ParameterInfo {
+	return ParameterInfo{Name: name, Description: description}
+}
+
+// lookupMethod returns the registered documentation for a method.
+func lookupMethod(name string) (methodDoc, bool) {
+	methodRegistryMu.RLock()
+	defer methodRegistryMu.RUnlock()
+	doc, exists := methodRegistry[name]
+	return doc, exists
+}
+
+func init() {
+	sessions := Param("sessions", "Sessions to evaluate or train on")
+	maxIterations := Param("maxIterations", "Maximum number of iterations")
+	neuronTypes := Param("neuronTypes", "Neuron types that may be inserted")
+	weightUpdateIterations := Param("weightUpdateIterations", "Hill-climbing steps per improvement")
+	useHillClimbing := Param("useHillClimbing", "Toggle for hill climbing")
+	saveImprovedModel := Param("saveImprovedModel", "Toggle for saving improved models")
+	saveLocation := Param("saveLocation", "Folder path to save improved models")
+	forgivenessThreshold := Param("forgivenessThreshold", "Forgiveness threshold (currently unused)")
+	neuron := Param("neuron", "Neuron to process")
+	inputs := Param("inputs", "Weighted input values")
+	data := Param("data", "Data produced by SerializeToGob")
+	ctx := Param("ctx", "Context that cancels the run")
+
+	// Construction and topology
+	RegisterMethod("AddInputNodes", "Registers input node IDs without creating neurons", Param("ids", "Input neuron IDs"))
+	RegisterMethod("AddOutputNodes", "Registers output node IDs without creating neurons", Param("ids", "Output neuron IDs"))
+	RegisterMethod("AddInputNeurons", "Registers input node IDs and creates missing input neurons", Param("ids", "Input neuron IDs"))
+	RegisterMethod("AddOutputNeurons", "Registers output node IDs and creates missing output neurons",
+		Param("ids", "Output neuron IDs"), Param("activation", "Activation of the created neurons"))
+	RegisterMethod("CheckInputCompatibility", "Checks that a sample provides exactly the model's input nodes",
+		Param("sample", "Input values keyed by neuron ID"))
+	RegisterMethod("RemapInputs", "Relabels input node IDs and the connections reading from them",
+		Param("oldToNew", "New ID of each remapped input node"))
+	RegisterMethod("InsertNeuronOfTypeBetweenInputsAndOutputs", "Inserts a neuron wired from the inputs to the outputs",
+		Param("neuronType", "Type of the inserted neuron"))
+	RegisterMethod("InsertNeuronWithRandomConnections", "Inserts a neuron with random incoming connections",
+		Param("neuronType", "Type of the inserted neuron"))
+	RegisterMethod("InsertNeuronWithRandomConnectionsAndReconnect", "Inserts a neuron and reconnects it to recent neurons",
+		Param("neuronType", "Type of the inserted neuron"), Param("reconnectToLastX", "Number of most recent neurons to reconnect"))
+	RegisterMethod("GetWeight", "Returns the weight of the connection from one neuron to another, if it exists",
+		Param("sourceID", "ID of the neuron the connection reads from"), Param("targetID", "ID of the neuron that stores the connection"))
+	RegisterMethod("SetWeight", "Replaces the weight of an existing connection from one neuron to another",
+		Param("sourceID", "ID of the neuron the connection reads from"), Param("targetID", "ID of the neuron that stores the connection"),
+		Param("weight", "New connection weight"))
+	RegisterMethod("InitializeWeights", "Redraws incoming weights scaled by each neuron's number of connections",
+		Param("strategy", "Initialization strategy: uniform, xavier or he"))
+	RegisterMethod("FreezeNeurons", "Keeps the weights and biases of the given neurons fixed during training",
+		Param("ids", "IDs of the neurons to freeze"))
+	RegisterMethod("UnfreezeAll", "Lets training change every neuron's weights and bias again")
+	RegisterMethod("RemoveNeuron", "Removes a neuron, its connections and its input and output node entries", Param("neuronID", "ID of the neuron to remove"))
+	RegisterMethod("MergeDuplicateNeurons", "Merges hidden neurons with nearly identical incoming weights",
+		Param("cosineThreshold", "Cosine similarity above which two neurons are merged"))
+	RegisterMethod("PruneConnections", "Removes connections with near-zero weights and the hidden neurons left unread",
+		Param("threshold", "Absolute weight below which a connection is removed"))
+	RegisterMethod("PruneConnectionsIfSafe", "Prunes near-zero connections unless that lowers accuracy by more than maxDrop",
+		Param("threshold", "Absolute weight below which a connection is removed"), sessions,
+		Param("maxDrop", "Largest accepted drop of any accuracy, in percentage points"))
+	RegisterMethod("ComputeLayers", "Groups neurons into feed-forward layers")
+	RegisterMethod("TopologicalOrder", "Returns the neuron IDs in dependency order or the cycle that prevents it")
+	RegisterMethod("AdjacencyMatrix", "Returns the weighted adjacency matrix and the neuron ID of each row")
+	RegisterMethod("SpectralRadius", "Estimates the largest eigenvalue magnitude of the adjacency matrix")
+	RegisterMethod("SpectralNormalize", "Divides each layer's weights by the largest singular value of its weight matrix")
+	RegisterMethod("PriorWarmStart", "Sets output biases so zero-input predictions match a class distribution",
+		Param("targetDistribution", "Probability of each output neuron"))
+	RegisterMethod("WeightOutliers", "Lists connections whose weight is far from the mean weight",
+		Param("zThreshold", "Distance from the mean in standard deviations"))
+	RegisterMethod("ClipWeightOutliers", "Clamps outlier weights to the threshold and returns how many changed",
+		Param("zThreshold", "Distance from the mean in standard deviations"))
+	RegisterMethod("SetLayerLearningRates", "Sets per-layer learning-rate multipliers",
+		Param("multipliers", "Multiplier per layer, starting with the input layer"))
+	RegisterMethod("Crossover", "Combines this blueprint with another", Param("other", "Second parent"))
+	RegisterMethod("DeepCopy", "Returns an independent copy of the blueprint without a JSON round trip")
+	RegisterMethod("Diff", "Lists the neurons, connections and weights another blueprint adds, removes or changes",
+		Param("other", "Blueprint compared against this one"))
+	RegisterMethod("Validate", "Returns every structural problem found in the blueprint")
+	RegisterMethod("ValidateEntanglements", "Checks that quantum entanglements are reciprocal and consistent")
+	RegisterMethod("RepairEntanglements", "Makes the quantum entanglement graph symmetric")
+
+	// Inference
+	RegisterMethod("Forward", "Runs the network",
+		Param("inputs", "Input values keyed by neuron ID"), Param("timesteps", "Number of timesteps to run"))
+	RegisterMethod("RunNetwork", "Runs the network",
+		Param("inputs", "Input values keyed by neuron ID"), Param("timesteps", "Number of timesteps to run"))
+	RegisterMethod("DetectStateExplosion", "Runs the network and reports the first timestep at which recurrent state exploded",
+		Param("inputs", "Input values keyed by neuron ID"), Param("timesteps", "Number of timesteps to run"))
+	RegisterMethod("ForwardMatrix", "Runs a single forward pass with one matrix product per layer",
+		Param("inputs", "Input values keyed by neuron ID"))
+	RegisterMethod("ForwardBatch", "Runs the network on a list of inputs, reusing buffers across them",
+		Param("inputsList", "Input values keyed by neuron ID, one map per run"), Param("timesteps", "Number of timesteps to run"))
+	RegisterMethod("GetOutputs", "Returns the output neuron values")
+	RegisterMethod("Predict", "Runs the network and returns its outputs, leaving the neuron values untouched",
+		Param("inputs", "Input values keyed by neuron ID"), Param("timesteps", "Number of timesteps to run"))
+	RegisterMethod("ForwardState", "Runs the network on a state local to the call and returns the outputs",
+		Param("inputs", "Input values keyed by neuron ID"), Param("timesteps", "Number of timesteps to run"))
+	RegisterMethod("Compile", "Compiles the blueprint into a cached ExecutionPlan")
+	RegisterMethod("ApplyScalarActivation", "Applies a named activation function",
+		Param("value", "Input to the activation"), Param("activation", "Name of the activation function"))
+	RegisterMethod("RegisterActivation", "Adds a custom activation function usable by this blueprint's neurons",
+		Param("name", "Name neurons use to select the activation"), Param("fn", "Activation function"))
+	RegisterMethod("ProcessNeuron", "Computes a neuron's value based on its type",
+		neuron, inputs, Param("timestep", "Current timestep"))
+	RegisterMethod("ProcessDenseNeuron", "Computes a dense neuron's value", neuron, inputs)
+	RegisterMethod("ProcessRNNNeuron", "Computes an RNN neuron's value", neuron, inputs)
+	RegisterMethod("ProcessLSTMNeuron", "Computes an LSTM neuron's value", neuron, inputs)
+	RegisterMethod("ProcessCNNNeuron", "Computes a CNN neuron's value", neuron, inputs)
+	RegisterMethod("ProcessNCANeuron", "Updates an NCA neuron from its neighborhood", neuron)
+	RegisterMethod("ProcessQuantumNeuron", "Handles quantum operations", Param("neuron", "Quantum neuron to process"))
+	RegisterMethod("ApplyDropout", "Randomly zeroes out a neuron's value", neuron)
+	RegisterMethod("ApplyBatchNormalization", "Normalizes a neuron's value with its running statistics", neuron)
+	RegisterMethod("ApplyAttention", "Adjusts a neuron's value based on attention weights",
+		neuron, inputs, Param("attentionWeights", "Weight per input"))
+	RegisterMethod("ComputeAttentionWeights", "Computes attention weights for the inputs", neuron, inputs)
+	RegisterMethod("InitializeKernel", "Creates a kernel with random weights", Param("kernelSize", "Number of kernel weights"))
+	RegisterMethod("RandomWeights", "Generates random connection weights", Param("size", "Number of weights"))
+
+	// Evaluation
+	RegisterMethod("EvaluateModelPerformance", "Returns exact, generous and forgiveness metrics", sessions)
+	RegisterMethod("Evaluate", "Returns the evaluation metrics as an EvaluationResult", sessions)
+	RegisterMethod("EvaluateWithMetrics", "Returns the mean score of each metric over the sessions, keyed by metric name",
+		sessions, Param("metrics", "Metrics to score, nil for exact, generous and forgiveness"))
+	RegisterMethod("CollectActivationStats", "Summarizes the value of every neuron over the sessions to find dead or saturated neurons",
+		sessions)
+	RegisterMethod("EvaluateOnSample", "Evaluates on a random subsample of the sessions",
+		sessions, Param("sampleSize", "Number of sessions to sample"), Param("seed", "Seed of the sampler"))
+	RegisterMethod("EvaluateModelPerformanceRegularized", "Returns the evaluation metrics plus a score penalized by the squared weights",
+		sessions, Param("l2Lambda", "Weight of the L2 penalty"))
+	RegisterMethod("L2Penalty", "Returns the sum of the squared connection weights")
+	RegisterMethod("NeuronAblation", "Measures the exact accuracy lost when each hidden neuron is silenced", sessions)
+	RegisterMethod("SmoothedMetrics", "Returns an exponential moving average of the model's sampled NAS evaluations",
+		Param("alpha", "Weight of the newest sample, between 0 and 1"))
+	RegisterMethod("ConfusionMatrix", "Tallies expected against predicted classes over the sessions", sessions,
+		Param("numClasses", "Number of classes, taken from the start of OutputNodes"))
+	RegisterMethod("EvaluationReport", "Returns a versioned JSON report of accuracies, confusion matrix, per-class and calibration metrics", sessions)
+	RegisterMethod("SessionDifficulty", "Scores each session by the cross-entropy loss of its expected class", sessions)
+	RegisterMethod("AdvancedEvaluateModelPerformance", "Returns the evaluation metrics plus advanced metrics", sessions)
+	RegisterMethod("RunTemporalSession", "Runs a sequence of timesteps and returns the outputs after each one",
+		Param("session", "Temporal session with the inputs and expected outputs of every timestep"))
+	RegisterMethod("EvaluateTemporalConsistency", "Scores how closely output changes between timesteps follow the expected changes",
+		Param("sessions", "Temporal sessions to evaluate"))
+
+	// Training and search
+	RegisterMethod("SetRandomSeed", "Seeds the generator used by all stochastic training methods",
+		Param("seed", "Seed of the generator"))
+	RegisterMethod("TrainBackprop", "Trains the dense neurons with gradient descent on the softmax cross-entropy",
+		sessions, Param("learningRate", "Step size of each update"), Param("epochs", "Passes over the sessions"))
+	RegisterMethod("GenerateAdversarial", "Perturbs an input with one fast gradient sign step",
+		Param("input", "Input values keyed by neuron ID"), Param("epsilon", "Size of the perturbation of each input"),
+		Param("targetClass", "Output neuron ID to push the prediction to, or -1 to push away from the current one"))
+	RegisterMethod("AdversarialTrain", "Trains on the sessions together with FGSM-perturbed copies of them",
+		sessions, Param("epsilon", "Size of the perturbation of each input"), Param("epochs", "Passes over the sessions"))
+	RegisterMethod("MCDropoutPredict", "Returns the mean and variance of the outputs over repeated passes with dropout active",
+		Param("inputs", "Input values keyed by neuron ID"), Param("timesteps", "Number of timesteps to run"),
+		Param("samples", "Number of forward passes"))
+	RegisterMethod("HillClimbWeightUpdate", "Perturbs one weight and keeps the change if it improves", sessions)
+	RegisterMethod("NumericalGradient", "Estimates the gradient of the squared error with respect to every connection weight by finite differences",
+		Param("session", "Session whose loss is differentiated"), Param("epsilon", "Weight offset, non-positive for 1e-5"))
+	RegisterMethod("AdamWeightUpdate", "Takes one Adam step on every weight using finite-difference gradients",
+		sessions, Param("lr", "Learning rate"), Param("beta1", "Decay of the first moment estimates"),
+		Param("beta2", "Decay of the second moment estimates"))
+	RegisterMethod("HillClimb", "Runs hill-climbing steps until patience consecutive steps fail to improve",
+		sessions, Param("maxIters", "Maximum number of steps"), Param("patience", "Failed steps in a row before stopping, 0 to run every step"))
+	RegisterMethod("EvolutionaryTrain", "Trains the blueprint with neuroevolution",
+		sessions, Param("populationSize", "Individuals per generation"), Param("generations", "Number of generations"),
+		Param("opts", "Optional EvolutionOptions"))
+	RegisterMethod("EvolutionaryTrainWithContext", "EvolutionaryTrain that stops when the context is cancelled",
+		ctx, sessions, Param("populationSize", "Individuals per generation"), Param("generations", "Number of generations"),
+		Param("opts", "Optional EvolutionOptions"))
+	RegisterMethod("TrainCMAES", "Optimizes the weights and biases of the fixed architecture with CMA-ES",
+		sessions, Param("populationSize", "Candidates per generation, below 2 uses the CMA-ES default"),
+		Param("generations", "Number of generations"), Param("opts", "Optional EvolutionOptions"))
+	RegisterMethod("SimpleNAS", "Adds neurons one at a time while they improve the model", sessions, maxIterations)
+	RegisterMethod("SimpleNASWithoutCrossover", "Adds neurons one at a time while they improve the selected metrics",
+		sessions, maxIterations, forgivenessThreshold, neuronTypes,
+		Param("metricsToOptimize", "Metrics that count as improvement: exact, generous, forgiveness"))
+	RegisterMethod("SimpleNASWithRandomConnections", "Sequential NAS with hill climbing",
+		sessions, maxIterations, forgivenessThreshold, neuronTypes, weightUpdateIterations)
+	RegisterMethod("SimpleNASWithConfig", "Sequential NAS configured by a NASConfig",
+		sessions, Param("cfg", "Search configuration"))
+	RegisterMethod("SimpleNASWithContext", "SimpleNASWithConfig that stops when the context is cancelled",
+		ctx, sessions, Param("cfg", "Search configuration"))
+	RegisterMethod("ParallelNAS", "Parallel NAS configured by a NASConfig",
+		sessions, Param("cfg", "Search configuration"))
+	RegisterMethod("ParallelNASWithContext", "ParallelNAS that stops when the context is cancelled",
+		ctx, sessions, Param("cfg", "Search configuration"))
+	RegisterMethod("ResumeNAS", "Loads a ParallelNAS checkpoint and continues the search from it",
+		Param("checkpointDir", "Directory the checkpoint was written to"), sessions, Param("cfg", "Search configuration"))
+	RegisterMethod("AdaptiveTypeSampler", "Returns a sampler of neuron types that favours types that improved the model")
+	RegisterMethod("DiagnoseStuckSearch", "Describes signs in a training history that the search is broken rather than converged",
+		Param("history", "History recorded during the search"))
+	RegisterMethod("NeuronTypeStats", "Returns the per-type statistics learned by adaptive NAS")
+	RegisterMethod("ParallelSimpleNASWithRandomConnections", "Parallel NAS with one candidate per CPU core",
+		sessions, maxIterations, neuronTypes, weightUpdateIterations, useHillClimbing, saveImprovedModel, saveLocation)
+	RegisterMethod("ParallelSimpleNASWithContext", "ParallelSimpleNASWithRandomConnections that stops when the context is cancelled",
+		ctx, sessions, maxIterations, neuronTypes, weightUpdateIterations, useHillClimbing, saveImprovedModel, saveLocation)
+	RegisterMethod("AdvancedParallelSimpleNASWithRandomConnections", "Parallel NAS scored with advanced metrics",
+		sessions, maxIterations, neuronTypes, weightUpdateIterations, useHillClimbing, saveImprovedModel, saveLocation)
+	RegisterMethod("AdvancedParallelNASWithDynamicNeuronGeneration", "Parallel NAS that grows the number of inserted neurons",
+		sessions, maxIterations, neuronTypes, weightUpdateIterations, useHillClimbing, saveImprovedModel, saveLocation,
+		Param("maxTriesWithoutImprovement", "Iterations without improvement before inserting more neurons"),
+		Param("batchSize", "Candidates evaluated per iteration"))
+	RegisterMethod("LearnOneDataItemAtATime", "Tries modifications per session and keeps batches that improve the model",
+		sessions, Param("maxAttemptsPerSession", "Modification attempts per session"), neuronTypes,
+		Param("batchSize", "Sessions processed per batch"))
+	RegisterMethod("ModificationStats", "Returns how often each modification type improved the model in LearnOneDataItemAtATime")
+	RegisterMethod("TargetedMicroRefinement", "Makes small weight tweaks focused on near-miss samples",
+		sessions, maxIterations, Param("sampleSubsetSize", "Samples examined per iteration"),
+		Param("connectionTrialsPerSample", "Connection changes tried per sample"),
+		Param("improvementThreshold", "Minimum improvement to accept a change"), Param("opts", "Optional RefinementOptions, such as a progress callback, perturbation schedule or evaluation batch"))
+	RegisterMethod("TargetedMicroRefinementWithContext", "TargetedMicroRefinement that stops when the context is cancelled",
+		ctx, sessions, maxIterations, Param("sampleSubsetSize", "Samples examined per iteration"),
+		Param("connectionTrialsPerSample", "Connection changes tried per sample"),
+		Param("improvementThreshold", "Minimum improvement to accept a change"), Param("opts", "Optional RefinementOptions, such as a progress callback, perturbation schedule or evaluation batch"))
+	RegisterMethod("TryAddConnections", "Tries random new connections and keeps improving ones",
+		sessions, Param("maxAttempts", "Number of connections to try"))
+
+	// Serialization
+	RegisterMethod("SerializeToJSON", "Serializes the blueprint to JSON")
+	RegisterMethod("DeserializesFromJSON", "Restores the blueprint from JSON", Param("data", "JSON produced by SerializeToJSON"))
+	RegisterMethod("SerializeToGob", "Serializes the blueprint losslessly to gob")
+	RegisterMethod("DeserializeFromGob", "Restores the blueprint from gob", data)
+	RegisterMethod("GenerateGoCode", "Emits dependency-free Go source implementing the forward pass with baked-in weights",
+		Param("packageName", "Package of the generated file"))
+	RegisterMethod("ToLayeredJSON", "Describes a feed-forward network as a Keras-like sequence of layers")
+	RegisterMethod("SaveToJSON", "Writes the blueprint to a JSON file", Param("fileName", "Destination file"))
+	RegisterMethod("LoadNeurons", "Loads neurons from JSON", Param("jsonData", "JSON encoded neurons"))
+	RegisterMethod("SerializationFidelity", "Returns the largest weight drift caused by a JSON round-trip")
+	RegisterMethod("Hash", "Returns a stable SHA-256 digest of the model's structure and weights")
+	RegisterMethod("ConvertToFloat32Storage", "Stores connection weights as float32 for inference")
+	RegisterMethod("ConvertToFloat64Storage", "Restores float64 connection storage for training")
+	RegisterMethod("ConnectionMemoryBytes", "Estimates the memory used by connection lists")
+	RegisterMethod("ParameterCount", "Returns the number of trainable values in the model")
+	RegisterMethod("ToDOT", "Returns the network as a GraphViz digraph")
+	RegisterMethod("ExportONNX", "Writes a feed-forward dense network as an ONNX model", Param("path", "File to write"))
+	RegisterMethod("EffectiveParameters", "Returns the parameter count after merging duplicate connections and folding linear chains")
+	RegisterMethod("NeuronTypeCounts", "Returns the number of neurons of each type")
+	RegisterMethod("TotalConnections", "Returns the number of stored connections")
+	RegisterMethod("FillModelMetadata", "Sets the structural fields of a ModelMetadata from the model",
+		Param("meta", "Metadata to update"))
+	RegisterMethod("RecommendWorkerCount", "Returns how many parallel NAS workers fit in memory", sessions)
+	RegisterMethod("EvaluateAndLogPerformance", "Evaluates each session and logs its metrics",
+		sessions, Param("logger", "Logger receiving one record per session"))
+
+	// Service
+	RegisterMethod("ServeHTTP", "Dispatches JSON method calls received over HTTP",
+		Param("w", "Response writer"), Param("r", "Incoming request"))
+	RegisterMethod("PredictHandler", "Returns an HTTP handler that runs inference on POSTed inputs")
+	RegisterMethod("RPCHandler", "Returns an HTTP handler like ServeHTTP that also accepts authorized deserialization calls",
+		Param("authorize", "Reports whether a request may be served"))
+
+	// Utilities and benchmarks
+	RegisterMethod("DownloadFile", "Downloads a file from a URL",
+		Param("filepath", "Destination path"), Param("url", "Source URL"))
+	RegisterMethod("UnzipFile", "Decompresses a .gz file",
+		Param("gzFile", "Path of the .gz file"), Param("targetDir", "Directory to extract into"))
+	RegisterMethod("RunBenchmark", "Runs the floating-point benchmarks", Param("duration", "Duration of each benchmark"))
+	RegisterMethod("PerformFloat32Ops", "Runs float32 multiply-add operations", Param("count", "Number of operations"))
+	RegisterMethod("PerformFloat64Ops", "Runs float64 multiply-add operations", Param("count", "Number of operations"))
+	RegisterMethod("EstimateMaxLayersAndNodes", "Estimates the largest network the measured throughput supports",
+		Param("ops32", "Measured float32 operations"), Param("ops64", "Measured float64 operations"))
+	RegisterMethod("FormatNumber", "Formats a number with a magnitude suffix", Param("num", "Number to format"))
+}
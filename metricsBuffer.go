@@ -0,0 +1,95 @@
+// blueprint/metricsBuffer.go
+package blueprint
+
+import (
+	"sync"
+	"time"
+)
+
+// NASMetrics holds the metrics recorded for one NAS iteration or evolutionary generation.
+type NASMetrics struct {
+	Iteration           int
+	ExactAccuracy       float64
+	GenerousAccuracy    float64
+	ForgivenessAccuracy float64
+	NeuronCount         int
+	Improved            bool
+	Timestamp           time.Time
+	CandidateSpread     float64 // How far the candidates' scores strayed from the best model's; 0 when they all scored identically
+}
+
+// MetricsBuffer is a fixed-capacity ring buffer of NASMetrics that is safe for concurrent use.
+// Training loops push into it while monitoring code reads recent entries, without any disk I/O.
+type MetricsBuffer struct {
+	mu      sync.Mutex
+	entries []NASMetrics
+	next    int // Index the next entry is written to
+	count   int // Number of valid entries
+}
+
+// NewMetricsBuffer creates a MetricsBuffer holding at most capacity entries.
+// A non-positive capacity is treated as 1.
+func NewMetricsBuffer(capacity int) *MetricsBuffer {
+	if capacity <= 0 {
+		capacity = 1
+	}
+	return &MetricsBuffer{entries: make([]NASMetrics, capacity)}
+}
+
+// Push appends an entry, overwriting the oldest one when the buffer is full.
+// Pushing to a nil buffer is a no-op, so trainers can push unconditionally.
+func (b *MetricsBuffer) Push(m NASMetrics) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if m.Timestamp.IsZero() {
+		m.Timestamp = time.Now()
+	}
+	b.entries[b.next] = m
+	b.next = (b.next + 1) % len(b.entries)
+	if b.count < len(b.entries) {
+		b.count++
+	}
+}
+
+// Recent returns a copy of up to n of the most recent entries, oldest first.
+// A non-positive n returns every buffered entry.
+func (b *MetricsBuffer) Recent(n int) []NASMetrics {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if n <= 0 || n > b.count {
+		n = b.count
+	}
+	result := make([]NASMetrics, n)
+	start := b.next - n
+	if start < 0 {
+		start += len(b.entries)
+	}
+	for i := range result {
+		result[i] = b.entries[(start+i)%len(b.entries)]
+	}
+	return result
+}
+
+// Len returns the number of buffered entries.
+func (b *MetricsBuffer) Len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.count
+}
+
+// ProgressFunc is called by a training loop after every iteration or generation with the metrics of the best
+// model so far. Returning a non-nil error stops the loop, which keeps the best model found so far.
+type ProgressFunc func(iteration int, best NASMetrics) error
+
+// call invokes the callback, doing nothing when it is nil, so trainers can call it unconditionally.
+func (fn ProgressFunc) call(iteration int, best NASMetrics) error {
+	if fn == nil {
+		return nil
+	}
+	return fn(iteration, best)
+}
@@ -0,0 +1,69 @@
+package blueprint
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestProgressCallbacksCountAndStop(t *testing.T) {
+	sessions := xorSessions()
+	errStop := errors.New("stop")
+	trainers := map[string]func(iterations int, progress ProgressFunc) error{
+		"SimpleNAS": func(iterations int, progress ProgressFunc) error {
+			bp := NewDenseMLP([]int{2, 3, 2}, "relu")
+			return bp.SimpleNASWithContext(context.Background(), sessions, NASConfig{
+				MaxIterations: iterations, NeuronTypes: []string{"dense"}, WeightUpdateIterations: 2, OnIteration: progress,
+			})
+		},
+		"EvolutionaryTrain": func(iterations int, progress ProgressFunc) error {
+			bp := NewDenseMLP([]int{2, 3, 2}, "relu")
+			return bp.EvolutionaryTrainWithContext(context.Background(), sessions, 4, iterations, WithProgressCallback(progress))
+		},
+		"TargetedMicroRefinement": func(iterations int, progress ProgressFunc) error {
+			// Refinement only runs on near misses, which the untrained soft regression network has
+			bp, sessions := softRegressionTask()
+			return bp.TargetedMicroRefinementWithContext(context.Background(), sessions, iterations, 2, 2, 100,
+				WithRefinementProgress(progress))
+		},
+	}
+	for name, train := range trainers {
+		t.Run(name, func(t *testing.T) {
+			randomSource.Seed(1)
+			calls := []int{}
+			record := func(iteration int, best NASMetrics) error {
+				if best.Iteration != iteration {
+					t.Errorf("call %d reported metrics of iteration %d", iteration, best.Iteration)
+				}
+				calls = append(calls, iteration)
+				return nil
+			}
+			if err := train(4, record); err != nil {
+				t.Fatal(err)
+			}
+			if len(calls) == 0 || len(calls) > 4 {
+				t.Fatalf("callback ran for iterations %v, want one call per iteration up to 4", calls)
+			}
+			for i, iteration := range calls {
+				if iteration != i+1 {
+					t.Fatalf("callback ran for iterations %v, want 1, 2, ...", calls)
+				}
+			}
+			if name != "TargetedMicroRefinement" && len(calls) != 4 {
+				t.Errorf("callback ran %d times, want 4", len(calls))
+			}
+
+			calls = nil
+			stopAtFirst := func(iteration int, best NASMetrics) error {
+				calls = append(calls, iteration)
+				return errStop
+			}
+			if err := train(4, stopAtFirst); !errors.Is(err, errStop) {
+				t.Errorf("returned %v, want the callback's error", err)
+			}
+			if len(calls) != 1 {
+				t.Errorf("callback ran %d times after asking to stop, want 1", len(calls))
+			}
+		})
+	}
+}
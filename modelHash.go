@@ -0,0 +1,275 @@
+package blueprint
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"hash"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// maxEvaluationCacheEntries bounds the evaluation cache; it is cleared when full.
+const maxEvaluationCacheEntries = 4096
+
+// evaluationCache maps a model hash combined with a session hash to the metrics returned by EvaluateModelPerformance.
+var evaluationCache = struct {
+	sync.Mutex
+	entries map[string]EvaluationResult
+}{entries: make(map[string]EvaluationResult)}
+
+// modelHasher writes values into a hash in a fixed binary layout.
+type modelHasher struct {
+	h   hash.Hash
+	buf [8]byte
+}
+
+func (m *modelHasher) int(v int) {
+	binary.LittleEndian.PutUint64(m.buf[:], uint64(int64(v)))
+	m.h.Write(m.buf[:])
+}
+
+func (m *modelHasher) float(v float64) {
+	binary.LittleEndian.PutUint64(m.buf[:], math.Float64bits(v))
+	m.h.Write(m.buf[:])
+}
+
+func (m *modelHasher) bool(v bool) {
+	if v {
+		m.int(1)
+	} else {
+		m.int(0)
+	}
+}
+
+func (m *modelHasher) string(v string) {
+	m.int(len(v))
+	m.h.Write([]byte(v))
+}
+
+func (m *modelHasher) floats(v []float64) {
+	m.int(len(v))
+	for _, f := range v {
+		m.float(f)
+	}
+}
+
+func (m *modelHasher) complexes(v []complex128) {
+	m.int(len(v))
+	for _, c := range v {
+		m.float(real(c))
+		m.float(imag(c))
+	}
+}
+
+func (m *modelHasher) ints(v []int) {
+	m.int(len(v))
+	for _, i := range v {
+		m.int(i)
+	}
+}
+
+// floatMap writes a map in ascending key order so map iteration order never affects the hash.
+func (m *modelHasher) floatMap(v map[int]float64) {
+	keys := make([]int, 0, len(v))
+	for k := range v {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	m.int(len(keys))
+	for _, k := range keys {
+		m.int(k)
+		m.float(v[k])
+	}
+}
+
+// Hash returns a stable SHA-256 hex digest of the model: its input and output nodes and every
+// neuron's type, activation, bias, weights and configuration, visited in sorted ID order.
+// Runtime state such as neuron values, LSTM cell state and quantum state is excluded, so
+// running the model does not change its hash while any change to a weight does.
+func (bp *Blueprint) Hash() string {
+	m := &modelHasher{h: sha256.New()}
+	m.ints(bp.InputNodes)
+	m.ints(bp.OutputNodes)
+
+	neuronIDs := bp.getAllNeuronIDs()
+	sort.Ints(neuronIDs)
+	m.int(len(neuronIDs))
+	for _, id := range neuronIDs {
+		neuron := bp.Neurons[id]
+		m.int(id)
+		m.string(neuron.Type)
+		m.string(neuron.Activation)
+		m.float(neuron.Bias)
+		m.int(neuron.numConnections())
+		for i := 0; i < neuron.numConnections(); i++ {
+			sourceID, weight := neuron.connection(i)
+			m.int(sourceID)
+			m.float(weight)
+		}
+		m.int(neuron.LoopCount)
+		if neuron.Type == "rnn" {
+			m.float(neuron.recurrentWeight())
+		}
+		m.int(neuron.WindowSize)
+		m.float(neuron.DropoutRate)
+		m.bool(neuron.BatchNorm)
+		m.bool(neuron.BatchNormParams != nil)
+		if neuron.BatchNormParams != nil {
+			m.floats([]float64{neuron.BatchNormParams.Gamma, neuron.BatchNormParams.Beta, neuron.BatchNormParams.Mean, neuron.BatchNormParams.Var})
+		}
+		m.bool(neuron.Attention)
+		m.floats(neuron.AttentionWeights)
+		m.int(len(neuron.Kernels))
+		for _, kernel := range neuron.Kernels {
+			m.floats(kernel)
+		}
+		if neuron.InputShape != [2]int{} || neuron.KernelShape != [2]int{} || neuron.Stride > 1 || neuron.Padding != "" {
+			m.ints(neuron.InputShape[:])
+			m.ints(neuron.KernelShape[:])
+			m.int(max(neuron.Stride, 1))
+			m.string(neuron.Padding)
+		}
+		if neuron.Type == "maxpool" || neuron.Type == "avgpool" {
+			// A pooling stride of 0 follows the pool size, so it differs from 1
+			m.int(neuron.PoolSize)
+			m.int(neuron.Stride)
+		}
+		gates := make([]string, 0, len(neuron.GateWeights))
+		for gate := range neuron.GateWeights {
+			gates = append(gates, gate)
+		}
+		sort.Strings(gates)
+		m.int(len(gates))
+		for _, gate := range gates {
+			m.string(gate)
+			m.floats(neuron.GateWeights[gate])
+		}
+		m.ints(neuron.NeighborhoodIDs)
+		m.string(neuron.UpdateRules)
+	}
+
+	quantumIDs := make([]int, 0, len(bp.QuantumNeurons))
+	for id := range bp.QuantumNeurons {
+		quantumIDs = append(quantumIDs, id)
+	}
+	sort.Ints(quantumIDs)
+	m.int(len(quantumIDs))
+	for _, id := range quantumIDs {
+		neuron := bp.QuantumNeurons[id]
+		m.int(id)
+		m.int(neuron.CNOTTarget)
+		m.int(len(neuron.Connections))
+		for _, conn := range neuron.Connections {
+			m.complexes(conn)
+		}
+		m.int(len(neuron.QuantumGates))
+		for _, gate := range neuron.QuantumGates {
+			m.string(gate.Type)
+			m.float(gate.Angle)
+			m.int(len(gate.Matrix))
+			for _, row := range gate.Matrix {
+				m.complexes(row)
+			}
+		}
+	}
+
+	return hex.EncodeToString(m.h.Sum(nil))
+}
+
+// hashSessions returns a stable digest of the sessions' inputs, expected outputs and timesteps.
+func hashSessions(sessions []Session) string {
+	m := &modelHasher{h: sha256.New()}
+	m.int(len(sessions))
+	for _, session := range sessions {
+		m.floatMap(session.InputVariables)
+		m.floatMap(session.ExpectedOutput)
+		m.int(session.Timesteps)
+	}
+	return hex.EncodeToString(m.h.Sum(nil))
+}
+
+// isStateless reports whether every forward pass depends only on its inputs, so evaluation results
+// can be cached. Forward visits every neuron in ascending ID order, so this requires every neuron to be
+// dense and every connection to read from an input or a neuron with a lower ID; sources that are not in
+// bp.Neurons are skipped by Forward and do not matter.
+func (bp *Blueprint) isStateless() bool {
+	if len(bp.QuantumNeurons) > 0 {
+		return false // Quantum measurements are random
+	}
+	for id, neuron := range bp.Neurons {
+		if neuron.Type == "input" {
+			continue
+		}
+		if !isDenseNeuronType(neuron.Type) {
+			return false
+		}
+		for i := 0; i < neuron.numConnections(); i++ {
+			sourceID, _ := neuron.connection(i)
+			source, exists := bp.Neurons[sourceID]
+			if !exists || source.Type == "input" || bp.isInputNode(sourceID) {
+				continue
+			}
+			if sourceID >= id {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// usesBuiltinActivations reports whether every neuron's activation resolves to the built-in function of that
+// name. The model hash only covers activation names, so evaluations of models using activations added or
+// redefined with RegisterActivation, or without any activation map, are not cached: the same name may stand for
+// different functions.
+func (bp *Blueprint) usesBuiltinActivations() bool {
+	for _, neuron := range bp.Neurons {
+		if neuron.Type == "input" {
+			continue
+		}
+		builtin, isBuiltin := scalarActivationFunctions[neuron.Activation]
+		fn, registered := bp.ScalarActivationMap[neuron.Activation]
+		if !isBuiltin || !registered || reflect.ValueOf(fn).Pointer() != reflect.ValueOf(builtin).Pointer() {
+			return false
+		}
+	}
+	return true
+}
+
+// cachedEvaluation returns the cache key for evaluating the model on the sessions and the cached
+// result if there is one. The key is empty when the model is not stateless or uses custom activations,
+// and cannot be cached. The decile step is part of the key since it changes forgiveness accuracy.
+func (bp *Blueprint) cachedEvaluation(sessions []Session) (string, EvaluationResult, bool) {
+	if len(sessions) == 0 || !bp.isStateless() || !bp.usesBuiltinActivations() {
+		return "", EvaluationResult{}, false
+	}
+	key := bp.Hash() + hashSessions(sessions) + strconv.FormatFloat(bp.decileStep(), 'g', -1, 64)
+
+	evaluationCache.Lock()
+	defer evaluationCache.Unlock()
+	result, found := evaluationCache.entries[key]
+	return key, result, found
+}
+
+// storeEvaluation records an evaluation result under a key returned by cachedEvaluation.
+func storeEvaluation(key string, result EvaluationResult) {
+	if key == "" {
+		return
+	}
+	evaluationCache.Lock()
+	defer evaluationCache.Unlock()
+	if len(evaluationCache.entries) >= maxEvaluationCacheEntries {
+		evaluationCache.entries = make(map[string]EvaluationResult)
+	}
+	evaluationCache.entries[key] = result
+}
+
+// ClearEvaluationCache discards every cached evaluation result.
+func ClearEvaluationCache() {
+	evaluationCache.Lock()
+	defer evaluationCache.Unlock()
+	evaluationCache.entries = make(map[string]EvaluationResult)
+}
@@ -3,12 +3,18 @@ package blueprint
 import (
 	"encoding/json"
 	"fmt"
-	"math/rand"
+	"sync"
 )
 
 // InsertNeuronOfTypeBetweenInputsAndOutputs inserts a new neuron of the specified type
 // between all input and output nodes without removing existing connections.
+// Several goroutines may insert neurons into the same blueprint at once.
 func (bp *Blueprint) InsertNeuronOfTypeBetweenInputsAndOutputs(neuronType string) error {
+	// Hold the blueprint's lock across ID allocation, the map insert and the rewiring
+	mu := bp.neuronLock()
+	mu.Lock()
+	defer mu.Unlock()
+
 	// Validate the neuron type
 	if !bp.isValidNeuronType(neuronType) {
 		return fmt.Errorf("invalid neuron type: %s", neuronType)
@@ -28,38 +34,38 @@ func (bp *Blueprint) InsertNeuronOfTypeBetweenInputsAndOutputs(neuronType string
 
 	// Add the new neuron to the Blueprint
 	bp.Neurons[newNeuronID] = newNeuron
-	if bp.Debug {
-		fmt.Printf("Inserted new Neuron with ID %d of type '%s'.\n", newNeuronID, neuronType)
-	}
+	bp.invalidateCompiled()
+	bp.debugf("Inserted new Neuron with ID %d of type '%s'.", newNeuronID, neuronType)
 
 	// Randomly connect the new neuron to other neurons in the network
 	existingNeuronIDs := bp.getAllNeuronIDs()
-	rand.Shuffle(len(existingNeuronIDs), func(i, j int) {
+	random.Shuffle(len(existingNeuronIDs), func(i, j int) {
 		existingNeuronIDs[i], existingNeuronIDs[j] = existingNeuronIDs[j], existingNeuronIDs[i]
 	})
 
 	// Determine the number of connections (random between 1 and total neurons)
-	numConnections := rand.Intn(len(existingNeuronIDs)) + 1
+	numConnections := random.Intn(len(existingNeuronIDs)) + 1
 
 	// Add random connections to the new neuron
 	for i := 0; i < numConnections; i++ {
 		targetID := existingNeuronIDs[i]
-		weight := rand.Float64()*2 - 1 // Random weight between -1 and 1
+		weight := random.Float64()*2 - 1 // Random weight between -1 and 1
 		newConnection := []float64{float64(targetID), weight}
 		newNeuron.Connections = append(newNeuron.Connections, newConnection)
-		if bp.Debug {
-			fmt.Printf("Connected Neuron %d to existing Neuron %d with weight %.4f.\n", newNeuronID, targetID, weight)
-		}
+		bp.debugf("Connected Neuron %d to existing Neuron %d with weight %.4f.", newNeuronID, targetID, weight)
 	}
 
 	// Randomly connect existing neurons to the new neuron (optional, if bidirectional connections are desired)
-	for _, neuron := range bp.Neurons {
-		if rand.Float64() < 0.3 { // 30% chance of connecting to the new neuron
-			weight := rand.Float64()*2 - 1
+	for _, id := range bp.getAllNeuronIDs() {
+		neuron := bp.Neurons[id]
+		if bp.isFrozen(id) {
+			continue
+		}
+		if random.Float64() < 0.3 { // 30% chance of connecting to the new neuron
+			weight := random.Float64()*2 - 1
 			neuron.Connections = append(neuron.Connections, []float64{float64(newNeuronID), weight})
-			if bp.Debug {
-				fmt.Printf("Connected existing Neuron %d to new Neuron %d with weight %.4f.\n", neuron.ID, newNeuronID, weight)
-			}
+			bp.alignLSTMGates(neuron)
+			bp.debugf("Connected existing Neuron %d to new Neuron %d with weight %.4f.", neuron.ID, newNeuronID, weight)
 		}
 	}
 
@@ -73,15 +79,16 @@ func (bp *Blueprint) InsertNeuronOfTypeBetweenInputsAndOutputs(neuronType string
 		bp.initializeBatchNormFields(newNeuron)
 		// Add cases for other neuron types as needed
 	}
+	bp.initializeInsertedWeights(newNeuron)
 
 	return nil
 }
 
 // initializeLSTMWeights initializes the GateWeights for an LSTM neuron based on its connections.
 func (bp *Blueprint) initializeLSTMWeights(neuron *Neuron) {
-	numConnections := len(neuron.Connections)
+	numConnections := neuron.numConnections()
 	if numConnections == 0 {
-		fmt.Printf("Warning: LSTM Neuron %d has no connections to initialize GateWeights.\n", neuron.ID)
+		bp.warnf("Warning: LSTM Neuron %d has no connections to initialize GateWeights.", neuron.ID)
 		return
 	}
 
@@ -92,8 +99,22 @@ func (bp *Blueprint) initializeLSTMWeights(neuron *Neuron) {
 		"output": bp.RandomWeights(numConnections),
 		"cell":   bp.RandomWeights(numConnections),
 	}
-	if bp.Debug {
-		fmt.Printf("Initialized GateWeights for LSTM Neuron %d with %d connections.\n", neuron.ID, numConnections)
+	bp.debugf("Initialized GateWeights for LSTM Neuron %d with %d connections.", neuron.ID, numConnections)
+}
+
+// alignLSTMGates gives every gate of an LSTM neuron one weight per connection, drawing random weights for
+// connections added since the gates were initialized and dropping weights beyond the last connection.
+func (bp *Blueprint) alignLSTMGates(neuron *Neuron) {
+	if neuron.Type != "lstm" || neuron.GateWeights == nil {
+		return
+	}
+	numConnections := neuron.numConnections()
+	for _, gate := range sortedGateNames(neuron.GateWeights) {
+		weights := neuron.GateWeights[gate]
+		if len(weights) < numConnections {
+			weights = append(weights, bp.RandomWeights(numConnections-len(weights))...)
+		}
+		neuron.GateWeights[gate] = weights[:numConnections]
 	}
 }
 
@@ -101,23 +122,21 @@ func (bp *Blueprint) createNeuron(id int, neuronType string) (*Neuron, error) {
 	neuron := &Neuron{
 		ID:          id,
 		Type:        neuronType,
-		Value:       rand.Float64()*2 - 1, // Random value between -1 and 1
-		Bias:        rand.Float64()*2 - 1, // Random bias between -1 and 1
+		Value:       random.Float64()*2 - 1, // Random value between -1 and 1
+		Bias:        random.Float64()*2 - 1, // Random bias between -1 and 1
 		Connections: [][]float64{},
 		Activation:  "linear", // Default activation; will be overridden below
 	}
 
-	// Define possible activation functions
-	activationFunctions := []string{"relu", "sigmoid", "tanh", "leaky_relu", "linear"}
-
-	// Assign activation function based on type or randomly
+	// Assign activation function based on type or randomly, from the built-in and registered activations
 	switch neuronType {
 	case "dense":
-		neuron.Activation = activationFunctions[rand.Intn(len(activationFunctions))]
+		neuron.Activation = bp.randomActivation()
 	case "rnn":
-		neuron.Activation = activationFunctions[rand.Intn(len(activationFunctions))]
+		neuron.Activation = bp.randomActivation()
+		neuron.RecurrentWeight = 1.0
 	case "lstm":
-		neuron.Activation = activationFunctions[rand.Intn(len(activationFunctions))]
+		neuron.Activation = bp.randomActivation()
 		// Initialize gate weights for LSTM
 		neuron.GateWeights = map[string][]float64{
 			"input":  bp.RandomWeights(1), // Replace with actual connection size
@@ -126,35 +145,40 @@ func (bp *Blueprint) createNeuron(id int, neuronType string) (*Neuron, error) {
 			"cell":   bp.RandomWeights(1),
 		}
 	case "cnn":
-		neuron.Activation = activationFunctions[rand.Intn(len(activationFunctions))]
+		neuron.Activation = bp.randomActivation()
 		// Initialize default kernels
 		neuron.Kernels = [][]float64{
 			{0.2, 0.5},
 			{0.3, 0.4},
 		}
+	case "maxpool", "avgpool":
+		// Pooling has no weights of its own, so it keeps no bias and a linear activation
+		neuron.Bias = 0
+		neuron.PoolSize = 2
 	case "dropout":
 		neuron.DropoutRate = 0.5 // Default dropout rate
 	case "batch_norm":
 		// Initialize BatchNormParams
 		neuron.BatchNormParams = &BatchNormParams{
-			Gamma: 1.0,
-			Beta:  0.0,
-			Mean:  0.0,
-			Var:   1.0,
+			Gamma:    1.0,
+			Beta:     0.0,
+			Mean:     0.0,
+			Var:      1.0,
+			Momentum: defaultBatchNormMomentum,
 		}
-		neuron.Activation = activationFunctions[rand.Intn(len(activationFunctions))]
+		neuron.Activation = bp.randomActivation()
 	case "attention":
 		neuron.Attention = true
 		neuron.AttentionWeights = []float64{}
-		neuron.Activation = activationFunctions[rand.Intn(len(activationFunctions))]
+		neuron.Activation = bp.randomActivation()
 	case "nca":
-		neuron.Activation = activationFunctions[rand.Intn(len(activationFunctions))]
+		neuron.Activation = bp.randomActivation()
 		neuron.NCAState = make([]float64, 10)
 		for i := range neuron.NCAState {
-			neuron.NCAState[i] = rand.Float64()*2 - 1
+			neuron.NCAState[i] = random.Float64()*2 - 1
 		}
 	default:
-		neuron.Activation = activationFunctions[rand.Intn(len(activationFunctions))]
+		neuron.Activation = bp.randomActivation()
 	}
 
 	return neuron, nil
@@ -163,7 +187,7 @@ func (bp *Blueprint) createNeuron(id int, neuronType string) (*Neuron, error) {
 // isValidNeuronType checks if the provided neuron type is supported.
 func (bp *Blueprint) isValidNeuronType(neuronType string) bool {
 	supportedTypes := []string{
-		"dense", "rnn", "lstm", "cnn", "dropout", "batch_norm", "attention", "nca",
+		"dense", "rnn", "lstm", "cnn", "maxpool", "avgpool", "dropout", "batch_norm", "attention", "nca",
 	}
 	for _, t := range supportedTypes {
 		if neuronType == t {
@@ -173,16 +197,45 @@ func (bp *Blueprint) isValidNeuronType(neuronType string) bool {
 	return false
 }
 
-// generateUniqueNeuronID generates a unique neuron ID by finding the maximum existing ID and adding 1.
+// blueprintLockInit guards the creation of the blueprint's own locks, such as neuronMu and rpcMu, so two goroutines
+// never create separate locks for the same blueprint. It is held only while a lock is looked up.
+var blueprintLockInit sync.Mutex
+
+// neuronLock returns the blueprint's own mutex that serializes neuron insertion, creating it on first use.
+func (bp *Blueprint) neuronLock() *sync.Mutex {
+	blueprintLockInit.Lock()
+	defer blueprintLockInit.Unlock()
+	if bp.neuronMu == nil {
+		bp.neuronMu = &sync.Mutex{}
+	}
+	return bp.neuronMu
+}
+
+// generateUniqueNeuronID reserves and returns a neuron ID that is not in use. The counter starts after the
+// maximum existing ID the first time it is needed, including after a load or Clone, and every call advances it,
+// so two callers never get the same ID even before either has added its neuron. Callers must hold neuronLock
+// until the neuron is in bp.Neurons, since the lookup reads the map.
 // Returns -1 if it fails to generate a unique ID.
 func (bp *Blueprint) generateUniqueNeuronID() int {
-	maxID := 0
-	for id := range bp.Neurons {
-		if id > maxID {
-			maxID = id
+	if bp.nextNeuronID == 0 {
+		maxID := 0
+		for id := range bp.Neurons {
+			if id > maxID {
+				maxID = id
+			}
 		}
+		bp.nextNeuronID = maxID + 1
 	}
-	return maxID + 1
+	// Skip IDs taken by neurons added with an explicit ID since the counter was set
+	for {
+		if _, taken := bp.Neurons[bp.nextNeuronID]; !taken {
+			break
+		}
+		bp.nextNeuronID++
+	}
+	id := bp.nextNeuronID
+	bp.nextNeuronID++
+	return id
 }
 
 // isInputNode checks if a given neuron ID is an input node.
@@ -199,7 +252,7 @@ func (bp *Blueprint) isInputNode(neuronID int) bool {
 // For demonstration, it inserts one neuron of each supported type between inputs and outputs.
 func (bp *Blueprint) MutateNetwork() error {
 	neuronTypes := []string{
-		"dense", "rnn", "lstm", "cnn", "dropout", "batch_norm", "attention", "nca",
+		"dense", "rnn", "lstm", "cnn", "maxpool", "avgpool", "dropout", "batch_norm", "attention", "nca",
 	}
 
 	for _, neuronType := range neuronTypes {
@@ -233,9 +286,7 @@ func (bp *Blueprint) initializeNCACustomFields(neuron *Neuron) {
 	neuron.NeighborhoodIDs = append(neuron.NeighborhoodIDs, bp.InputNodes...)
 	// Set a default update rule, e.g., "sum". This can be made configurable.
 	neuron.UpdateRules = "sum"
-	if bp.Debug {
-		fmt.Printf("Initialized NCA-specific fields for NCA Neuron %d: NeighborhoodIDs=%v, UpdateRules=%s\n", neuron.ID, neuron.NeighborhoodIDs, neuron.UpdateRules)
-	}
+	bp.debugf("Initialized NCA-specific fields for NCA Neuron %d: NeighborhoodIDs=%v, UpdateRules=%s", neuron.ID, neuron.NeighborhoodIDs, neuron.UpdateRules)
 }
 
 // blueprint.go
@@ -245,32 +296,37 @@ func (bp *Blueprint) initializeNCACustomFields(neuron *Neuron) {
 func (bp *Blueprint) initializeBatchNormFields(neuron *Neuron) {
 	// Check if BatchNormParams are already set
 	if neuron.BatchNormParams != nil {
-		fmt.Printf("BatchNorm Neuron %d: BatchNormParams already initialized.\n", neuron.ID)
+		bp.infof("BatchNorm Neuron %d: BatchNormParams already initialized.", neuron.ID)
 		return
 	}
 
 	// Initialize BatchNormParams with default or specified values
 	neuron.BatchNormParams = &BatchNormParams{
-		Gamma: 1.0, // Scale parameter
-		Beta:  0.0, // Shift parameter
-		Mean:  0.0, // Running mean
-		Var:   1.0, // Running variance
+		Gamma:    1.0,                      // Scale parameter
+		Beta:     0.0,                      // Shift parameter
+		Mean:     0.0,                      // Running mean
+		Var:      1.0,                      // Running variance
+		Momentum: defaultBatchNormMomentum, // Running statistics momentum
 	}
 
 	// Optionally, if you want to allow customization via JSON, you can check if values are provided
 	// For simplicity, we're using default values here
-	if bp.Debug {
-		fmt.Printf("Initialized BatchNormParams for BatchNorm Neuron %d: Gamma=%.2f, Beta=%.2f, Mean=%.2f, Var=%.2f\n",
-			neuron.ID, neuron.BatchNormParams.Gamma, neuron.BatchNormParams.Beta,
-			neuron.BatchNormParams.Mean, neuron.BatchNormParams.Var)
-	}
+	bp.debugf("Initialized BatchNormParams for BatchNorm Neuron %d: Gamma=%.2f, Beta=%.2f, Mean=%.2f, Var=%.2f",
+		neuron.ID, neuron.BatchNormParams.Gamma, neuron.BatchNormParams.Beta,
+		neuron.BatchNormParams.Mean, neuron.BatchNormParams.Var)
 }
 
 // InsertNeuronWithRandomConnectionsAndReconnect modifies the network by:
 // - Appending a new neuron.
 // - Randomly connecting it to existing neurons.
 // - Reconnecting output neurons to the last `x` added neurons.
+// Several goroutines may insert neurons into the same blueprint at once.
 func (bp *Blueprint) InsertNeuronWithRandomConnectionsAndReconnect(neuronType string, reconnectToLastX int) error {
+	// Hold the blueprint's lock across ID allocation, the map insert and the rewiring
+	mu := bp.neuronLock()
+	mu.Lock()
+	defer mu.Unlock()
+
 	// Validate the neuron type
 	if !bp.isValidNeuronType(neuronType) {
 		return fmt.Errorf("invalid neuron type: %s", neuronType)
@@ -290,9 +346,8 @@ func (bp *Blueprint) InsertNeuronWithRandomConnectionsAndReconnect(neuronType st
 
 	// Add the new neuron to the blueprint
 	bp.Neurons[newNeuronID] = newNeuron
-	if bp.Debug {
-		fmt.Printf("Inserted new Neuron with ID %d of type '%s'.\n", newNeuronID, neuronType)
-	}
+	bp.invalidateCompiled()
+	bp.debugf("Inserted new Neuron with ID %d of type '%s'.", newNeuronID, neuronType)
 
 	// Check if this is the first hidden neuron
 	//if !bp.isInputNode(newNeuronID) && !bp.isOutputNode(newNeuronID) {
@@ -301,17 +356,17 @@ func (bp *Blueprint) InsertNeuronWithRandomConnectionsAndReconnect(neuronType st
 
 	// Randomly connect the new neuron to other existing neurons
 	neuronIDs := bp.getAllNeuronIDs()
-	rand.Shuffle(len(neuronIDs), func(i, j int) { neuronIDs[i], neuronIDs[j] = neuronIDs[j], neuronIDs[i] })
-	numConnections := rand.Intn(2) + 1 // Randomly choose 1 or 2 connections
+	random.Shuffle(len(neuronIDs), func(i, j int) { neuronIDs[i], neuronIDs[j] = neuronIDs[j], neuronIDs[i] })
+	numConnections := random.Intn(2) + 1 // Randomly choose 1 or 2 connections
 
 	for i := 0; i < numConnections && i < len(neuronIDs); i++ {
 		targetID := neuronIDs[i]
-		weight := rand.Float64()*2 - 1 // Random weight between -1 and 1
+		weight := random.Float64()*2 - 1 // Random weight between -1 and 1
 		newNeuron.Connections = append(newNeuron.Connections, []float64{float64(targetID), weight})
-		if bp.Debug {
-			fmt.Printf("Connected Neuron %d to existing Neuron %d with weight %.4f.\n", newNeuronID, targetID, weight)
-		}
+		bp.debugf("Connected Neuron %d to existing Neuron %d with weight %.4f.", newNeuronID, targetID, weight)
 	}
+	bp.alignLSTMGates(newNeuron)
+	bp.initializeInsertedWeights(newNeuron)
 
 	// Add the new neuron to the list of "active" neurons for future connections
 	activeNeuronIDs := append(bp.getActiveNeuronIDs(), newNeuronID)
@@ -321,27 +376,29 @@ func (bp *Blueprint) InsertNeuronWithRandomConnectionsAndReconnect(neuronType st
 	for _, outputID := range bp.OutputNodes {
 		outputNeuron, exists := bp.Neurons[outputID]
 		if !exists {
-			fmt.Printf("Warning: Output Neuron with ID %d does not exist.\n", outputID)
+			bp.warnf("Warning: Output Neuron with ID %d does not exist.", outputID)
+			continue
+		}
+		if bp.isFrozen(outputID) {
 			continue
 		}
 		// Clear old connections for clean reconnection
 		outputNeuron.Connections = nil
 		for _, lastNeuronID := range lastNeurons {
-			weight := rand.Float64()*2 - 1
+			weight := random.Float64()*2 - 1
 			outputNeuron.Connections = append(outputNeuron.Connections, []float64{float64(lastNeuronID), weight})
-			if bp.Debug {
-				fmt.Printf("Reconnected Output Neuron %d to Neuron %d with weight %.4f.\n", outputID, lastNeuronID, weight)
-			}
+			bp.debugf("Reconnected Output Neuron %d to Neuron %d with weight %.4f.", outputID, lastNeuronID, weight)
 		}
+		bp.alignLSTMGates(outputNeuron)
 	}
 
 	return nil
 }
 
-// getActiveNeuronIDs retrieves IDs of all neurons except inputs and outputs.
+// getActiveNeuronIDs retrieves IDs of all neurons except inputs and outputs, in ascending order.
 func (bp *Blueprint) getActiveNeuronIDs() []int {
 	activeNeuronIDs := []int{}
-	for id := range bp.Neurons {
+	for _, id := range bp.getAllNeuronIDs() {
 		if !bp.isInputNode(id) && !bp.isOutputNode(id) {
 			activeNeuronIDs = append(activeNeuronIDs, id)
 		}
@@ -380,37 +437,34 @@ func (bp *Blueprint) InsertNeuronWithRandomConnections(neuronType string) error
 
 	// Add the new neuron to the blueprint
 	bp.Neurons[newNeuronID] = newNeuron
-	if bp.Debug {
-		fmt.Printf("Inserted new Neuron with ID %d of type '%s'.\n", newNeuronID, neuronType)
-	}
+	bp.debugf("Inserted new Neuron with ID %d of type '%s'.", newNeuronID, neuronType)
 
 	// Randomly connect the new neuron to 1-2 existing neurons
 	neuronIDs := bp.getAllNeuronIDs()
-	rand.Shuffle(len(neuronIDs), func(i, j int) { neuronIDs[i], neuronIDs[j] = neuronIDs[j], neuronIDs[i] })
-	numConnections := rand.Intn(2) + 1 // Randomly choose 1 or 2 connections
+	random.Shuffle(len(neuronIDs), func(i, j int) { neuronIDs[i], neuronIDs[j] = neuronIDs[j], neuronIDs[i] })
+	numConnections := random.Intn(2) + 1 // Randomly choose 1 or 2 connections
 
 	for i := 0; i < numConnections && i < len(neuronIDs); i++ {
 		targetID := neuronIDs[i]
-		weight := rand.Float64()*2 - 1 // Random weight between -1 and 1
+		weight := random.Float64()*2 - 1 // Random weight between -1 and 1
 		newNeuron.Connections = append(newNeuron.Connections, []float64{float64(targetID), weight})
-		if bp.Debug {
-			fmt.Printf("Connected Neuron %d to existing Neuron %d with weight %.4f.\n", newNeuronID, targetID, weight)
-		}
+		bp.debugf("Connected Neuron %d to existing Neuron %d with weight %.4f.", newNeuronID, targetID, weight)
 	}
 
 	// Reconnect all output neurons to include the new neuron
 	for _, outputID := range bp.OutputNodes {
 		outputNeuron, exists := bp.Neurons[outputID]
 		if !exists {
-			fmt.Printf("Warning: Output Neuron with ID %d does not exist.\n", outputID)
+			bp.warnf("Warning: Output Neuron with ID %d does not exist.", outputID)
 			continue
 		}
-		weight := rand.Float64()*2 - 1
+		if bp.isFrozen(outputID) {
+			continue
+		}
+		weight := random.Float64()*2 - 1
 		newConnection := []float64{float64(newNeuronID), weight}
 		outputNeuron.Connections = append(outputNeuron.Connections, newConnection)
-		if bp.Debug {
-			fmt.Printf("Connected New Neuron %d to Output Neuron %d with weight %.4f.\n", newNeuronID, outputID, weight)
-		}
+		bp.debugf("Connected New Neuron %d to Output Neuron %d with weight %.4f.", newNeuronID, outputID, weight)
 	}
 
 	return nil
@@ -418,7 +472,13 @@ func (bp *Blueprint) InsertNeuronWithRandomConnections(neuronType string) error
 
 // InsertNeuronWithRandomConnections appends a new neuron to the network,
 // randomly connects it to 1-2 existing neurons, and ensures selective output connections.
+// Several goroutines may insert neurons into the same blueprint at once.
 func (bp *Blueprint) InsertNeuronWithRandomConnections(neuronType string) error {
+	// Hold the blueprint's lock across ID allocation, the map insert and the rewiring
+	mu := bp.neuronLock()
+	mu.Lock()
+	defer mu.Unlock()
+
 	// Validate the neuron type
 	if !bp.isValidNeuronType(neuronType) {
 		return fmt.Errorf("invalid neuron type: %s", neuronType)
@@ -438,34 +498,32 @@ func (bp *Blueprint) InsertNeuronWithRandomConnections(neuronType string) error
 
 	// Add the new neuron to the blueprint
 	bp.Neurons[newNeuronID] = newNeuron
-	if bp.Debug {
-		fmt.Printf("Inserted new Neuron with ID %d of type '%s'.\n", newNeuronID, neuronType)
-	}
+	bp.invalidateCompiled()
+	bp.debugf("Inserted new Neuron with ID %d of type '%s'.", newNeuronID, neuronType)
 
 	// Randomly connect the new neuron to 1-2 existing neurons
 	neuronIDs := bp.getAllNeuronIDs()
-	rand.Shuffle(len(neuronIDs), func(i, j int) { neuronIDs[i], neuronIDs[j] = neuronIDs[j], neuronIDs[i] })
-	numConnections := rand.Intn(2) + 1 // Randomly choose 1 or 2 connections
+	random.Shuffle(len(neuronIDs), func(i, j int) { neuronIDs[i], neuronIDs[j] = neuronIDs[j], neuronIDs[i] })
+	numConnections := random.Intn(2) + 1 // Randomly choose 1 or 2 connections
 
 	for i := 0; i < numConnections && i < len(neuronIDs); i++ {
 		targetID := neuronIDs[i]
-		weight := rand.Float64()*2 - 1 // Random weight between -1 and 1
+		weight := random.Float64()*2 - 1 // Random weight between -1 and 1
 		newNeuron.Connections = append(newNeuron.Connections, []float64{float64(targetID), weight})
-		if bp.Debug {
-			fmt.Printf("Connected Neuron %d to existing Neuron %d with weight %.4f.\n", newNeuronID, targetID, weight)
-		}
+		bp.debugf("Connected Neuron %d to existing Neuron %d with weight %.4f.", newNeuronID, targetID, weight)
 	}
+	bp.alignLSTMGates(newNeuron)
+	bp.initializeInsertedWeights(newNeuron)
 
 	// Selectively connect the new neuron to output neurons
 	if len(bp.OutputNodes) > 0 {
-		selectedOutputID := bp.OutputNodes[rand.Intn(len(bp.OutputNodes))] // Randomly select one output neuron
+		selectedOutputID := bp.OutputNodes[random.Intn(len(bp.OutputNodes))] // Randomly select one output neuron
 		outputNeuron, exists := bp.Neurons[selectedOutputID]
-		if exists {
-			weight := rand.Float64()*2 - 1
+		if exists && !bp.isFrozen(selectedOutputID) {
+			weight := random.Float64()*2 - 1
 			outputNeuron.Connections = append(outputNeuron.Connections, []float64{float64(newNeuronID), weight})
-			if bp.Debug {
-				fmt.Printf("Connected New Neuron %d to Output Neuron %d with weight %.4f.\n", newNeuronID, selectedOutputID, weight)
-			}
+			bp.alignLSTMGates(outputNeuron)
+			bp.debugf("Connected New Neuron %d to Output Neuron %d with weight %.4f.", newNeuronID, selectedOutputID, weight)
 		}
 	}
 
@@ -0,0 +1,39 @@
+package blueprint
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestConcurrentInsertsGetDistinctIDs(t *testing.T) {
+	bp := NewBlueprint()
+	bp.AddInputNeurons([]int{1, 2})
+	bp.AddOutputNeurons([]int{3}, "linear")
+
+	const inserts = 100
+	var wg sync.WaitGroup
+	errs := make(chan error, inserts)
+	for i := 0; i < inserts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- bp.InsertNeuronOfTypeBetweenInputsAndOutputs("dense")
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("insert failed: %v", err)
+		}
+	}
+
+	if got, want := len(bp.Neurons), 3+inserts; got != want {
+		t.Fatalf("got %d neurons, want %d", got, want)
+	}
+	for id, neuron := range bp.Neurons {
+		if neuron.ID != id {
+			t.Errorf("neuron stored under ID %d has ID %d", id, neuron.ID)
+		}
+	}
+}
@@ -2,9 +2,10 @@
 package blueprint
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"math/rand"
+	"math"
 	"os"
 	"runtime"
 	"strings"
@@ -25,33 +26,31 @@ type candidateResult struct {
 	GenerousError       float64            // Average generous error
 	DecileInconsistency int                // Count of decile inconsistencies
 	CandidateBlueprint  *Blueprint         // The evaluated candidate blueprint
+	NeuronType          string             // Type of the neuron the candidate inserted
 }
 
 // SimpleNAS performs a basic neural architecture search by incrementally adding one neuron at a time
 // and keeping the change if it improves the model's evaluation on any of the three evaluation metrics.
 func (bp *Blueprint) SimpleNAS(sessions []Session, maxIterations int) {
-	// Seed the random number generator
-	rand.Seed(time.Now().UnixNano())
-
 	// Keep track of the best model and its performance
-	bestBlueprint := bp.Clone() // Assume we have a Clone method
+	bestBlueprint := bp.DeepCopy()
 	bestExactAccuracy, bestGenerousAccuracy, bestForgivenessAccuracy, _, _, _ := bestBlueprint.EvaluateModelPerformance(sessions)
 
-	fmt.Printf("Initial model performance: Exact=%.2f%%, Generous=%.2f%%, Forgiveness=%.2f%%\n",
+	bp.infof("Initial model performance: Exact=%.2f%%, Generous=%.2f%%, Forgiveness=%.2f%%",
 		bestExactAccuracy, bestGenerousAccuracy, bestForgivenessAccuracy)
 
 	for iteration := 1; iteration <= maxIterations; iteration++ {
 		// Clone the best blueprint to create a new candidate
-		candidateBlueprint := bestBlueprint.Clone()
+		candidateBlueprint := bestBlueprint.DeepCopy()
 
 		// Randomly select a neuron type to add
-		neuronTypes := []string{"dense", "rnn", "lstm", "cnn", "dropout", "batch_norm", "attention", "nca"}
-		neuronType := neuronTypes[rand.Intn(len(neuronTypes))]
+		neuronTypes := []string{"dense", "rnn", "lstm", "cnn", "maxpool", "avgpool", "dropout", "batch_norm", "attention", "nca"}
+		neuronType := neuronTypes[random.Intn(len(neuronTypes))]
 
 		// Insert a neuron of this type between inputs and outputs
 		err := candidateBlueprint.InsertNeuronOfTypeBetweenInputsAndOutputs(neuronType)
 		if err != nil {
-			fmt.Printf("Iteration %d: Failed to insert neuron of type '%s': %v\n", iteration, neuronType, err)
+			bp.warnf("Iteration %d: Failed to insert neuron of type '%s': %v", iteration, neuronType, err)
 			continue
 		}
 
@@ -66,10 +65,10 @@ func (bp *Blueprint) SimpleNAS(sessions []Session, maxIterations int) {
 			bestGenerousAccuracy = generousAccuracy
 			bestForgivenessAccuracy = forgivenessAccuracy
 
-			fmt.Printf("Iteration %d: Improved model found! Exact=%.2f%%, Generous=%.2f%%, Forgiveness=%.2f%%\n",
+			bp.infof("Iteration %d: Improved model found! Exact=%.2f%%, Generous=%.2f%%, Forgiveness=%.2f%%",
 				iteration, exactAccuracy, generousAccuracy, forgivenessAccuracy)
 		} else {
-			fmt.Printf("Iteration %d: No improvement.\n", iteration)
+			bp.infof("Iteration %d: No improvement.", iteration)
 		}
 	}
 
@@ -82,7 +81,7 @@ func (bp *Blueprint) Clone() *Blueprint {
 	// Serialize the blueprint to JSON
 	data, err := json.Marshal(bp)
 	if err != nil {
-		fmt.Printf("Error serializing blueprint: %v\n", err)
+		bp.warnf("Error serializing blueprint: %v", err)
 		return nil
 	}
 
@@ -90,7 +89,7 @@ func (bp *Blueprint) Clone() *Blueprint {
 	var newBP Blueprint
 	err = json.Unmarshal(data, &newBP)
 	if err != nil {
-		fmt.Printf("Error deserializing blueprint: %v\n", err)
+		bp.warnf("Error deserializing blueprint: %v", err)
 		return nil
 	}
 
@@ -98,6 +97,11 @@ func (bp *Blueprint) Clone() *Blueprint {
 	if newBP.Neurons == nil {
 		newBP.Neurons = make(map[int]*Neuron)
 	}
+	// Activations are not serialized; share the source's map, which RegisterActivation never modifies in place
+	newBP.ScalarActivationMap = bp.ScalarActivationMap
+	newBP.Logger = bp.Logger
+	newBP.frozenNeurons = bp.frozenNeurons
+	newBP.TrainingMode = bp.TrainingMode
 	if newBP.ScalarActivationMap == nil {
 		newBP.InitializeActivationFunctions()
 	}
@@ -114,9 +118,6 @@ func (bp *Blueprint) SimpleNASWithoutCrossover(
 	neuronTypes []string,
 	metricsToOptimize []string,
 ) {
-	// Seed the random number generator
-	rand.Seed(time.Now().UnixNano())
-
 	// Validate and normalize metricsToOptimize
 	validMetrics := map[string]bool{
 		"exact":       true,
@@ -129,40 +130,36 @@ func (bp *Blueprint) SimpleNASWithoutCrossover(
 		if _, exists := validMetrics[metricLower]; exists {
 			selectedMetrics[metricLower] = true
 		} else {
-			fmt.Printf("Warning: Invalid metric '%s' ignored.\n", metric)
+			bp.warnf("Warning: Invalid metric '%s' ignored.", metric)
 		}
 	}
 
 	if len(selectedMetrics) == 0 {
-		fmt.Println("No valid metrics specified for optimization. Exiting NAS.")
+		bp.infof("No valid metrics specified for optimization. Exiting NAS.")
 		return
 	}
 
 	// Evaluate the initial model
 	initialExact, initialGenerous, initialForgiveness, _, _, _ := bp.EvaluateModelPerformance(sessions)
-	fmt.Printf("Initial model performance: Exact=%.2f%%, Generous=%.2f%%, Forgiveness=%.2f%%\n",
+	bp.infof("Initial model performance: Exact=%.2f%%, Generous=%.2f%%, Forgiveness=%.2f%%",
 		initialExact, initialGenerous, initialForgiveness)
 
 	// Initialize best metrics based on selectedMetrics
 	bestExact, bestGenerous, bestForgiveness := initialExact, initialGenerous, initialForgiveness
 
 	for iteration := 1; iteration <= maxIterations; iteration++ {
-		fmt.Printf("Iteration %d\n", iteration)
+		bp.infof("Iteration %d", iteration)
 
 		// Clone the current blueprint
-		candidateBlueprint := bp.Clone()
-		if candidateBlueprint == nil {
-			fmt.Println("Cloning failed. Skipping iteration.")
-			continue
-		}
+		candidateBlueprint := bp.DeepCopy()
 
 		// Randomly select a neuron type to insert
-		neuronType := neuronTypes[rand.Intn(len(neuronTypes))]
+		neuronType := neuronTypes[random.Intn(len(neuronTypes))]
 
 		// Insert a neuron of the selected type
 		err := candidateBlueprint.InsertNeuronOfTypeBetweenInputsAndOutputs(neuronType)
 		if err != nil {
-			fmt.Printf("Iteration %d: Failed to insert neuron of type '%s': %v\n", iteration, neuronType, err)
+			bp.warnf("Iteration %d: Failed to insert neuron of type '%s': %v", iteration, neuronType, err)
 			continue
 		}
 
@@ -218,9 +215,9 @@ func (bp *Blueprint) SimpleNASWithoutCrossover(
 			}
 			// Remove trailing comma and space
 			improvementLog = strings.TrimSuffix(improvementLog, ", ")
-			fmt.Printf(improvementLog+"\n", args...)
+			bp.infof(improvementLog, args...)
 		} else {
-			fmt.Printf("Iteration %d: No improvement.\n", iteration)
+			bp.infof("Iteration %d: No improvement.", iteration)
 		}
 
 		// Early stopping if any selected metric reaches 100%
@@ -242,12 +239,12 @@ func (bp *Blueprint) SimpleNASWithoutCrossover(
 			}
 		}
 		if perfect {
-			fmt.Println("Perfect accuracy achieved on one of the selected metrics. Stopping NAS.")
+			bp.infof("Perfect accuracy achieved on one of the selected metrics. Stopping NAS.")
 			break
 		}
 	}
 
-	fmt.Println("SimpleNASWithoutCrossover training completed.")
+	bp.infof("SimpleNASWithoutCrossover training completed.")
 }
 
 // SimpleNASWithRandomConnections incrementally adds neurons with random connections,
@@ -260,17 +257,44 @@ func (bp *Blueprint) SimpleNASWithRandomConnections(
 	neuronTypes []string,
 	weightUpdateIterations int, // Number of hill-climbing steps per NAS iteration
 ) {
-	// Seed the random number generator
-	rand.Seed(time.Now().UnixNano())
+	bp.SimpleNASWithConfig(sessions, NASConfig{
+		MaxIterations:          maxIterations,
+		NeuronTypes:            neuronTypes,
+		WeightUpdateIterations: weightUpdateIterations,
+	})
+}
 
+// SimpleNASWithConfig is the sequential NAS behind SimpleNASWithRandomConnections.
+// Each candidate inserts one neuron, receives cfg.WeightUpdateIterations hill-climbing steps and is
+// accepted under the same rule, subject to the guard set configured in cfg.
+func (bp *Blueprint) SimpleNASWithConfig(sessions []Session, cfg NASConfig) {
+	_ = bp.SimpleNASWithContext(context.Background(), sessions, cfg)
+}
+
+// SimpleNASWithContext is SimpleNASWithConfig that stops once ctx is cancelled. Cancellation is checked before
+// every iteration and between hill-climbing steps; the blueprint is then left as the best model found so far
+// and ctx.Err() is returned, or the error of cfg.OnIteration when that stopped the search. A search that runs to
+// the end returns nil.
+func (bp *Blueprint) SimpleNASWithContext(ctx context.Context, sessions []Session, cfg NASConfig) error {
 	// Keep track of the best model and its performance
-	bestBlueprint := bp.Clone() // Assume we have a Clone method
-	if bestBlueprint == nil {
-		fmt.Println("Failed to clone the initial blueprint.")
-		return
+	bestBlueprint := bp.DeepCopy()
+	bestBlueprint.frozenNeurons = bp.freezeOutside(cfg.MutableNeuronIDs)
+
+	// Candidates train on sessions but are accepted on the validation set when one is configured
+	scoreSessions := cfg.scoreSessions(sessions)
+	bestExactAccuracy, bestGenerousAccuracy, bestForgivenessAccuracy, _, _, _ := bestBlueprint.EvaluateModelPerformance(scoreSessions)
+
+	// Baseline on the held-out guard set
+	var bestGuard EvaluationResult
+	if len(cfg.GuardSessions) > 0 {
+		bestGuard = bestBlueprint.Evaluate(cfg.GuardSessions)
 	}
 
-	bestExactAccuracy, bestGenerousAccuracy, bestForgivenessAccuracy, _, _, _ := bestBlueprint.EvaluateModelPerformance(sessions)
+	var typeBandit *Bandit
+	if cfg.AdaptiveTypes {
+		typeBandit = bp.neuronTypeBandit(cfg.NeuronTypes)
+	}
+	watchdog := newStuckWatchdog(cfg.StuckIterations, bp.warnf)
 
 	// Array to store progress
 	progress := []struct {
@@ -287,52 +311,67 @@ func (bp *Blueprint) SimpleNASWithRandomConnections(
 		},
 	}
 
-	fmt.Printf("Initial model performance: Exact=%.2f%%, Generous=%.2f%%, Forgiveness=%.2f%%\n",
+	bp.infof("Initial model performance: Exact=%.2f%%, Generous=%.2f%%, Forgiveness=%.2f%%",
 		bestExactAccuracy, bestGenerousAccuracy, bestForgivenessAccuracy)
 
-	for iteration := 1; iteration <= maxIterations; iteration++ {
-		fmt.Printf("=== Iteration %d ===\n", iteration)
+	var stopErr error
+	for iteration := 1; iteration <= cfg.MaxIterations; iteration++ {
+		if stopErr = ctx.Err(); stopErr != nil {
+			bp.infof("NAS cancelled after iteration %d.", iteration-1)
+			break
+		}
+		bp.infof("=== Iteration %d ===", iteration)
 
 		// Clone the best blueprint to create a new candidate
-		candidateBlueprint := bestBlueprint.Clone()
-		if candidateBlueprint == nil {
-			fmt.Printf("Iteration %d: Failed to clone the best blueprint.\n", iteration)
-			continue
-		}
+		candidateBlueprint := bestBlueprint.DeepCopy()
 
-		// Randomly select a neuron type to add
-		neuronType := neuronTypes[rand.Intn(len(neuronTypes))]
+		// Select a neuron type to add
+		neuronType := sampleNeuronType(typeBandit, cfg.NeuronTypes)
 
 		// Insert a neuron of this type between inputs and outputs
 		err := candidateBlueprint.InsertNeuronOfTypeBetweenInputsAndOutputs(neuronType)
 		if err != nil {
-			fmt.Printf("Iteration %d: Failed to insert neuron of type '%s': %v\n", iteration, neuronType, err)
+			bp.warnf("Iteration %d: Failed to insert neuron of type '%s': %v", iteration, neuronType, err)
 			continue
 		}
 
 		// Perform hill-climbing weight updates
-		for w := 0; w < weightUpdateIterations; w++ {
-			improved := candidateBlueprint.HillClimbWeightUpdate(sessions)
-			if !improved {
-				// If no improvement, you can choose to continue or break early
-				// Here, we'll continue for the specified number of iterations
-				continue
-			}
-		}
+		candidateBlueprint.hillClimb(ctx, sessions, cfg.WeightUpdateIterations, cfg.HillClimbPatience)
 
 		// Evaluate the candidate model after weight updates
-		exactAccuracy, generousAccuracy, forgivenessAccuracy, _, _, _ := candidateBlueprint.EvaluateModelPerformance(sessions)
+		exactAccuracy, generousAccuracy, forgivenessAccuracy, _, _, _ := candidateBlueprint.EvaluateModelPerformance(scoreSessions)
+
+		candidateSpread := scoreDistance(
+			EvaluationResult{ExactAccuracy: exactAccuracy, GenerousAccuracy: generousAccuracy, ForgivenessAccuracy: forgivenessAccuracy},
+			EvaluationResult{ExactAccuracy: bestExactAccuracy, GenerousAccuracy: bestGenerousAccuracy, ForgivenessAccuracy: bestForgivenessAccuracy})
 
 		// Check if the candidate model improves on any of the three metrics
-		if exactAccuracy > bestExactAccuracy ||
-			(exactAccuracy == bestExactAccuracy && (generousAccuracy > bestGenerousAccuracy || forgivenessAccuracy > bestForgivenessAccuracy)) {
+		improved := exactAccuracy > bestExactAccuracy ||
+			(exactAccuracy == bestExactAccuracy && (generousAccuracy > bestGenerousAccuracy || forgivenessAccuracy > bestForgivenessAccuracy))
+		rejected := false
+
+		// Reject candidates that regress on the guard set
+		if improved && len(cfg.GuardSessions) > 0 {
+			guard := candidateBlueprint.Evaluate(cfg.GuardSessions)
+			if reason := guardRegression(guard, bestGuard, cfg.GuardTolerance); reason != "" {
+				bp.infof("Iteration %d: Candidate rejected, %s.", iteration, reason)
+				improved = false
+				rejected = true
+			} else {
+				bestGuard = guard
+			}
+		}
+
+		if improved {
+			typeBandit.Update(neuronType, 1)
+
 			// Update the best model
 			bestBlueprint = candidateBlueprint
 			bestExactAccuracy = exactAccuracy
 			bestGenerousAccuracy = generousAccuracy
 			bestForgivenessAccuracy = forgivenessAccuracy
 
-			fmt.Printf("Iteration %d: Improved model found! Exact=%.2f%%, Generous=%.2f%%, Forgiveness=%.2f%%\n",
+			bp.infof("Iteration %d: Improved model found! Exact=%.2f%%, Generous=%.2f%%, Forgiveness=%.2f%%",
 				iteration, exactAccuracy, generousAccuracy, forgivenessAccuracy)
 
 			// Store progress
@@ -347,26 +386,50 @@ func (bp *Blueprint) SimpleNASWithRandomConnections(
 				GenerousAccuracy:    bestGenerousAccuracy,
 				ForgivenessAccuracy: bestForgivenessAccuracy,
 			})
-		} else {
-			fmt.Printf("Iteration %d: No improvement.\n", iteration)
+		} else if !rejected {
+			bp.infof("Iteration %d: No improvement.", iteration)
+		}
+
+		metrics := NASMetrics{
+			Iteration:           iteration,
+			ExactAccuracy:       bestExactAccuracy,
+			GenerousAccuracy:    bestGenerousAccuracy,
+			ForgivenessAccuracy: bestForgivenessAccuracy,
+			NeuronCount:         len(bestBlueprint.Neurons),
+			Improved:            improved,
+			CandidateSpread:     candidateSpread,
+		}
+		cfg.Metrics.Push(metrics)
+		watchdog.observe(EvaluationResult{
+			ExactAccuracy:       bestExactAccuracy,
+			GenerousAccuracy:    bestGenerousAccuracy,
+			ForgivenessAccuracy: bestForgivenessAccuracy,
+		}, improved, candidateSpread)
+		if stopErr = cfg.OnIteration.call(iteration, metrics); stopErr != nil {
+			bp.infof("NAS stopped by the progress callback: %v", stopErr)
+			break
 		}
 
 		// Early stopping if exact accuracy reaches 100%
 		if bestExactAccuracy == 100.0 {
-			fmt.Println("Perfect exact accuracy achieved. Stopping NAS.")
+			bp.infof("Perfect exact accuracy achieved. Stopping NAS.")
 			break
 		}
 	}
 
 	// Print progress
-	fmt.Println("NAS Progress:")
+	bp.infof("NAS Progress:")
 	for _, record := range progress {
-		fmt.Printf("Iteration %d: Exact=%.2f%%, Generous=%.2f%%, Forgiveness=%.2f%%\n",
+		bp.infof("Iteration %d: Exact=%.2f%%, Generous=%.2f%%, Forgiveness=%.2f%%",
 			record.Iteration, record.ExactAccuracy, record.GenerousAccuracy, record.ForgivenessAccuracy)
 	}
 
-	// Update the original blueprint with the best found
+	// Update the original blueprint with the best found, keeping what the type bandit learned
+	bandit := bp.typeBandit
 	*bp = *bestBlueprint
+	bp.typeBandit = bandit
+	bp.frozenNeurons = nil
+	return stopErr
 }
 
 // getRandomXNeurons retrieves `x` random neurons from the list, or fewer if not enough exist.
@@ -374,10 +437,145 @@ func getRandomXNeurons(neuronIDs []int, x int) []int {
 	if len(neuronIDs) <= x {
 		return neuronIDs
 	}
-	rand.Shuffle(len(neuronIDs), func(i, j int) { neuronIDs[i], neuronIDs[j] = neuronIDs[j], neuronIDs[i] })
+	random.Shuffle(len(neuronIDs), func(i, j int) { neuronIDs[i], neuronIDs[j] = neuronIDs[j], neuronIDs[i] })
 	return neuronIDs[:x]
 }
 
+// NASConfig configures ParallelNAS.
+type NASConfig struct {
+	MaxIterations          int      // Number of NAS iterations
+	NeuronTypes            []string // Neuron types candidates may insert
+	WeightUpdateIterations int      // Hill-climbing steps applied to each improved model
+	UseHillClimbing        bool     // Toggle for hill climbing
+	SaveImprovedModel      bool     // Toggle for saving improved models
+	SaveLocation           string   // Folder path to save improved models
+
+	// HillClimbPatience is the number of consecutive failed hill-climbing steps after which the steps stop early,
+	// see HillClimb. When 0, SimpleNASWithConfig runs every step and ParallelNAS stops at the first failure.
+	HillClimbPatience int
+
+	// EvalSampleSize scores candidates on a random subsample of this many sessions instead of
+	// the full set (0 uses every session). A candidate is only promoted after it also
+	// improves on the full session set. The sample is drawn from ValidationSessions when set.
+	EvalSampleSize int
+	// ResampleEvery is the number of iterations between drawing a new evaluation sample (0 resamples every iteration).
+	ResampleEvery int
+	// SmoothingAlpha, when set together with EvalSampleSize, compares candidates against an exponential moving
+	// average of the best model's sampled scores (see SmoothedMetrics) instead of its score on the current sample.
+	SmoothingAlpha float64
+
+	// GuardSessions is an optional held-out set that is never optimized on. A candidate that
+	// improves on the training sessions is still rejected if any metric on the guard set drops
+	// by more than GuardTolerance percentage points below the current best model. Generous accuracy, a fraction
+	// between 0 and 1, is compared as a percentage, so a tolerance of 1 allows it to drop by 0.01.
+	GuardSessions  []Session
+	GuardTolerance float64
+
+	// ValidationSessions, when set, is the held-out set candidates are scored and accepted on, see SplitSessions.
+	// Hill climbing still trains on the sessions passed to the search, so an improvement must carry over to data
+	// the weights were not fitted to. Guard sessions are checked on top of it.
+	ValidationSessions []Session
+
+	// Metrics receives the best model's metrics after every iteration when set.
+	Metrics *MetricsBuffer
+
+	// OnIteration is called with the best model's metrics after every iteration when set. Returning an error
+	// stops the search, and the WithContext variants return that error.
+	OnIteration ProgressFunc
+
+	// Workers is the number of candidates evaluated in parallel per iteration (0 uses RecommendWorkerCount).
+	Workers int
+
+	// StuckIterations is the number of iterations in which the best model does not change and every candidate
+	// scores exactly like it before a warning that the search may be broken is printed (0 uses 50, negative disables).
+	StuckIterations int
+
+	// AdaptiveTypes samples neuron types from the blueprint's type bandit (see AdaptiveTypeSampler)
+	// instead of uniformly, rewarding every type whose insertion improved the model.
+	AdaptiveTypes bool
+
+	// MutableNeuronIDs confines the search to a region of the network when set. Inserted neurons may read
+	// from any neuron, but only neurons of the region, and neurons inserted during the run, gain connections
+	// from them or have their weights changed by hill climbing. Every other neuron is left exactly as it was.
+	MutableNeuronIDs []int
+
+	// CheckpointEvery is the number of iterations between checkpoints of ParallelNAS written to CheckpointDir
+	// (0 disables checkpointing). A run interrupted after a checkpoint can be continued with ResumeNAS.
+	CheckpointEvery int
+	CheckpointDir   string
+
+	// resumeFrom is the checkpoint ResumeNAS continues from, nil for a fresh search.
+	resumeFrom *NASCheckpoint
+}
+
+// freezeOutside returns the set of neurons that are not in mutableIDs, or nil when mutableIDs is empty
+// and the whole network may change.
+func (bp *Blueprint) freezeOutside(mutableIDs []int) map[int]bool {
+	if len(mutableIDs) == 0 {
+		return nil
+	}
+	mutable := make(map[int]bool, len(mutableIDs))
+	for _, id := range mutableIDs {
+		if _, exists := bp.Neurons[id]; !exists {
+			bp.warnf("Warning: mutable neuron %d does not exist.", id)
+		}
+		mutable[id] = true
+	}
+	frozen := make(map[int]bool)
+	for id := range bp.Neurons {
+		if !mutable[id] {
+			frozen[id] = true
+		}
+	}
+	return frozen
+}
+
+// scoreSessions returns the sessions candidates are scored on: the validation set when one is configured,
+// otherwise the training sessions.
+func (cfg NASConfig) scoreSessions(sessions []Session) []Session {
+	if len(cfg.ValidationSessions) > 0 {
+		return cfg.ValidationSessions
+	}
+	return sessions
+}
+
+// isImprovement reports whether a candidate beats the current best: higher exact accuracy,
+// or equal exact accuracy with higher generous or forgiveness accuracy.
+func isImprovement(candidate, best EvaluationResult) bool {
+	return candidate.ExactAccuracy > best.ExactAccuracy ||
+		(candidate.ExactAccuracy == best.ExactAccuracy &&
+			(candidate.GenerousAccuracy > best.GenerousAccuracy || candidate.ForgivenessAccuracy > best.ForgivenessAccuracy))
+}
+
+// guardRegression compares a candidate's guard set metrics against the current best model and
+// returns the reason for rejecting it, or an empty string if no metric regressed beyond tolerance.
+func guardRegression(candidate, best EvaluationResult, tolerance float64) string {
+	if reason := metricRegression(candidate, best, tolerance); reason != "" {
+		return "guard " + reason
+	}
+	return ""
+}
+
+// metricRegression returns a description of the first metric that dropped by more than tolerance percentage
+// points from best to candidate, or an empty string if none did. Generous accuracy, which is a fraction
+// between 0 and 1, is scaled to a percentage first, so the one tolerance means the same for every metric.
+func metricRegression(candidate, best EvaluationResult, tolerance float64) string {
+	metrics := []struct {
+		name            string
+		candidate, best float64
+	}{
+		{"exact", candidate.ExactAccuracy, best.ExactAccuracy},
+		{"generous", candidate.GenerousAccuracy * 100, best.GenerousAccuracy * 100},
+		{"forgiveness", candidate.ForgivenessAccuracy, best.ForgivenessAccuracy},
+	}
+	for _, m := range metrics {
+		if m.best-m.candidate > tolerance {
+			return fmt.Sprintf("%s accuracy regressed from %.2f%% to %.2f%%", m.name, m.best, m.candidate)
+		}
+	}
+	return ""
+}
+
 // ParallelSimpleNASWithRandomConnections attempts to improve the blueprint using multi-threading.
 // It automatically detects the number of CPU cores and runs multiple candidate tests per iteration.
 // Hill climbing is only done on the best selected model of each iteration.
@@ -390,26 +588,101 @@ func (bp *Blueprint) ParallelSimpleNASWithRandomConnections(
 	saveImprovedModel bool, // Toggle for saving improved models
 	saveLocation string, // Folder path to save improved models
 ) {
-	// Seed the random number generator
-	rand.Seed(time.Now().UnixNano())
+	bp.ParallelNAS(sessions, NASConfig{
+		MaxIterations:          maxIterations,
+		NeuronTypes:            neuronTypes,
+		WeightUpdateIterations: weightUpdateIterations,
+		UseHillClimbing:        useHillClimbing,
+		SaveImprovedModel:      saveImprovedModel,
+		SaveLocation:           saveLocation,
+	})
+}
+
+// ParallelSimpleNASWithContext is ParallelSimpleNASWithRandomConnections that stops once ctx is cancelled,
+// see ParallelNASWithContext.
+func (bp *Blueprint) ParallelSimpleNASWithContext(
+	ctx context.Context,
+	sessions []Session,
+	maxIterations int,
+	neuronTypes []string,
+	weightUpdateIterations int,
+	useHillClimbing bool, // Toggle for hill climbing
+	saveImprovedModel bool, // Toggle for saving improved models
+	saveLocation string, // Folder path to save improved models
+) error {
+	return bp.ParallelNASWithContext(ctx, sessions, NASConfig{
+		MaxIterations:          maxIterations,
+		NeuronTypes:            neuronTypes,
+		WeightUpdateIterations: weightUpdateIterations,
+		UseHillClimbing:        useHillClimbing,
+		SaveImprovedModel:      saveImprovedModel,
+		SaveLocation:           saveLocation,
+	})
+}
+
+// ParallelNAS runs the parallel neural architecture search described by cfg.
+// Every iteration each worker inserts a random neuron into a clone of the current best model,
+// and the best improving candidate is kept. By default the number of workers is chosen by
+// RecommendWorkerCount so large models do not exhaust memory.
+func (bp *Blueprint) ParallelNAS(sessions []Session, cfg NASConfig) {
+	_ = bp.ParallelNASWithContext(context.Background(), sessions, cfg)
+}
 
+// ParallelNASWithContext is ParallelNAS that stops once ctx is cancelled. Cancellation is checked before every
+// iteration, by each worker before it builds and scores its candidate, and between hill-climbing steps. The
+// candidates of a cancelled iteration are discarded, the blueprint is left as the best model found so far and
+// ctx.Err() is returned, or the error of cfg.OnIteration when that stopped the search. A search that runs to the
+// end returns nil.
+func (bp *Blueprint) ParallelNASWithContext(ctx context.Context, sessions []Session, cfg NASConfig) error {
 	// Clone the initial blueprint
-	bestBlueprint := bp.Clone()
-	if bestBlueprint == nil {
-		fmt.Println("Failed to clone the initial blueprint.")
-		return
+	bestBlueprint := bp.DeepCopy()
+	bestBlueprint.frozenNeurons = bp.freezeOutside(cfg.MutableNeuronIDs)
+	firstIteration := 1
+	if cfg.resumeFrom != nil {
+		// Neurons inserted before the checkpoint stay mutable
+		firstIteration = cfg.resumeFrom.Iteration + 1
+		if bestBlueprint.frozenNeurons != nil {
+			bestBlueprint.frozenNeurons = idSet(cfg.resumeFrom.FrozenNeuronIDs)
+		}
 	}
 
+	// Candidates train on sessions but are accepted on the validation set when one is configured
+	scoreSessions := cfg.scoreSessions(sessions)
+
 	// Evaluate initial blueprint performance
-	bestExactAccuracy, bestGenerousAccuracy, bestForgivenessAccuracy, _, _, _ :=
-		bestBlueprint.EvaluateModelPerformance(sessions)
+	best := bestBlueprint.Evaluate(scoreSessions)
 
-	fmt.Printf("Initial model performance: Exact=%.2f%%, Generous=%.2f%%, Forgiveness=%.2f%%\n",
-		bestExactAccuracy, bestGenerousAccuracy, bestForgivenessAccuracy)
+	bp.infof("Initial model performance: Exact=%.2f%%, Generous=%.2f%%, Forgiveness=%.2f%%",
+		best.ExactAccuracy, best.GenerousAccuracy, best.ForgivenessAccuracy)
 
 	// Determine the level of parallelism
-	numWorkers := runtime.NumCPU()
-	fmt.Printf("Running with %d parallel workers.\n", numWorkers)
+	numWorkers := cfg.Workers
+	if numWorkers <= 0 {
+		numWorkers = bestBlueprint.RecommendWorkerCount(sessions)
+	}
+	bp.infof("Running with %d parallel workers.", numWorkers)
+
+	var typeBandit *Bandit
+	if cfg.AdaptiveTypes {
+		typeBandit = bp.neuronTypeBandit(cfg.NeuronTypes)
+	}
+	watchdog := newStuckWatchdog(cfg.StuckIterations, bp.warnf)
+
+	// Candidates are scored on evalSessions, which is a random sample when EvalSampleSize is set
+	useSample := cfg.EvalSampleSize > 0 && cfg.EvalSampleSize < len(scoreSessions)
+	evalSessions := scoreSessions
+	bestOnSample := best
+	if useSample {
+		bp.infof("Scoring candidates on %d of %d sessions.", cfg.EvalSampleSize, len(scoreSessions))
+	}
+
+	// Baseline on the held-out guard set
+	var bestGuard EvaluationResult
+	if len(cfg.GuardSessions) > 0 {
+		bestGuard = bestBlueprint.Evaluate(cfg.GuardSessions)
+		bp.infof("Initial guard performance: Exact=%.2f%%, Generous=%.2f%%, Forgiveness=%.2f%%",
+			bestGuard.ExactAccuracy, bestGuard.GenerousAccuracy, bestGuard.ForgivenessAccuracy)
+	}
 
 	// Helper functions for serialization
 	serializeBlueprint := func(bp *Blueprint) (string, error) {
@@ -421,28 +694,46 @@ func (bp *Blueprint) ParallelSimpleNASWithRandomConnections(
 	}
 
 	saveModelToFile := func(bp *Blueprint, iteration int) {
-		if !saveImprovedModel {
+		if !cfg.SaveImprovedModel {
 			return
 		}
 
-		fileName := fmt.Sprintf("%s/iteration%d_model_%d.json", saveLocation, iteration, time.Now().Unix())
+		fileName := fmt.Sprintf("%s/iteration%d_model_%d.json", cfg.SaveLocation, iteration, time.Now().Unix())
 		serializedModel, err := serializeBlueprint(bp)
 		if err != nil {
-			fmt.Printf("Error serializing model for saving: %v\n", err)
+			bp.warnf("Error serializing model for saving: %v", err)
 			return
 		}
 
 		err = os.WriteFile(fileName, []byte(serializedModel), 0644)
 		if err != nil {
-			fmt.Printf("Error saving model to file: %v\n", err)
+			bp.warnf("Error saving model to file: %v", err)
 			return
 		}
-		fmt.Printf("Model saved to %s\n", fileName)
+		bp.infof("Model saved to %s", fileName)
 	}
 
 	// Main NAS loop
-	for iteration := 1; iteration <= maxIterations; iteration++ {
-		fmt.Printf("=== Iteration %d ===\n", iteration)
+	var stopErr error
+	for iteration := firstIteration; iteration <= cfg.MaxIterations; iteration++ {
+		if stopErr = ctx.Err(); stopErr != nil {
+			bp.infof("NAS cancelled after iteration %d.", iteration-1)
+			break
+		}
+		bp.infof("=== Iteration %d ===", iteration)
+
+		// Draw a fresh evaluation sample and rescore the best model on it
+		if useSample && (cfg.ResampleEvery <= 0 || (iteration-firstIteration)%cfg.ResampleEvery == 0) {
+			evalSessions = sampleSessions(scoreSessions, cfg.EvalSampleSize, random.Int63())
+			bestOnSample = bestBlueprint.Evaluate(evalSessions)
+			bestBlueprint.recordScore(bestOnSample)
+		}
+
+		// Candidates must beat the best model's smoothed score rather than a single noisy sample
+		reference := bestOnSample
+		if useSample && cfg.SmoothingAlpha > 0 {
+			reference = bestBlueprint.SmoothedMetrics(cfg.SmoothingAlpha)
+		}
 
 		// Generate candidates in parallel
 		var wg sync.WaitGroup
@@ -452,29 +743,32 @@ func (bp *Blueprint) ParallelSimpleNASWithRandomConnections(
 			wg.Add(1)
 			go func() {
 				defer wg.Done()
-
-				// Clone the current best blueprint
-				candidateBlueprint := bestBlueprint.Clone()
-				if candidateBlueprint == nil {
+				if ctx.Err() != nil {
 					return
 				}
 
+				// Clone the current best blueprint
+				candidateBlueprint := bestBlueprint.DeepCopy()
+
 				// Add a new neuron
-				neuronType := neuronTypes[rand.Intn(len(neuronTypes))]
+				neuronType := sampleNeuronType(typeBandit, cfg.NeuronTypes)
 				if err := candidateBlueprint.InsertNeuronOfTypeBetweenInputsAndOutputs(neuronType); err != nil {
 					return
 				}
+				if ctx.Err() != nil {
+					return
+				}
 
 				// Evaluate the candidate
-				exactAccuracy, generousAccuracy, forgivenessAccuracy, _, _, _ :=
-					candidateBlueprint.EvaluateModelPerformance(sessions)
+				result := candidateBlueprint.Evaluate(evalSessions)
 
 				// Send result to channel
 				resultsChan <- candidateResult{
-					ExactAccuracy:       exactAccuracy,
-					GenerousAccuracy:    generousAccuracy,
-					ForgivenessAccuracy: forgivenessAccuracy,
+					ExactAccuracy:       result.ExactAccuracy,
+					GenerousAccuracy:    result.GenerousAccuracy,
+					ForgivenessAccuracy: result.ForgivenessAccuracy,
 					CandidateBlueprint:  candidateBlueprint,
+					NeuronType:          neuronType,
 				}
 			}()
 		}
@@ -482,42 +776,107 @@ func (bp *Blueprint) ParallelSimpleNASWithRandomConnections(
 		// Wait for all workers
 		wg.Wait()
 		close(resultsChan)
+		if stopErr = ctx.Err(); stopErr != nil {
+			bp.infof("NAS cancelled during iteration %d.", iteration)
+			break
+		}
 
 		// Process results
 		var bestIterationCandidate *Blueprint
-		improved := false
+		iterationBest := reference
+		candidateSpread := 0.0
 
 		for res := range resultsChan {
-			if res.ExactAccuracy > bestExactAccuracy ||
-				(res.ExactAccuracy == bestExactAccuracy && (res.GenerousAccuracy > bestGenerousAccuracy || res.ForgivenessAccuracy > bestForgivenessAccuracy)) {
+			result := EvaluationResult{
+				ExactAccuracy:       res.ExactAccuracy,
+				GenerousAccuracy:    res.GenerousAccuracy,
+				ForgivenessAccuracy: res.ForgivenessAccuracy,
+			}
+			candidateSpread = math.Max(candidateSpread, scoreDistance(result, reference))
+			if isImprovement(result, reference) {
+				typeBandit.Update(res.NeuronType, 1)
+			}
+			if isImprovement(result, iterationBest) {
 				bestIterationCandidate = res.CandidateBlueprint
-				bestExactAccuracy = res.ExactAccuracy
-				bestGenerousAccuracy = res.GenerousAccuracy
-				bestForgivenessAccuracy = res.ForgivenessAccuracy
-				improved = true
+				iterationBest = result
 			}
 		}
 
-		if improved && bestIterationCandidate != nil {
-			if useHillClimbing {
-				for w := 0; w < weightUpdateIterations; w++ {
-					if !bestIterationCandidate.HillClimbWeightUpdate(sessions) {
-						break
-					}
+		improved := bestIterationCandidate != nil
+		candidateBest := iterationBest
+		if improved && useSample {
+			// Only promote a sampled winner if it also improves on the full session set
+			candidateBest = bestIterationCandidate.Evaluate(scoreSessions)
+			if !isImprovement(candidateBest, best) {
+				improved = false
+				bp.infof("Iteration %d: Candidate improved on the sample but not on the full session set.", iteration)
+			}
+		}
+		if improved && len(cfg.GuardSessions) > 0 {
+			// Reject candidates that regress on the guard set
+			guard := bestIterationCandidate.Evaluate(cfg.GuardSessions)
+			if reason := guardRegression(guard, bestGuard, cfg.GuardTolerance); reason != "" {
+				improved = false
+				bp.infof("Iteration %d: Candidate rejected, %s.", iteration, reason)
+			} else {
+				bestGuard = guard
+			}
+		}
+		if improved {
+			best = candidateBest
+			bestOnSample = iterationBest
+			bestIterationCandidate.recordScore(iterationBest)
+		}
+
+		if improved {
+			if cfg.UseHillClimbing {
+				patience := cfg.HillClimbPatience
+				if patience <= 0 {
+					patience = 1
 				}
+				bestIterationCandidate.hillClimb(ctx, sessions, cfg.WeightUpdateIterations, patience)
 			}
 
 			bestBlueprint = bestIterationCandidate
+			bandit := bp.typeBandit
 			*bp = *bestBlueprint // Update the original blueprint as well
-			fmt.Printf("Iteration %d: Improved model found! Exact=%.2f%%, Generous=%.2e, Forgiveness=%.2f%%\n",
-				iteration, bestExactAccuracy, bestGenerousAccuracy, bestForgivenessAccuracy)
+			bp.typeBandit = bandit
+			bp.infof("Iteration %d: Improved model found! Exact=%.2f%%, Generous=%.2e, Forgiveness=%.2f%%",
+				iteration, best.ExactAccuracy, best.GenerousAccuracy, best.ForgivenessAccuracy)
 
 			// Save the improved model
 			saveModelToFile(bestBlueprint, iteration)
 		} else {
-			fmt.Printf("Iteration %d: No improvement.\n", iteration)
+			bp.infof("Iteration %d: No improvement.", iteration)
+		}
+
+		metrics := NASMetrics{
+			Iteration:           iteration,
+			ExactAccuracy:       best.ExactAccuracy,
+			GenerousAccuracy:    best.GenerousAccuracy,
+			ForgivenessAccuracy: best.ForgivenessAccuracy,
+			NeuronCount:         len(bestBlueprint.Neurons),
+			Improved:            improved,
+			CandidateSpread:     candidateSpread,
+		}
+		cfg.Metrics.Push(metrics)
+		watchdog.observe(best, improved, candidateSpread)
+
+		if cfg.CheckpointEvery > 0 && iteration%cfg.CheckpointEvery == 0 {
+			if err := bestBlueprint.writeNASCheckpoint(cfg.CheckpointDir, iteration, best); err != nil {
+				bp.warnf("Error writing checkpoint: %v", err)
+			}
+		}
+		if stopErr = cfg.OnIteration.call(iteration, metrics); stopErr != nil {
+			bp.infof("NAS stopped by the progress callback: %v", stopErr)
+			break
 		}
 	}
+
+	// Keep the samples scored since the last promotion available through SmoothedMetrics
+	bp.scoreHistory = bestBlueprint.scoreHistory
+	bp.frozenNeurons = nil
+	return stopErr
 }
 
 func (bp *Blueprint) AdvancedParallelSimpleNASWithRandomConnections(
@@ -529,26 +888,19 @@ func (bp *Blueprint) AdvancedParallelSimpleNASWithRandomConnections(
 	saveImprovedModel bool, // Toggle for saving improved models
 	saveLocation string, // Folder path to save improved models
 ) {
-	// Seed the random number generator
-	rand.Seed(time.Now().UnixNano())
-
 	// Clone the initial blueprint
-	bestBlueprint := bp.Clone()
-	if bestBlueprint == nil {
-		fmt.Println("Failed to clone the initial blueprint.")
-		return
-	}
+	bestBlueprint := bp.DeepCopy()
 
 	// Evaluate initial blueprint performance
 	bestExactAccuracy, bestGenerousAccuracy, bestAdvancedMetrics, bestDecileConsistency, _, _, _ :=
 		bestBlueprint.AdvancedEvaluateModelPerformance(sessions)
 
-	fmt.Printf("Initial model performance: Exact=%.2f%%, Generous=%.2f%%, DecileConsistency=%.2f%%\n",
+	bp.infof("Initial model performance: Exact=%.2f%%, Generous=%.2f%%, DecileConsistency=%.2f%%",
 		bestExactAccuracy, bestGenerousAccuracy, bestDecileConsistency)
 
 	// Determine the level of parallelism
 	numWorkers := runtime.NumCPU()
-	fmt.Printf("Running with %d parallel workers.\n", numWorkers)
+	bp.infof("Running with %d parallel workers.", numWorkers)
 
 	// Helper functions for serialization
 	serializeBlueprint := func(bp *Blueprint) (string, error) {
@@ -567,21 +919,21 @@ func (bp *Blueprint) AdvancedParallelSimpleNASWithRandomConnections(
 		fileName := fmt.Sprintf("%s/iteration%d_model_%d.json", saveLocation, iteration, time.Now().Unix())
 		serializedModel, err := serializeBlueprint(bp)
 		if err != nil {
-			fmt.Printf("Error serializing model for saving: %v\n", err)
+			bp.warnf("Error serializing model for saving: %v", err)
 			return
 		}
 
 		err = os.WriteFile(fileName, []byte(serializedModel), 0644)
 		if err != nil {
-			fmt.Printf("Error saving model to file: %v\n", err)
+			bp.warnf("Error saving model to file: %v", err)
 			return
 		}
-		fmt.Printf("Model saved to %s\n", fileName)
+		bp.infof("Model saved to %s", fileName)
 	}
 
 	// Main NAS loop
 	for iteration := 1; iteration <= maxIterations; iteration++ {
-		fmt.Printf("=== Iteration %d ===\n", iteration)
+		bp.infof("=== Iteration %d ===", iteration)
 
 		// Generate candidates in parallel
 		var wg sync.WaitGroup
@@ -593,13 +945,10 @@ func (bp *Blueprint) AdvancedParallelSimpleNASWithRandomConnections(
 				defer wg.Done()
 
 				// Clone the current best blueprint
-				candidateBlueprint := bestBlueprint.Clone()
-				if candidateBlueprint == nil {
-					return
-				}
+				candidateBlueprint := bestBlueprint.DeepCopy()
 
 				// Add a new neuron
-				neuronType := neuronTypes[rand.Intn(len(neuronTypes))]
+				neuronType := neuronTypes[random.Intn(len(neuronTypes))]
 				if err := candidateBlueprint.InsertNeuronOfTypeBetweenInputsAndOutputs(neuronType); err != nil {
 					return
 				}
@@ -609,7 +958,7 @@ func (bp *Blueprint) AdvancedParallelSimpleNASWithRandomConnections(
 					candidateBlueprint.AdvancedEvaluateModelPerformance(sessions)
 
 				// Log the metrics for debugging
-				fmt.Printf("Candidate Metrics: Exact=%.2f, Generous=%.2f, DecileConsistency=%.2f, WeightedProximity=%.2f\n",
+				bp.infof("Candidate Metrics: Exact=%.2f, Generous=%.2f, DecileConsistency=%.2f, WeightedProximity=%.2f",
 					exactAccuracy, generousAccuracy, decileConsistency, advancedMetrics["weightedProximity"])
 
 				// Send result to channel
@@ -647,22 +996,18 @@ func (bp *Blueprint) AdvancedParallelSimpleNASWithRandomConnections(
 
 		if improved && bestIterationCandidate != nil {
 			if useHillClimbing {
-				for w := 0; w < weightUpdateIterations; w++ {
-					if !bestIterationCandidate.HillClimbWeightUpdate(sessions) {
-						break
-					}
-				}
+				bestIterationCandidate.HillClimb(sessions, weightUpdateIterations, 1)
 			}
 
 			bestBlueprint = bestIterationCandidate
 			*bp = *bestBlueprint // Update the original blueprint as well
-			fmt.Printf("Iteration %d: Improved model found! Exact=%.2f%%, Generous=%.2f%%, DecileConsistency=%.2f%%\n",
+			bp.infof("Iteration %d: Improved model found! Exact=%.2f%%, Generous=%.2f%%, DecileConsistency=%.2f%%",
 				iteration, bestExactAccuracy, bestGenerousAccuracy, bestDecileConsistency)
 
 			// Save the improved model
 			saveModelToFile(bestBlueprint, iteration)
 		} else {
-			fmt.Printf("Iteration %d: No improvement.\n", iteration)
+			bp.infof("Iteration %d: No improvement.", iteration)
 		}
 	}
 }
@@ -678,26 +1023,19 @@ func (bp *Blueprint) AdvancedParallelNASWithDynamicNeuronGeneration(
 	maxTriesWithoutImprovement int, // Number of tries before increasing neuron range
 	batchSize int, // Number of batches per iteration
 ) {
-	// Seed the random number generator
-	rand.Seed(time.Now().UnixNano())
-
 	// Clone the initial blueprint
-	bestBlueprint := bp.Clone()
-	if bestBlueprint == nil {
-		fmt.Println("Failed to clone the initial blueprint.")
-		return
-	}
+	bestBlueprint := bp.DeepCopy()
 
 	// Evaluate initial blueprint performance
 	bestExactAccuracy, bestGenerousAccuracy, bestAdvancedMetrics, bestDecileConsistency, _, _, _ :=
 		bestBlueprint.AdvancedEvaluateModelPerformance(sessions)
 
-	fmt.Printf("Initial model performance: Exact=%.2f%%, Generous=%.2f%%, DecileConsistency=%.2f%%\n",
+	bp.infof("Initial model performance: Exact=%.2f%%, Generous=%.2f%%, DecileConsistency=%.2f%%",
 		bestExactAccuracy, bestGenerousAccuracy, bestDecileConsistency)
 
 	// Determine the level of parallelism
 	numWorkers := runtime.NumCPU()
-	fmt.Printf("Running with %d parallel workers.\n", numWorkers)
+	bp.infof("Running with %d parallel workers.", numWorkers)
 
 	// Helper functions for serialization
 	serializeBlueprint := func(bp *Blueprint) (string, error) {
@@ -708,14 +1046,6 @@ func (bp *Blueprint) AdvancedParallelNASWithDynamicNeuronGeneration(
 		return string(data), nil
 	}
 
-	deserializeBlueprint := func(data string) (*Blueprint, error) {
-		newBP := &Blueprint{}
-		if err := json.Unmarshal([]byte(data), newBP); err != nil {
-			return nil, err
-		}
-		return newBP, nil
-	}
-
 	saveModelToFile := func(bp *Blueprint, iteration int) {
 		if !saveImprovedModel {
 			return
@@ -724,16 +1054,16 @@ func (bp *Blueprint) AdvancedParallelNASWithDynamicNeuronGeneration(
 		fileName := fmt.Sprintf("%s/iteration%d_model_%d.json", saveLocation, iteration, time.Now().Unix())
 		serializedModel, err := serializeBlueprint(bp)
 		if err != nil {
-			fmt.Printf("Error serializing model for saving: %v\n", err)
+			bp.warnf("Error serializing model for saving: %v", err)
 			return
 		}
 
 		err = os.WriteFile(fileName, []byte(serializedModel), 0644)
 		if err != nil {
-			fmt.Printf("Error saving model to file: %v\n", err)
+			bp.warnf("Error saving model to file: %v", err)
 			return
 		}
-		fmt.Printf("Model saved to %s\n", fileName)
+		bp.infof("Model saved to %s", fileName)
 	}
 
 	// Initialize variables for dynamic range adjustment
@@ -742,7 +1072,7 @@ func (bp *Blueprint) AdvancedParallelNASWithDynamicNeuronGeneration(
 
 	// Main NAS loop
 	for iteration := 1; iteration <= maxIterations; iteration++ {
-		fmt.Printf("=== Iteration %d ===\n", iteration)
+		bp.infof("=== Iteration %d ===", iteration)
 
 		// Generate candidates in parallel within batches
 		for batch := 0; batch < batchSize; batch++ {
@@ -754,23 +1084,13 @@ func (bp *Blueprint) AdvancedParallelNASWithDynamicNeuronGeneration(
 				go func() {
 					defer wg.Done()
 
-					// Serialize and deserialize to ensure isolation
-					serializedBlueprint, err := serializeBlueprint(bestBlueprint)
-					if err != nil {
-						fmt.Printf("Error serializing blueprint: %v\n", err)
-						return
-					}
-
-					candidateBlueprint, err := deserializeBlueprint(serializedBlueprint)
-					if err != nil {
-						fmt.Printf("Error deserializing blueprint: %v\n", err)
-						return
-					}
+					// Clone the current best blueprint
+					candidateBlueprint := bestBlueprint.DeepCopy()
 
 					// Add a random number of neurons within the current range
-					numNeurons := rand.Intn(currentNeuronRange) + 1
+					numNeurons := random.Intn(currentNeuronRange) + 1
 					for i := 0; i < numNeurons; i++ {
-						neuronType := neuronTypes[rand.Intn(len(neuronTypes))]
+						neuronType := neuronTypes[random.Intn(len(neuronTypes))]
 						if err := candidateBlueprint.InsertNeuronOfTypeBetweenInputsAndOutputs(neuronType); err != nil {
 							return
 						}
@@ -778,7 +1098,7 @@ func (bp *Blueprint) AdvancedParallelNASWithDynamicNeuronGeneration(
 
 					// Validate connections
 					if !candidateBlueprint.ValidateConnections() {
-						fmt.Println("Candidate blueprint has invalid connections. Skipping.")
+						bp.warnf("Candidate blueprint has invalid connections. Skipping.")
 						return
 					}
 
@@ -787,7 +1107,7 @@ func (bp *Blueprint) AdvancedParallelNASWithDynamicNeuronGeneration(
 						candidateBlueprint.AdvancedEvaluateModelPerformance(sessions)
 
 					// Log the metrics for debugging
-					fmt.Printf("Candidate Metrics: Exact=%.2f, Generous=%.2f, DecileConsistency=%.2f, WeightedProximity=%.2f\n",
+					bp.infof("Candidate Metrics: Exact=%.2f, Generous=%.2f, DecileConsistency=%.2f, WeightedProximity=%.2f",
 						exactAccuracy, generousAccuracy, decileConsistency, advancedMetrics["weightedProximity"])
 
 					// Send result to channel
@@ -827,27 +1147,23 @@ func (bp *Blueprint) AdvancedParallelNASWithDynamicNeuronGeneration(
 				triesWithoutImprovement = 0 // Reset tries without improvement
 
 				if useHillClimbing {
-					for w := 0; w < weightUpdateIterations; w++ {
-						if !bestIterationCandidate.HillClimbWeightUpdate(sessions) {
-							break
-						}
-					}
+					bestIterationCandidate.HillClimb(sessions, weightUpdateIterations, 1)
 				}
 
 				bestBlueprint = bestIterationCandidate
 				*bp = *bestBlueprint // Update the original blueprint as well
-				fmt.Printf("Iteration %d: Improved model found! Exact=%.2f%%, Generous=%.2f%%, DecileConsistency=%.2f%%\n",
+				bp.infof("Iteration %d: Improved model found! Exact=%.2f%%, Generous=%.2f%%, DecileConsistency=%.2f%%",
 					iteration, bestExactAccuracy, bestGenerousAccuracy, bestDecileConsistency)
 
 				// Save the improved model
 				saveModelToFile(bestBlueprint, iteration)
 			} else {
 				triesWithoutImprovement++
-				fmt.Printf("Iteration %d: No improvement.\n", iteration)
+				bp.infof("Iteration %d: No improvement.", iteration)
 				if triesWithoutImprovement >= maxTriesWithoutImprovement {
 					currentNeuronRange++ // Increase the range for neuron generation
 					triesWithoutImprovement = 0
-					fmt.Printf("Increasing neuron generation range to %d.\n", currentNeuronRange)
+					bp.infof("Increasing neuron generation range to %d.", currentNeuronRange)
 				}
 			}
 		}
@@ -0,0 +1,148 @@
+package blueprint
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Files of a NAS checkpoint directory.
+const (
+	checkpointModelFile = "model.json"
+	checkpointStateFile = "state.json"
+)
+
+// NASCheckpoint is the search state ParallelNAS writes to state.json in the checkpoint directory, next to the best
+// model in model.json, every CheckpointEvery iterations.
+type NASCheckpoint struct {
+	Iteration           int       `json:"iteration"` // Last completed iteration
+	ExactAccuracy       float64   `json:"exact_accuracy"`
+	GenerousAccuracy    float64   `json:"generous_accuracy"`
+	ForgivenessAccuracy float64   `json:"forgiveness_accuracy"`
+	RandomSeed          int64     `json:"random_seed"`                 // Seed the package generator was reset to
+	FrozenNeuronIDs     []int     `json:"frozen_neuron_ids,omitempty"` // Neurons outside MutableNeuronIDs
+	SavedAt             time.Time `json:"saved_at"`
+}
+
+// writeNASCheckpoint saves the blueprint and the search state to dir, creating it if needed. The generator's state
+// cannot be serialized, so it is reseeded with a seed drawn from itself and the seed is recorded instead; a resumed
+// run then draws the same numbers as one that was never interrupted. Each file is written to a temporary file
+// first and renamed, so a crash never leaves a half-written checkpoint.
+func (bp *Blueprint) writeNASCheckpoint(dir string, iteration int, best EvaluationResult) error {
+	if dir == "" {
+		return fmt.Errorf("no checkpoint directory configured")
+	}
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return fmt.Errorf("failed to create checkpoint directory: %w", err)
+	}
+
+	seed := random.Int63()
+	randomSource.Seed(seed)
+	checkpoint := NASCheckpoint{
+		Iteration:           iteration,
+		ExactAccuracy:       best.ExactAccuracy,
+		GenerousAccuracy:    best.GenerousAccuracy,
+		ForgivenessAccuracy: best.ForgivenessAccuracy,
+		RandomSeed:          seed,
+		SavedAt:             time.Now(),
+	}
+	for id := range bp.frozenNeurons {
+		checkpoint.FrozenNeuronIDs = append(checkpoint.FrozenNeuronIDs, id)
+	}
+	sort.Ints(checkpoint.FrozenNeuronIDs)
+
+	// The model goes first so the state never points past it
+	model, err := json.Marshal(bp)
+	if err != nil {
+		return fmt.Errorf("failed to serialize checkpoint model: %w", err)
+	}
+	if err := writeFileAtomic(filepath.Join(dir, checkpointModelFile), model); err != nil {
+		return err
+	}
+	state, err := json.MarshalIndent(checkpoint, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to serialize checkpoint state: %w", err)
+	}
+	if err := writeFileAtomic(filepath.Join(dir, checkpointStateFile), state); err != nil {
+		return err
+	}
+	bp.infof("Checkpoint for iteration %d saved to %s", iteration, dir)
+	return nil
+}
+
+// LoadNASCheckpoint reads the search state of a checkpoint directory written by ParallelNAS.
+func LoadNASCheckpoint(dir string) (NASCheckpoint, error) {
+	var checkpoint NASCheckpoint
+	data, err := os.ReadFile(filepath.Join(dir, checkpointStateFile))
+	if err != nil {
+		return checkpoint, fmt.Errorf("failed to read checkpoint state: %w", err)
+	}
+	if err := json.Unmarshal(data, &checkpoint); err != nil {
+		return checkpoint, fmt.Errorf("failed to deserialize checkpoint state: %w", err)
+	}
+	return checkpoint, nil
+}
+
+// ResumeNAS replaces the blueprint with the best model of a checkpoint written by ParallelNAS, restores the random
+// generator and continues the search with cfg from the iteration after the checkpoint up to cfg.MaxIterations.
+// Sessions and configuration are not part of the checkpoint and must be passed again; when cfg has no
+// CheckpointDir, checkpoints keep going to checkpointDir. The type bandit and the sample scores behind
+// SmoothedMetrics start empty.
+func (bp *Blueprint) ResumeNAS(checkpointDir string, sessions []Session, cfg NASConfig) error {
+	checkpoint, err := LoadNASCheckpoint(checkpointDir)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(filepath.Join(checkpointDir, checkpointModelFile))
+	if err != nil {
+		return fmt.Errorf("failed to read checkpoint model: %w", err)
+	}
+	restored := &Blueprint{}
+	if err := restored.DeserializesFromJSON(string(data)); err != nil {
+		return fmt.Errorf("failed to deserialize checkpoint model: %w", err)
+	}
+	restored.Debug = bp.Debug
+	restored.Logger = bp.Logger
+	*bp = *restored
+
+	bp.infof("Resuming NAS after iteration %d: Exact=%.2f%%, Generous=%.2f, Forgiveness=%.2f%%",
+		checkpoint.Iteration, checkpoint.ExactAccuracy, checkpoint.GenerousAccuracy, checkpoint.ForgivenessAccuracy)
+	if checkpoint.Iteration >= cfg.MaxIterations {
+		bp.infof("Checkpoint already reached MaxIterations. Nothing to resume.")
+		return nil
+	}
+	randomSource.Seed(checkpoint.RandomSeed)
+	if cfg.CheckpointDir == "" {
+		cfg.CheckpointDir = checkpointDir
+	}
+	cfg.resumeFrom = &checkpoint
+	bp.ParallelNAS(sessions, cfg)
+	return nil
+}
+
+// writeFileAtomic writes data to a temporary file next to path and renames it into place.
+func writeFileAtomic(path string, data []byte) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", tmp, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to move %s into place: %w", tmp, err)
+	}
+	return nil
+}
+
+// idSet returns the IDs as a set, or nil when there are none.
+func idSet(ids []int) map[int]bool {
+	if len(ids) == 0 {
+		return nil
+	}
+	set := make(map[int]bool, len(ids))
+	for _, id := range ids {
+		set[id] = true
+	}
+	return set
+}
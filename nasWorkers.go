@@ -0,0 +1,74 @@
+package blueprint
+
+import (
+	"runtime"
+	"unsafe"
+
+	"github.com/shirou/gopsutil/v3/mem"
+)
+
+const (
+	// bytesPerParameter approximates the memory one weight costs a NAS worker: the [][]float64
+	// entry in the cloned candidate plus its text in the JSON buffer Clone goes through.
+	bytesPerParameter = 96
+	// bytesPerSessionValue approximates the per-value allocations made while evaluating a session.
+	bytesPerSessionValue = 64
+	// workerMemoryFraction is the share of available memory the NAS workers may use together.
+	workerMemoryFraction = 0.5
+)
+
+// ParameterCount returns the number of trainable values in the model: biases, connection weights,
+// RNN recurrent weights, LSTM gate weights, CNN kernels, attention weights and batch normalization parameters.
+func (bp *Blueprint) ParameterCount() int {
+	count := 0
+	for _, neuron := range bp.Neurons {
+		if neuron.Type == "input" {
+			continue
+		}
+		count += 1 + neuron.numConnections() + len(neuron.AttentionWeights)
+		for _, weights := range neuron.GateWeights {
+			count += len(weights)
+		}
+		for _, kernel := range neuron.Kernels {
+			count += len(kernel)
+		}
+		if neuron.BatchNormParams != nil {
+			count += 4
+		}
+		if neuron.Type == "rnn" {
+			count++
+		}
+	}
+	return count
+}
+
+// RecommendWorkerCount returns how many parallel NAS candidates fit in memory for this model.
+// Each worker holds a cloned candidate and evaluates it over the sessions, so its footprint is
+// estimated from the parameter count, the neuron count and the size of the sessions. The result
+// is capped at runtime.NumCPU() and at half of the currently available memory, and is at least 1.
+// If the available memory cannot be read, runtime.NumCPU() is returned.
+func (bp *Blueprint) RecommendWorkerCount(sessions []Session) int {
+	workers := runtime.NumCPU()
+
+	vmStat, err := mem.VirtualMemory()
+	if err != nil {
+		return workers
+	}
+
+	sessionValues := 0
+	for _, session := range sessions {
+		sessionValues += len(session.InputVariables) + len(session.ExpectedOutput)
+	}
+	perWorker := uint64(bp.ParameterCount())*bytesPerParameter +
+		uint64(len(bp.Neurons))*uint64(unsafe.Sizeof(Neuron{})) +
+		uint64(sessionValues)*bytesPerSessionValue
+
+	budget := uint64(float64(vmStat.Available) * workerMemoryFraction)
+	if perWorker > 0 && budget/perWorker < uint64(workers) {
+		workers = int(budget / perWorker)
+	}
+	if workers < 1 {
+		workers = 1
+	}
+	return workers
+}
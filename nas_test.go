@@ -0,0 +1,220 @@
+package blueprint
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestCloneKeepsLSTMGateWeightsExactly(t *testing.T) {
+	bp := NewBlueprint()
+	bp.AddInputNeurons([]int{1, 2})
+	gateWeights := map[string][]float64{
+		"input":  {0.1, -1.0 / 3},
+		"forget": {math.Pi, -math.SmallestNonzeroFloat64},
+		"output": {math.MaxFloat64, 1e-300},
+		"cell":   {-0.7071067811865476, 2},
+	}
+	bp.Neurons[3] = &Neuron{
+		ID: 3, Type: "lstm", Activation: "tanh", CellState: 0.123456789012345678,
+		Connections: [][]float64{{1, 0.5}, {2, -0.25}},
+		GateWeights: gateWeights,
+	}
+	bp.AddOutputNodes([]int{3})
+
+	clone := bp.Clone()
+	if clone == nil {
+		t.Fatal("Clone failed")
+	}
+	neuron := clone.Neurons[3]
+	if neuron == bp.Neurons[3] {
+		t.Fatal("the clone shares the neuron")
+	}
+	if math.Float64bits(neuron.CellState) != math.Float64bits(bp.Neurons[3].CellState) {
+		t.Errorf("cell state is %v, want %v", neuron.CellState, bp.Neurons[3].CellState)
+	}
+	if len(neuron.GateWeights) != len(gateWeights) {
+		t.Fatalf("clone has gates %v, want %v", neuron.GateWeights, gateWeights)
+	}
+	for gate, weights := range gateWeights {
+		cloned := neuron.GateWeights[gate]
+		if len(cloned) != len(weights) {
+			t.Errorf("%s gate has %d weights, want %d", gate, len(cloned), len(weights))
+			continue
+		}
+		for i, weight := range weights {
+			if math.Float64bits(cloned[i]) != math.Float64bits(weight) {
+				t.Errorf("%s gate weight %d is %v, want %v", gate, i, cloned[i], weight)
+			}
+		}
+	}
+
+	neuron.GateWeights["input"][0] = 42
+	if gateWeights["input"][0] == 42 {
+		t.Error("changing the clone's gate weights changed the original")
+	}
+}
+
+func TestResumeNASKeepsTheCheckpointedModel(t *testing.T) {
+	randomSource.Seed(3)
+	sessions := xorSessions()
+	dir := t.TempDir()
+	cfg := NASConfig{
+		MaxIterations:          4,
+		NeuronTypes:            []string{"dense"},
+		WeightUpdateIterations: 5,
+		UseHillClimbing:        true,
+		Workers:                2,
+		CheckpointEvery:        2,
+		CheckpointDir:          dir,
+	}
+	bp := NewDenseMLP([]int{2, 2, 2}, "relu")
+	bp.ParallelNAS(sessions, cfg)
+
+	checkpoint, err := LoadNASCheckpoint(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if checkpoint.Iteration != 4 {
+		t.Fatalf("checkpoint is from iteration %d, want 4", checkpoint.Iteration)
+	}
+
+	// Resuming a finished search only restores the best model
+	restored := NewBlueprint()
+	if err := restored.ResumeNAS(dir, sessions, cfg); err != nil {
+		t.Fatal(err)
+	}
+	if restored.Hash() != bp.Hash() {
+		t.Fatal("resumed blueprint differs from the checkpointed model")
+	}
+
+	iterations, improved := []int{}, false
+	cfg.MaxIterations = 8
+	cfg.OnIteration = func(iteration int, metrics NASMetrics) error {
+		iterations = append(iterations, iteration)
+		improved = improved || metrics.Improved
+		return nil
+	}
+	resumed := NewBlueprint()
+	if err := resumed.ResumeNAS(dir, sessions, cfg); err != nil {
+		t.Fatal(err)
+	}
+	if len(iterations) != 4 || iterations[0] != 5 || iterations[3] != 8 {
+		t.Errorf("resumed search ran iterations %v, want 5 to 8", iterations)
+	}
+	if !improved && resumed.Hash() != bp.Hash() {
+		t.Error("resumed search replaced the checkpointed model without finding an improvement")
+	}
+	if checkpoint, err := LoadNASCheckpoint(dir); err != nil || checkpoint.Iteration != 8 {
+		t.Errorf("resumed search left a checkpoint from iteration %d (%v), want 8", checkpoint.Iteration, err)
+	}
+}
+
+func TestTrainingStopsPromptlyWhenCancelled(t *testing.T) {
+	sessions := xorSessions()
+	trainers := map[string]func(ctx context.Context, bp *Blueprint) error{
+		"ParallelSimpleNAS": func(ctx context.Context, bp *Blueprint) error {
+			return bp.ParallelSimpleNASWithContext(ctx, sessions, math.MaxInt32, []string{"dense", "rnn", "lstm"}, 5, true, false, "")
+		},
+		"EvolutionaryTrain": func(ctx context.Context, bp *Blueprint) error {
+			return bp.EvolutionaryTrainWithContext(ctx, sessions, 6, math.MaxInt32)
+		},
+	}
+	for name, train := range trainers {
+		t.Run(name, func(t *testing.T) {
+			randomSource.Seed(1)
+			bp := NewDenseMLP([]int{2, 3, 2}, "relu")
+			logger := &captureLogger{}
+			bp.Logger = logger
+			initial := bp.Hash()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+			start := time.Now()
+			err := train(ctx, bp)
+			if elapsed := time.Since(start); elapsed > 5*time.Second {
+				t.Errorf("returned %v after the deadline", elapsed)
+			}
+			if !errors.Is(err, context.DeadlineExceeded) {
+				t.Errorf("returned %v, want %v", err, context.DeadlineExceeded)
+			}
+			if errs := bp.Validate(); len(errs) > 0 {
+				t.Fatalf("best blueprint is invalid: %v", errs)
+			}
+			result := bp.Evaluate(sessions)
+			if math.IsNaN(result.GenerousAccuracy) || len(bp.Predict(sessions[0].InputVariables, 1)) != 2 {
+				t.Errorf("best blueprint does not run: %+v", result)
+			}
+			// Evaluate starts from the values left by earlier runs, so the kept model is checked against the log
+			// rather than by scoring it again
+			if name == "ParallelSimpleNAS" && bp.Hash() != initial && logger.count("Improved model found!") == 0 {
+				t.Error("the blueprint was replaced without an improved model being found")
+			}
+
+			// A search cancelled before it starts leaves the blueprint as it was
+			before := bp.Hash()
+			cancelled, cancelNow := context.WithCancel(context.Background())
+			cancelNow()
+			if err := train(cancelled, bp); !errors.Is(err, context.Canceled) {
+				t.Errorf("with a cancelled context returned %v, want %v", err, context.Canceled)
+			}
+			if bp.Hash() != before {
+				t.Error("a cancelled run changed the blueprint")
+			}
+		})
+	}
+}
+
+// neuronJSON returns the JSON of a neuron without its runtime state, which running the network changes.
+func neuronJSON(t *testing.T, neuron *Neuron) string {
+	t.Helper()
+	c := neuron.copy()
+	c.Value, c.CellState, c.NCAState = 0, 0, nil
+	data, err := json.Marshal(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestNASLeavesNeuronsOutsideMutableRegionUnchanged(t *testing.T) {
+	randomSource.Seed(4)
+	bp := NewDenseMLP([]int{2, 3, 2}, "relu") // Inputs 1-2, hidden 3-5, outputs 6-7
+	before := make(map[int]string)
+	for id, neuron := range bp.Neurons {
+		before[id] = neuronJSON(t, neuron)
+	}
+	mutable := map[int]bool{4: true, 6: true}
+
+	err := bp.SimpleNASWithContext(context.Background(), xorSessions(), NASConfig{
+		MaxIterations:          10,
+		NeuronTypes:            []string{"dense"},
+		WeightUpdateIterations: 20,
+		UseHillClimbing:        true,
+		MutableNeuronIDs:       []int{4, 6},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	changed := len(bp.Neurons) > len(before)
+	for id, want := range before {
+		neuron, exists := bp.Neurons[id]
+		if !exists {
+			t.Errorf("neuron %d was removed", id)
+			continue
+		}
+		got := neuronJSON(t, neuron)
+		if mutable[id] {
+			changed = changed || got != want
+		} else if got != want {
+			t.Errorf("neuron %d outside the mutable region changed:\nbefore %s\nafter  %s", id, want, got)
+		}
+	}
+	if !changed {
+		t.Error("NAS changed nothing, not even inside the mutable region")
+	}
+}
@@ -1,19 +1,22 @@
 package blueprint
 
 import (
-	"fmt"
+	"encoding/json"
 	"math"
-	"math/rand"
 )
 
 // BatchNormParams holds parameters for batch normalization
 type BatchNormParams struct {
-	Gamma float64 `json:"gamma"`
-	Beta  float64 `json:"beta"`
-	Mean  float64 `json:"mean"`
-	Var   float64 `json:"var"`
+	Gamma    float64 `json:"gamma"`
+	Beta     float64 `json:"beta"`
+	Mean     float64 `json:"mean"`               // Running mean
+	Var      float64 `json:"var"`                // Running variance
+	Momentum float64 `json:"momentum,omitempty"` // Weight of the old running statistics, 0 uses defaultBatchNormMomentum
 }
 
+// defaultBatchNormMomentum is the running statistics momentum of batch_norm neurons that do not set one.
+const defaultBatchNormMomentum = 0.9
+
 // Neuron represents a single neuron in the network
 type Neuron struct {
 	ID               int              `json:"id"`
@@ -23,6 +26,7 @@ type Neuron struct {
 	Connections      [][]float64      `json:"connections"`       // [source_id, weight]
 	Activation       string           `json:"activation"`        // Activation function
 	LoopCount        int              `json:"loop_count"`        // For RNN/LSTM loops
+	RecurrentWeight  float64          `json:"recurrent_weight"`  // For RNN, weight of the previous value; 0 means unset and counts as 1.0
 	WindowSize       int              `json:"window_size"`       // For CNN
 	DropoutRate      float64          `json:"dropout_rate"`      // For Dropout
 	BatchNorm        bool             `json:"batch_norm"`        // Apply batch normalization
@@ -30,14 +34,35 @@ type Neuron struct {
 	Attention        bool             `json:"attention"`         // Apply attention mechanism
 	AttentionWeights []float64        `json:"attention_weights"` // Weights for Attention
 	Kernels          [][]float64      `json:"kernels"`           // Multiple kernels for CNN neurons
-	// Additional fields for LSTM
-	CellState   float64              // For LSTM cell state
-	GateWeights map[string][]float64 // Weights for LSTM gates
+	Frozen           bool             `json:"frozen,omitempty"`  // Weights and bias are left unchanged by training, see FreezeNeurons
+	// Convolution layout of CNN neurons and pooling windows of maxpool and avgpool neurons, see cnnValue and poolValue
+	InputShape  [2]int `json:"input_shape"`         // Rows and columns of 2D inputs, read row by row in connection order; zero for 1D
+	KernelShape [2]int `json:"kernel_shape"`        // Rows and columns of each 2D kernel, stored row by row; zero for square kernels
+	Stride      int    `json:"stride,omitempty"`    // Step between kernel positions along each axis, 0 uses 1; for pooling see poolValue
+	Padding     string `json:"padding,omitempty"`   // "valid" (default) or "same", see convolve
+	PoolSize    int    `json:"pool_size,omitempty"` // Inputs per window of maxpool and avgpool neurons, 0 pools all inputs at once
+	// Additional fields for LSTM, serialized under their field names
+	CellState   float64              `json:"CellState"`   // For LSTM cell state
+	GateWeights map[string][]float64 `json:"GateWeights"` // Weights for LSTM gates
 
 	// Fields for NCA Neurons
 	NeighborhoodIDs []int     `json:"neighborhood"` // IDs of neighboring neurons (for NCA)
 	UpdateRules     string    `json:"update_rules"` // Rules for updating (e.g., Sum, Average)
 	NCAState        []float64 `json:"nca_state"`    // Internal state for NCA neurons
+
+	// Float32 connection storage used in low-precision mode, see ConvertToFloat32Storage
+	Connections32 [][2]float32 `json:"connections32,omitempty"` // [source_id, weight]
+}
+
+// UnmarshalJSON decodes a neuron, defaulting RecurrentWeight to 1.0 for models saved before it existed.
+func (neuron *Neuron) UnmarshalJSON(data []byte) error {
+	type plainNeuron Neuron
+	decoded := plainNeuron{RecurrentWeight: 1.0}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	*neuron = Neuron(decoded)
+	return nil
 }
 
 // ProcessNeuron processes a single neuron based on its type
@@ -56,15 +81,19 @@ func (bp *Blueprint) ProcessNeuron(neuron *Neuron, inputs []float64, timestep in
 		bp.ProcessLSTMNeuron(neuron, inputs)
 	case "cnn":
 		bp.ProcessCNNNeuron(neuron, inputs)
+	case "maxpool", "avgpool":
+		bp.ProcessPoolNeuron(neuron, inputs)
 	case "dropout":
+		// Pass the weighted inputs through like a dense neuron, then drop the result
+		bp.ProcessDenseNeuron(neuron, inputs)
 		bp.ApplyDropout(neuron)
 	case "batch_norm":
-		bp.ApplyBatchNormalization(neuron, 0.0, 1.0) // Example mean/variance
+		// Pass the weighted inputs through like a dense neuron, then normalize the result
+		bp.ProcessDenseNeuron(neuron, inputs)
+		bp.ApplyBatchNormalization(neuron)
 	case "attention":
 		// Handled separately in Forward method
-		if bp.Debug {
-			fmt.Printf("Attention Neuron %d processed\n", neuron.ID)
-		}
+		bp.debugf("Attention Neuron %d processed", neuron.ID)
 	default:
 		// Default dense neuron behavior
 		bp.ProcessDenseNeuron(neuron, inputs)
@@ -73,33 +102,54 @@ func (bp *Blueprint) ProcessNeuron(neuron *Neuron, inputs []float64, timestep in
 
 // ProcessDenseNeuron handles standard dense neuron computation
 func (bp *Blueprint) ProcessDenseNeuron(neuron *Neuron, inputs []float64) {
+	neuron.Value = bp.denseValue(neuron, inputs)
+	bp.debugf("Dense Neuron %d: Value=%f", neuron.ID, neuron.Value)
+}
+
+// denseValue returns the activated sum of the weighted inputs and the bias.
+func (bp *Blueprint) denseValue(neuron *Neuron, inputs []float64) float64 {
 	sum := neuron.Bias
 	for _, input := range inputs {
 		sum += input
 	}
-	neuron.Value = bp.ApplyScalarActivation(sum, neuron.Activation)
-	if bp.Debug {
-		fmt.Printf("Dense Neuron %d: Value=%f\n", neuron.ID, neuron.Value)
-	}
+	return bp.ApplyScalarActivation(sum, neuron.Activation)
 }
 
 // ProcessRNNNeuron updates an RNN neuron over multiple time steps
 func (bp *Blueprint) ProcessRNNNeuron(neuron *Neuron, inputs []float64) {
+	neuron.Value = bp.rnnValue(neuron, inputs, neuron.Value)
+	bp.debugf("RNN Neuron %d: Value=%f", neuron.ID, neuron.Value)
+}
+
+// rnnValue returns the next value of an RNN neuron whose previous value is previous.
+func (bp *Blueprint) rnnValue(neuron *Neuron, inputs []float64, previous float64) float64 {
 	// Simple RNN implementation with separate weight for previous value
 	sum := neuron.Bias
 	for _, input := range inputs {
 		sum += input // Already includes weights from connections
 	}
-	// Add weighted previous value (assuming weight of 1.0 for simplicity)
-	sum += neuron.Value * 1.0
-	neuron.Value = bp.ApplyScalarActivation(sum, neuron.Activation)
-	if bp.Debug {
-		fmt.Printf("RNN Neuron %d: Value=%f\n", neuron.ID, neuron.Value)
+	// Add weighted previous value
+	sum += previous * neuron.recurrentWeight()
+	return bp.ApplyScalarActivation(sum, neuron.Activation)
+}
+
+// recurrentWeight returns the RNN weight of the previous value. A RecurrentWeight of 0, as left by gob decoding
+// of models saved before the field existed or by a Neuron literal without it, counts as the default 1.0.
+func (neuron *Neuron) recurrentWeight() float64 {
+	if neuron.RecurrentWeight == 0 {
+		return 1.0
 	}
+	return neuron.RecurrentWeight
 }
 
 // ProcessLSTMNeuron updates an LSTM neuron with gating
 func (bp *Blueprint) ProcessLSTMNeuron(neuron *Neuron, inputs []float64) {
+	neuron.Value, neuron.CellState = lstmValue(neuron, inputs, neuron.CellState)
+	bp.debugf("LSTM Neuron %d: Value=%f, CellState=%f", neuron.ID, neuron.Value, neuron.CellState)
+}
+
+// lstmValue returns the next value and cell state of an LSTM neuron whose previous cell state is cellState.
+func lstmValue(neuron *Neuron, inputs []float64, cellState float64) (value, nextCellState float64) {
 	// Standard LSTM cell implementation with weights
 	var (
 		inputGate  float64
@@ -125,55 +175,63 @@ func (bp *Blueprint) ProcessLSTMNeuron(neuron *Neuron, inputs []float64) {
 	cellInput = Tanh(cellInput + neuron.Bias)
 
 	// Update cell state and output
-	neuron.CellState = neuron.CellState*forgetGate + cellInput*inputGate
-	neuron.Value = Tanh(neuron.CellState) * outputGate
-	if bp.Debug {
-		fmt.Printf("LSTM Neuron %d: Value=%f, CellState=%f\n", neuron.ID, neuron.Value, neuron.CellState)
-	}
+	nextCellState = cellState*forgetGate + cellInput*inputGate
+	return Tanh(nextCellState) * outputGate, nextCellState
 }
 
 // ProcessCNNNeuron applies convolutional behavior using the neuron's predefined kernels
 func (bp *Blueprint) ProcessCNNNeuron(neuron *Neuron, inputs []float64) {
+	neuron.Value = bp.cnnValue(neuron, inputs)
+	bp.debugf("CNN Neuron %d: Aggregated Value=%f", neuron.ID, neuron.Value)
+}
+
+// cnnValue returns the mean of the activated convolutions of the inputs with every kernel of a CNN neuron,
+// or 0 when no kernel fits the inputs. Without an InputShape the inputs and kernels are 1D; with one, the first
+// rows × columns inputs form a 2D image and each kernel is a KernelShape matrix stored row by row.
+func (bp *Blueprint) cnnValue(neuron *Neuron, inputs []float64) float64 {
 	if len(neuron.Kernels) == 0 {
-		if bp.Debug {
-			fmt.Printf("CNN Neuron %d: No kernels defined. Setting value to 0.\n", neuron.ID)
+		bp.debugf("CNN Neuron %d: No kernels defined. Setting value to 0.", neuron.ID)
+		return 0.0
+	}
+
+	inputShape := [2]int{1, len(inputs)}
+	is2D := neuron.InputShape[0] > 0 && neuron.InputShape[1] > 0
+	if is2D {
+		inputShape = neuron.InputShape
+		if len(inputs) < inputShape[0]*inputShape[1] {
+			bp.debugf("CNN Neuron %d: Input shape %dx%d needs %d inputs, got %d. Setting value to 0.",
+				neuron.ID, inputShape[0], inputShape[1], inputShape[0]*inputShape[1], len(inputs))
+			return 0.0
 		}
-		neuron.Value = 0.0
-		return
+		inputs = inputs[:inputShape[0]*inputShape[1]]
 	}
 
 	// Iterate over each kernel assigned to the neuron
 	convolutionOutputs := []float64{}
 	for k, kernel := range neuron.Kernels {
-		kernelSize := len(kernel)
-		if len(inputs) < kernelSize {
-			if bp.Debug {
-				fmt.Printf("CNN Neuron %d: Skipping kernel %d due to insufficient inputs (required: %d, got: %d)\n", neuron.ID, k, kernelSize, len(inputs))
-			}
+		kernelShape, ok := neuron.kernelShape(kernel, is2D)
+		if !ok {
+			bp.debugf("CNN Neuron %d: Skipping kernel %d, whose %d weights do not fit its shape", neuron.ID, k, len(kernel))
 			continue
 		}
 
 		// Perform convolution for the current kernel
-		for i := 0; i <= len(inputs)-kernelSize; i++ {
-			sum := neuron.Bias
-			for j := 0; j < kernelSize; j++ {
-				sum += inputs[i+j] * kernel[j]
-			}
+		sums := convolve(inputs, inputShape, kernel, kernelShape, neuron.Bias, max(neuron.Stride, 1), neuron.Padding == "same")
+		if len(sums) == 0 {
+			bp.debugf("CNN Neuron %d: Skipping kernel %d due to insufficient inputs (required: %d, got: %d)", neuron.ID, k, len(kernel), len(inputs))
+			continue
+		}
+		for i, sum := range sums {
 			activatedValue := bp.ApplyScalarActivation(sum, neuron.Activation)
 			convolutionOutputs = append(convolutionOutputs, activatedValue)
-			if bp.Debug {
-				fmt.Printf("CNN Neuron %d: Kernel %d Output[%d]=%f\n", neuron.ID, k, i, activatedValue)
-			}
+			bp.debugf("CNN Neuron %d: Kernel %d Output[%d]=%f", neuron.ID, k, i, activatedValue)
 		}
 	}
 
 	// Handle cases where no valid convolution outputs were generated
 	if len(convolutionOutputs) == 0 {
-		if bp.Debug {
-			fmt.Printf("CNN Neuron %d: No valid convolution outputs. Setting value to 0.\n", neuron.ID)
-		}
-		neuron.Value = 0.0
-		return
+		bp.debugf("CNN Neuron %d: No valid convolution outputs. Setting value to 0.", neuron.ID)
+		return 0.0
 	}
 
 	// Aggregate the convolution outputs (e.g., by taking the mean)
@@ -181,39 +239,101 @@ func (bp *Blueprint) ProcessCNNNeuron(neuron *Neuron, inputs []float64) {
 	for _, v := range convolutionOutputs {
 		aggregate += v
 	}
-	neuron.Value = aggregate / float64(len(convolutionOutputs))
-	if bp.Debug {
-		fmt.Printf("CNN Neuron %d: Aggregated Value=%f\n", neuron.ID, neuron.Value)
-	}
+	return aggregate / float64(len(convolutionOutputs))
 }
 
-// ApplyDropout randomly zeroes out a neuron's value
-func (bp *Blueprint) ApplyDropout(neuron *Neuron) {
-	if rand.Float64() < neuron.DropoutRate {
-		neuron.Value = 0
-		if bp.Debug {
-			fmt.Printf("Dropout Neuron %d: Value set to 0\n", neuron.ID)
+// ProcessPoolNeuron reduces the inputs of a maxpool or avgpool neuron to a single value
+func (bp *Blueprint) ProcessPoolNeuron(neuron *Neuron, inputs []float64) {
+	neuron.Value = poolValue(neuron, inputs)
+	bp.debugf("Pool Neuron %d: Value=%f", neuron.ID, neuron.Value)
+}
+
+// poolValue slides a window of PoolSize inputs over the inputs, Stride apart, and returns the mean of the
+// windows' maxima for a maxpool neuron or of their means for an avgpool neuron. A PoolSize of 0 or beyond the
+// number of inputs uses one window over all of them, and a Stride of 0 uses PoolSize, so windows do not
+// overlap. A trailing window shorter than PoolSize is pooled over the inputs it covers. Pooling has no
+// weights of its own, so the bias and activation are not applied; it returns 0 without inputs.
+func poolValue(neuron *Neuron, inputs []float64) float64 {
+	if len(inputs) == 0 {
+		return 0.0
+	}
+	size := neuron.PoolSize
+	if size <= 0 || size > len(inputs) {
+		size = len(inputs)
+	}
+	stride := neuron.Stride
+	if stride <= 0 {
+		stride = size
+	}
+
+	total, windows := 0.0, 0
+	for start := 0; start < len(inputs); start += stride {
+		window := inputs[start:min(start+size, len(inputs))]
+		pooled := window[0]
+		if neuron.Type == "maxpool" {
+			for _, v := range window[1:] {
+				pooled = math.Max(pooled, v)
+			}
+		} else {
+			for _, v := range window[1:] {
+				pooled += v
+			}
+			pooled /= float64(len(window))
 		}
-	} else {
-		if bp.Debug {
-			fmt.Printf("Dropout Neuron %d: Value retained as %f\n", neuron.ID, neuron.Value)
+		total += pooled
+		windows++
+		if start+size >= len(inputs) {
+			break
 		}
 	}
+	return total / float64(windows)
 }
 
-// ApplyBatchNormalization normalizes the neuron's value
-func (bp *Blueprint) ApplyBatchNormalization(neuron *Neuron, mean, variance float64) {
-	if neuron.BatchNormParams == nil {
-		if bp.Debug {
-			fmt.Printf("BatchNorm Neuron %d: BatchNormParams not initialized. Skipping normalization.\n", neuron.ID)
-		}
+// ApplyDropout randomly zeroes out a neuron's value in training mode. Outside training mode the value is
+// scaled by 1 - DropoutRate instead, its expected value under dropout, so inference is deterministic.
+func (bp *Blueprint) ApplyDropout(neuron *Neuron) {
+	neuron.Value = bp.dropoutValue(neuron, neuron.Value)
+	bp.debugf("Dropout Neuron %d: Value=%f", neuron.ID, neuron.Value)
+}
+
+// dropoutValue returns value after dropout: 0 with probability DropoutRate in training mode, otherwise
+// value itself, or value scaled by 1 - DropoutRate outside training mode.
+func (bp *Blueprint) dropoutValue(neuron *Neuron, value float64) float64 {
+	if !bp.TrainingMode {
+		return value * (1 - neuron.DropoutRate)
+	}
+	if random.Float64() < neuron.DropoutRate {
+		return 0
+	}
+	return value
+}
+
+// ApplyBatchNormalization normalizes the neuron's value with the running mean and variance in its
+// BatchNormParams, then scales it by Gamma and shifts it by Beta. In training mode the running statistics
+// are first updated with the value as exponential moving averages, keeping Momentum of the old statistics.
+func (bp *Blueprint) ApplyBatchNormalization(neuron *Neuron) {
+	params := neuron.BatchNormParams
+	if params == nil {
+		bp.debugf("BatchNorm Neuron %d: BatchNormParams not initialized. Skipping normalization.", neuron.ID)
 		return
 	}
-	neuron.Value = (neuron.Value - neuron.BatchNormParams.Mean) / math.Sqrt(neuron.BatchNormParams.Var+1e-7)
-	neuron.Value = neuron.Value*neuron.BatchNormParams.Gamma + neuron.BatchNormParams.Beta
-	if bp.Debug {
-		fmt.Printf("BatchNorm Neuron %d: Normalized Value=%f\n", neuron.ID, neuron.Value)
+	if bp.TrainingMode {
+		momentum := params.Momentum
+		if momentum <= 0 || momentum >= 1 {
+			momentum = defaultBatchNormMomentum
+		}
+		delta := neuron.Value - params.Mean
+		params.Mean += (1 - momentum) * delta
+		params.Var = momentum * (params.Var + (1-momentum)*delta*delta)
 	}
+	neuron.Value = batchNormValue(params, neuron.Value)
+	bp.debugf("BatchNorm Neuron %d: Normalized Value=%f", neuron.ID, neuron.Value)
+}
+
+// batchNormValue normalizes value with the running statistics of params and applies Gamma and Beta.
+func batchNormValue(params *BatchNormParams, value float64) float64 {
+	value = (value - params.Mean) / math.Sqrt(params.Var+1e-7)
+	return value*params.Gamma + params.Beta
 }
 
 // ApplyAttention adjusts neuron values based on attention weights
@@ -224,9 +344,7 @@ func (bp *Blueprint) ApplyAttention(neuron *Neuron, inputs []float64, attentionW
 		sum += input * attentionWeights[i]
 	}
 	neuron.Value = bp.ApplyScalarActivation(sum, neuron.Activation)
-	if bp.Debug {
-		fmt.Printf("Attention Neuron %d: Value=%f\n", neuron.ID, neuron.Value)
-	}
+	bp.debugf("Attention Neuron %d: Value=%f", neuron.ID, neuron.Value)
 }
 
 // ComputeAttentionWeights computes attention weights for the given inputs
@@ -243,18 +361,23 @@ func (bp *Blueprint) ComputeAttentionWeights(neuron *Neuron, inputs []float64) [
 
 	// Apply softmax to get weights
 	attentionWeights := Softmax(scores)
-	if bp.Debug {
-		fmt.Printf("Attention Neuron %d: Weights=%v\n", neuron.ID, attentionWeights)
-	}
+	bp.debugf("Attention Neuron %d: Weights=%v", neuron.ID, attentionWeights)
 	return attentionWeights
 }
 
-// ApplySoftmax applies the Softmax function to all output neurons collectively
+// ApplySoftmax applies the Softmax function to all output neurons collectively. Quantum output neurons take
+// part with their measured value; their softmaxed value is kept as their output, see GetOutputs, so the
+// measurement classical neurons read stays untouched. Output nodes without a neuron are skipped.
 func (bp *Blueprint) ApplySoftmax() {
+	outputIDs := []int{}
 	outputValues := []float64{}
 	for _, id := range bp.OutputNodes {
 		if neuron, exists := bp.Neurons[id]; exists {
+			outputIDs = append(outputIDs, id)
 			outputValues = append(outputValues, neuron.Value)
+		} else if quantumNeuron, exists := bp.QuantumNeurons[id]; exists {
+			outputIDs = append(outputIDs, id)
+			outputValues = append(outputValues, quantumNeuron.measuredValue())
 		}
 	}
 
@@ -262,23 +385,35 @@ func (bp *Blueprint) ApplySoftmax() {
 	softmaxValues := Softmax(outputValues)
 
 	// Assign the Softmaxed values back to the output neurons
-	for i, id := range bp.OutputNodes {
+	for i, id := range outputIDs {
 		if neuron, exists := bp.Neurons[id]; exists {
 			neuron.Value = softmaxValues[i]
-			if bp.Debug {
-				fmt.Printf("Softmax Applied to Neuron %d: Value=%f\n", id, neuron.Value)
-			}
+			bp.debugf("Softmax Applied to Neuron %d: Value=%f", id, neuron.Value)
+		} else {
+			bp.QuantumNeurons[id].output = softmaxValues[i]
+			bp.debugf("Softmax Applied to Quantum Neuron %d: Value=%f", id, softmaxValues[i])
 		}
 	}
 }
 
 // ProcessNCANeuron processes an NCA neuron based on its neighborhood and update rules
 func (bp *Blueprint) ProcessNCANeuron(neuron *Neuron) {
+	value, ok := bp.ncaValue(neuron, func(neighbor *Neuron) float64 { return neighbor.Value })
+	if !ok {
+		return
+	}
+	neuron.Value = value
+	bp.debugf("NCA Neuron %d: Value=%f", neuron.ID, neuron.Value)
+}
+
+// ncaValue returns the next value of an NCA neuron, reading each neighbor's value through valueOf. It
+// reports false, leaving the value unchanged, when the update rule is unknown.
+func (bp *Blueprint) ncaValue(neuron *Neuron, valueOf func(neighbor *Neuron) float64) (float64, bool) {
 	// Gather values from neighboring neurons
 	neighborValues := []float64{}
 	for _, neighborID := range neuron.NeighborhoodIDs {
 		if neighbor, exists := bp.Neurons[neighborID]; exists {
-			neighborValues = append(neighborValues, neighbor.Value)
+			neighborValues = append(neighborValues, valueOf(neighbor))
 		}
 	}
 
@@ -298,24 +433,19 @@ func (bp *Blueprint) ProcessNCANeuron(neuron *Neuron) {
 			newValue = sum / float64(len(neighborValues))
 		}
 	default:
-		if bp.Debug {
-			fmt.Printf("Unknown update rule for NCA Neuron %d\n", neuron.ID)
-		}
-		return
+		bp.debugf("Unknown update rule for NCA Neuron %d", neuron.ID)
+		return 0, false
 	}
 
 	// Apply activation function
-	neuron.Value = bp.ApplyScalarActivation(newValue+neuron.Bias, neuron.Activation)
-	if bp.Debug {
-		fmt.Printf("NCA Neuron %d: Value=%f\n", neuron.ID, neuron.Value)
-	}
+	return bp.ApplyScalarActivation(newValue+neuron.Bias, neuron.Activation), true
 }
 
 // InitializeKernel initializes a kernel with random weights
 func (bp *Blueprint) InitializeKernel(kernelSize int) []float64 {
 	kernel := make([]float64, kernelSize)
 	for i := range kernel {
-		kernel[i] = rand.Float64() // Initialize with random weights between 0 and 1
+		kernel[i] = random.Float64() // Initialize with random weights between 0 and 1
 	}
 	return kernel
 }
@@ -345,7 +475,7 @@ func (bp *Blueprint) sum(a []float64) float64 {
 // sqrt computes the square root, handling negative inputs
 func (bp *Blueprint) sqrt(a float64) float64 {
 	if a < 0 {
-		fmt.Printf("Warning: sqrt received negative value %f. Returning 0.\n", a)
+		bp.warnf("Warning: sqrt received negative value %f. Returning 0.", a)
 		return 0.0
 	}
 	return math.Sqrt(a)
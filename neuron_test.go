@@ -0,0 +1,152 @@
+package blueprint
+
+import (
+	"math"
+	"testing"
+)
+
+// rnnValueAfter runs a linear RNN hidden neuron fed by input 1 for the given timesteps and returns its value.
+func rnnValueAfter(recurrentWeight float64, timesteps int) float64 {
+	bp := NewBlueprint()
+	bp.AddInputNeurons([]int{1})
+	bp.Neurons[2] = &Neuron{ID: 2, Type: "rnn", Activation: "linear", RecurrentWeight: recurrentWeight,
+		Connections: [][]float64{{1, 1}}}
+	bp.AddOutputNeurons([]int{3}, "linear")
+	bp.Neurons[3].Connections = [][]float64{{2, 1}}
+	bp.RunNetwork(map[int]float64{1: 1}, timesteps)
+	return bp.Neurons[2].Value
+}
+
+func TestRecurrentWeightChangesRNNOutput(t *testing.T) {
+	// With input 1 the value follows v = 1 + w*v: 1, 1+w, 1+w+w²
+	for _, tc := range []struct{ weight, want float64 }{
+		{1, 3},
+		{0.5, 1.75},
+		{-1, 1},
+		{0, 3}, // Unset, as after gob decoding, counts as 1
+	} {
+		if got := rnnValueAfter(tc.weight, 3); math.Abs(got-tc.want) > 1e-12 {
+			t.Errorf("recurrent weight %v gave %v after 3 timesteps, want %v", tc.weight, got, tc.want)
+		}
+	}
+	if rnnValueAfter(0.5, 1) != rnnValueAfter(2, 1) {
+		t.Error("the recurrent weight changed the first timestep, which has no previous value")
+	}
+}
+
+func TestDropoutIsDeterministicInEvalMode(t *testing.T) {
+	randomSource.Seed(9)
+	bp := NewBlueprint()
+	bp.AddInputNeurons([]int{1, 2})
+	bp.Neurons[3] = &Neuron{ID: 3, Type: "dropout", Activation: "linear", DropoutRate: 0.5,
+		Connections: [][]float64{{1, 1}, {2, 2}}}
+	bp.AddOutputNeurons([]int{4, 5}, "linear")
+	bp.Neurons[4].Connections = [][]float64{{3, 1}}
+	bp.Neurons[5].Connections = [][]float64{{3, -1}}
+	inputs := map[int]float64{1: 1, 2: 1}
+
+	bp.RunNetwork(inputs, 1)
+	first := bp.GetOutputs()
+	if got := bp.Neurons[3].Value; got != 1.5 {
+		t.Errorf("dropout neuron has value %v in eval mode, want the sum 3 scaled by 0.5", got)
+	}
+	bp.RunNetwork(inputs, 1)
+	for id, value := range bp.GetOutputs() {
+		if value != first[id] {
+			t.Errorf("output %d changed from %v to %v between eval-mode runs", id, first[id], value)
+		}
+	}
+
+	// Evaluation switches to eval mode and restores training mode afterwards
+	bp.TrainingMode = true
+	sessions := []Session{{InputVariables: inputs, ExpectedOutput: map[int]float64{4: 1, 5: 0}, Timesteps: 1}}
+	firstResult := bp.Evaluate(sessions)
+	for i := 0; i < 10; i++ {
+		ClearEvaluationCache()
+		if result := bp.Evaluate(sessions); result != firstResult {
+			t.Fatalf("evaluation changed from %+v to %+v", firstResult, result)
+		}
+	}
+	if !bp.TrainingMode {
+		t.Error("evaluation left training mode off")
+	}
+}
+
+func TestBatchNormNormalizesKnownDistribution(t *testing.T) {
+	randomSource.Seed(4)
+	const mean, stdDev = 5.0, 2.0
+	bp := NewBlueprint()
+	neuron := &Neuron{ID: 1, Type: "batch_norm", BatchNorm: true,
+		BatchNormParams: &BatchNormParams{Gamma: 1, Beta: 0, Mean: 0, Var: 1, Momentum: 0.99}}
+
+	// Accumulate the running statistics in training mode
+	bp.TrainingMode = true
+	for i := 0; i < 20000; i++ {
+		neuron.Value = mean + stdDev*random.NormFloat64()
+		bp.ApplyBatchNormalization(neuron)
+	}
+	if math.Abs(neuron.BatchNormParams.Mean-mean) > 0.3 || math.Abs(neuron.BatchNormParams.Var-stdDev*stdDev) > 0.8 {
+		t.Fatalf("running statistics are mean %v and variance %v, want about %v and %v",
+			neuron.BatchNormParams.Mean, neuron.BatchNormParams.Var, mean, stdDev*stdDev)
+	}
+
+	// Normalize fresh samples with the frozen statistics
+	bp.TrainingMode = false
+	running := *neuron.BatchNormParams
+	const n = 20000
+	sum, sumSquares := 0.0, 0.0
+	for i := 0; i < n; i++ {
+		neuron.Value = mean + stdDev*random.NormFloat64()
+		bp.ApplyBatchNormalization(neuron)
+		sum += neuron.Value
+		sumSquares += neuron.Value * neuron.Value
+	}
+	if *neuron.BatchNormParams != running {
+		t.Error("eval mode changed the running statistics")
+	}
+	normalizedMean := sum / n
+	normalizedVar := sumSquares/n - normalizedMean*normalizedMean
+	if math.Abs(normalizedMean) > 0.15 || math.Abs(normalizedVar-1) > 0.25 {
+		t.Errorf("normalized outputs have mean %v and variance %v, want about 0 and 1", normalizedMean, normalizedVar)
+	}
+
+	// Gamma and Beta are applied after the normalization
+	neuron.BatchNormParams.Gamma, neuron.BatchNormParams.Beta = 2, 3
+	neuron.Value = running.Mean + math.Sqrt(running.Var+1e-7)
+	bp.ApplyBatchNormalization(neuron)
+	if math.Abs(neuron.Value-5) > 1e-9 {
+		t.Errorf("a value one standard deviation above the mean became %v, want 2*1+3", neuron.Value)
+	}
+}
+
+func TestMaxPoolOutputsWindowMaximum(t *testing.T) {
+	bp := NewBlueprint()
+	bp.AddInputNeurons([]int{1, 2, 3, 4})
+	pool, err := bp.createNeuron(5, "maxpool")
+	if err != nil {
+		t.Fatalf("createNeuron(maxpool) failed: %v", err)
+	}
+	pool.Value = 0
+	pool.PoolSize = 0 // One window over every input
+	pool.Connections = [][]float64{{1, 1}, {2, 1}, {3, 1}, {4, 1}}
+	bp.Neurons[5] = pool
+	inputs := map[int]float64{1: -3, 2: 7, 3: 2, 4: 5}
+
+	bp.Forward(inputs, 1)
+	if got := bp.Neurons[5].Value; got != 7 {
+		t.Errorf("maxpool value = %v, want the maximum 7", got)
+	}
+
+	// Windows of two average their maxima 7 and 5, and an avgpool neuron averages their means 2 and 3.5
+	for _, c := range []struct {
+		neuronType string
+		want       float64
+	}{{"maxpool", 6}, {"avgpool", 2.75}} {
+		pool.Type = c.neuronType
+		pool.PoolSize = 2
+		bp.Forward(inputs, 1)
+		if got := bp.Neurons[5].Value; got != c.want {
+			t.Errorf("%s value with pool size 2 = %v, want %v", c.neuronType, got, c.want)
+		}
+	}
+}
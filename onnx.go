@@ -0,0 +1,272 @@
+package blueprint
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+)
+
+const (
+	// onnxIRVersion and onnxOpsetVersion are the ONNX versions ExportONNX targets (IR 7 with opset 13, ONNX 1.8).
+	onnxIRVersion    = 7
+	onnxOpsetVersion = 13
+
+	onnxFloat = 1 // TensorProto.DataType FLOAT
+	onnxInt64 = 7 // TensorProto.DataType INT64
+	onnxAttrI = 2 // AttributeProto.AttributeType INT
+)
+
+// onnxActivationOps maps the activations ExportONNX supports to their ONNX operator. Linear has none.
+var onnxActivationOps = map[string]string{
+	"linear":  "",
+	"relu":    "Relu",
+	"sigmoid": "Sigmoid",
+	"tanh":    "Tanh",
+}
+
+// ExportONNX writes the network to path as an ONNX model (IR 7, opset 13) with one float input, "input", of
+// shape [batch, len(InputNodes)] in InputNodes order, and one output, "output", holding the softmaxed values of
+// the output nodes in OutputNodes order. Each layer of ComputeLayers becomes a Gemm followed by its activation
+// per activation used in the layer, and its results are concatenated to everything computed before, so neurons
+// may read from any earlier layer. The model computes the feed-forward pass of TrainBackprop, which matches
+// Forward once every neuron has been reached. Only dense neurons with linear, relu, sigmoid or tanh activations
+// can be exported; any other neuron, quantum neurons included, makes it return an error listing their IDs.
+func (bp *Blueprint) ExportONNX(path string) error {
+	layers, err := bp.ComputeLayers()
+	if err != nil {
+		return fmt.Errorf("ONNX export requires a feed-forward network: %w", err)
+	}
+	if len(bp.InputNodes) == 0 || len(bp.OutputNodes) == 0 {
+		return fmt.Errorf("ONNX export requires input and output nodes")
+	}
+
+	unsupported := []int{}
+	for id := range bp.QuantumNeurons {
+		unsupported = append(unsupported, id)
+	}
+	for _, id := range bp.getAllNeuronIDs() {
+		neuron := bp.Neurons[id]
+		if bp.isInputNode(id) {
+			continue
+		}
+		if _, ok := onnxActivationOps[onnxActivation(neuron)]; !ok || neuron.Type == "input" || !isDenseNeuronType(neuron.Type) ||
+			neuron.BatchNorm || neuron.Attention {
+			unsupported = append(unsupported, id)
+		}
+	}
+	for _, id := range bp.OutputNodes {
+		if _, exists := bp.Neurons[id]; !exists {
+			unsupported = append(unsupported, id)
+		}
+	}
+	if len(unsupported) > 0 {
+		sort.Ints(unsupported)
+		return fmt.Errorf("ONNX export supports dense neurons with linear, relu, sigmoid or tanh activations only, unsupported neurons: %v", unsupported)
+	}
+
+	graph := &protoWriter{}
+	graph.string(2, "blueprint")
+
+	// The state tensor holds every value computed so far; column maps a neuron ID to its column in it
+	column := make(map[int]int, len(bp.Neurons))
+	for i, id := range bp.InputNodes {
+		column[id] = i
+	}
+	state := "input"
+	for l := 1; l < len(layers); l++ {
+		groups := make(map[string][]int)
+		for _, id := range layers[l] {
+			if !bp.isInputNode(id) {
+				activation := onnxActivation(bp.Neurons[id])
+				groups[activation] = append(groups[activation], id)
+			}
+		}
+		activations := make([]string, 0, len(groups))
+		for activation := range groups {
+			activations = append(activations, activation)
+		}
+		sort.Strings(activations)
+
+		concatInputs := []string{state}
+		for _, activation := range activations {
+			ids := groups[activation]
+			prefix := fmt.Sprintf("layer%d_%s", l, activation)
+
+			// Weight rows follow the state columns, weight columns the neurons of the group
+			weights := make([]float64, len(column)*len(ids))
+			biases := make([]float64, len(ids))
+			for unit, id := range ids {
+				neuron := bp.Neurons[id]
+				biases[unit] = neuron.Bias
+				for i := 0; i < neuron.numConnections(); i++ {
+					sourceID, weight := neuron.connection(i)
+					if row, exists := column[sourceID]; exists {
+						weights[row*len(ids)+unit] += weight
+					}
+				}
+			}
+			graph.message(5, onnxFloatTensor(prefix+"_weights", []int64{int64(len(column)), int64(len(ids))}, weights))
+			graph.message(5, onnxFloatTensor(prefix+"_bias", []int64{int64(len(ids))}, biases))
+
+			// Nodes must be listed in topological order
+			op := onnxActivationOps[activation]
+			gemmOutput := prefix
+			if op != "" {
+				gemmOutput = prefix + "_gemm"
+			}
+			graph.message(1, onnxNode("Gemm", prefix+"_gemm", []string{state, prefix + "_weights", prefix + "_bias"}, gemmOutput, nil))
+			if op != "" {
+				graph.message(1, onnxNode(op, prefix, []string{gemmOutput}, prefix, nil))
+			}
+			concatInputs = append(concatInputs, prefix)
+		}
+		for _, activation := range activations {
+			for _, id := range groups[activation] {
+				column[id] = len(column)
+			}
+		}
+
+		next := fmt.Sprintf("state%d", l)
+		graph.message(1, onnxNode("Concat", next, concatInputs, next, map[string]int64{"axis": 1}))
+		state = next
+	}
+
+	// Pick the output columns and softmax them like Forward
+	indices := make([]int64, len(bp.OutputNodes))
+	for i, id := range bp.OutputNodes {
+		indices[i] = int64(column[id])
+	}
+	graph.message(5, onnxInt64Tensor("output_indices", []int64{int64(len(indices))}, indices))
+	graph.message(1, onnxNode("Gather", "logits", []string{state, "output_indices"}, "logits", map[string]int64{"axis": 1}))
+	graph.message(1, onnxNode("Softmax", "output", []string{"logits"}, "output", map[string]int64{"axis": 1}))
+	graph.message(11, onnxValueInfo("input", int64(len(bp.InputNodes))))
+	graph.message(12, onnxValueInfo("output", int64(len(bp.OutputNodes))))
+
+	model := &protoWriter{}
+	model.varint(1, onnxIRVersion)
+	model.string(2, "blueprint")
+	model.message(7, graph)
+	opset := &protoWriter{}
+	opset.varint(2, onnxOpsetVersion)
+	model.message(8, opset)
+
+	if err := os.WriteFile(path, model.buf, 0644); err != nil {
+		return fmt.Errorf("failed to write ONNX model to '%s': %w", path, err)
+	}
+	return nil
+}
+
+// onnxActivation returns the activation a neuron runs with, treating an empty name as linear.
+func onnxActivation(neuron *Neuron) string {
+	if neuron.Activation == "" {
+		return "linear"
+	}
+	return neuron.Activation
+}
+
+// onnxNode encodes a NodeProto, sorting the integer attributes by name.
+func onnxNode(opType, name string, inputs []string, output string, attributes map[string]int64) *protoWriter {
+	node := &protoWriter{}
+	for _, input := range inputs {
+		node.string(1, input)
+	}
+	node.string(2, output)
+	node.string(3, name)
+	node.string(4, opType)
+	names := make([]string, 0, len(attributes))
+	for attribute := range attributes {
+		names = append(names, attribute)
+	}
+	sort.Strings(names)
+	for _, attribute := range names {
+		a := &protoWriter{}
+		a.string(1, attribute)
+		a.varint(3, uint64(attributes[attribute]))
+		a.varint(20, onnxAttrI)
+		node.message(5, a)
+	}
+	return node
+}
+
+// onnxFloatTensor encodes a float32 TensorProto initializer.
+func onnxFloatTensor(name string, dims []int64, values []float64) *protoWriter {
+	raw := make([]byte, 4*len(values))
+	for i, v := range values {
+		binary.LittleEndian.PutUint32(raw[4*i:], math.Float32bits(float32(v)))
+	}
+	return onnxTensor(name, dims, onnxFloat, raw)
+}
+
+// onnxInt64Tensor encodes an int64 TensorProto initializer.
+func onnxInt64Tensor(name string, dims []int64, values []int64) *protoWriter {
+	raw := make([]byte, 8*len(values))
+	for i, v := range values {
+		binary.LittleEndian.PutUint64(raw[8*i:], uint64(v))
+	}
+	return onnxTensor(name, dims, onnxInt64, raw)
+}
+
+// onnxTensor encodes a TensorProto with its data in raw little-endian form.
+func onnxTensor(name string, dims []int64, dataType uint64, raw []byte) *protoWriter {
+	tensor := &protoWriter{}
+	for _, d := range dims {
+		tensor.varint(1, uint64(d))
+	}
+	tensor.varint(2, dataType)
+	tensor.string(8, name)
+	tensor.bytes(9, raw)
+	return tensor
+}
+
+// onnxValueInfo encodes a ValueInfoProto for a float tensor of shape [batch, width].
+func onnxValueInfo(name string, width int64) *protoWriter {
+	batch := &protoWriter{}
+	batch.string(2, "batch")
+	features := &protoWriter{}
+	features.varint(1, uint64(width))
+	shape := &protoWriter{}
+	shape.message(1, batch)
+	shape.message(1, features)
+
+	tensorType := &protoWriter{}
+	tensorType.varint(1, onnxFloat)
+	tensorType.message(2, shape)
+	typeProto := &protoWriter{}
+	typeProto.message(1, tensorType)
+
+	info := &protoWriter{}
+	info.string(1, name)
+	info.message(2, typeProto)
+	return info
+}
+
+// protoWriter appends protocol buffer fields in wire format, which is all ExportONNX needs to write ONNX files
+// without depending on generated protobuf code.
+type protoWriter struct {
+	buf []byte
+}
+
+func (w *protoWriter) tag(field int, wireType uint64) {
+	w.buf = binary.AppendUvarint(w.buf, uint64(field)<<3|wireType)
+}
+
+func (w *protoWriter) varint(field int, v uint64) {
+	w.tag(field, 0)
+	w.buf = binary.AppendUvarint(w.buf, v)
+}
+
+func (w *protoWriter) bytes(field int, b []byte) {
+	w.tag(field, 2)
+	w.buf = binary.AppendUvarint(w.buf, uint64(len(b)))
+	w.buf = append(w.buf, b...)
+}
+
+func (w *protoWriter) string(field int, s string) {
+	w.bytes(field, []byte(s))
+}
+
+func (w *protoWriter) message(field int, m *protoWriter) {
+	w.bytes(field, m.buf)
+}
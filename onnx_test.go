@@ -0,0 +1,172 @@
+package blueprint
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// protoField is one decoded protocol buffer field: a varint or a length-delimited payload.
+type protoField struct {
+	number  int
+	varint  uint64
+	payload []byte
+}
+
+// parseProto decodes every field of a protocol buffer message, failing on anything but complete varint and
+// length-delimited fields, the only wire types ExportONNX writes.
+func parseProto(b []byte) ([]protoField, error) {
+	fields := []protoField{}
+	for len(b) > 0 {
+		key, n := binary.Uvarint(b)
+		if n <= 0 {
+			return nil, fmt.Errorf("bad field key")
+		}
+		b = b[n:]
+		field := protoField{number: int(key >> 3)}
+		switch key & 7 {
+		case 0:
+			field.varint, n = binary.Uvarint(b)
+			if n <= 0 {
+				return nil, fmt.Errorf("bad varint in field %d", field.number)
+			}
+			b = b[n:]
+		case 2:
+			length, n := binary.Uvarint(b)
+			if n <= 0 || uint64(len(b)-n) < length {
+				return nil, fmt.Errorf("truncated field %d", field.number)
+			}
+			field.payload = b[n : n+int(length)]
+			b = b[n+int(length):]
+		default:
+			return nil, fmt.Errorf("unexpected wire type %d in field %d", key&7, field.number)
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// protoMessages parses the payloads of every field with the given number as messages.
+func protoMessages(t *testing.T, fields []protoField, number int) [][]protoField {
+	t.Helper()
+	messages := [][]protoField{}
+	for _, field := range fields {
+		if field.number == number {
+			message, err := parseProto(field.payload)
+			if err != nil {
+				t.Fatalf("field %d: %v", number, err)
+			}
+			messages = append(messages, message)
+		}
+	}
+	return messages
+}
+
+// protoStrings returns the payloads of every field with the given number as strings.
+func protoStrings(fields []protoField, number int) []string {
+	values := []string{}
+	for _, field := range fields {
+		if field.number == number {
+			values = append(values, string(field.payload))
+		}
+	}
+	return values
+}
+
+// valueInfoShape returns the name of a ValueInfoProto and its dimensions, symbolic ones by name.
+func valueInfoShape(t *testing.T, info []protoField) (string, []string) {
+	t.Helper()
+	dims := []string{}
+	for _, typeProto := range protoMessages(t, info, 2) {
+		for _, tensorType := range protoMessages(t, typeProto, 1) {
+			for _, shape := range protoMessages(t, tensorType, 2) {
+				for _, dim := range protoMessages(t, shape, 1) {
+					for _, field := range dim {
+						switch field.number {
+						case 1:
+							dims = append(dims, fmt.Sprint(field.varint))
+						case 2:
+							dims = append(dims, string(field.payload))
+						}
+					}
+				}
+			}
+		}
+	}
+	return strings.Join(protoStrings(info, 1), ""), dims
+}
+
+func TestExportONNXParsesBack(t *testing.T) {
+	randomSource.Seed(8)
+	bp := NewDenseMLP([]int{3, 4, 2}, "relu")
+	bp.Neurons[8].Activation = "sigmoid"
+	bp.Neurons[9].Activation = "linear"
+	path := filepath.Join(t.TempDir(), "model.onnx")
+	if err := bp.ExportONNX(path); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	model, err := parseProto(data)
+	if err != nil {
+		t.Fatalf("the model does not parse: %v", err)
+	}
+	if model[0].number != 1 || model[0].varint != onnxIRVersion {
+		t.Errorf("the model starts with field %d = %d, want ir_version %d", model[0].number, model[0].varint, onnxIRVersion)
+	}
+	graphs := protoMessages(t, model, 7)
+	if len(graphs) != 1 {
+		t.Fatalf("the model has %d graphs, want 1", len(graphs))
+	}
+	graph := graphs[0]
+
+	inputs := protoMessages(t, graph, 11)
+	outputs := protoMessages(t, graph, 12)
+	if len(inputs) != 1 || len(outputs) != 1 {
+		t.Fatalf("the graph has %d inputs and %d outputs, want 1 and 1", len(inputs), len(outputs))
+	}
+	if name, dims := valueInfoShape(t, inputs[0]); name != "input" || !slices.Equal(dims, []string{"batch", "3"}) {
+		t.Errorf("graph input is %q with shape %v, want \"input\" with [batch 3]", name, dims)
+	}
+	if name, dims := valueInfoShape(t, outputs[0]); name != "output" || !slices.Equal(dims, []string{"batch", "2"}) {
+		t.Errorf("graph output is %q with shape %v, want \"output\" with [batch 2]", name, dims)
+	}
+
+	ops := []string{}
+	for _, node := range protoMessages(t, graph, 1) {
+		ops = append(ops, protoStrings(node, 4)...)
+	}
+	want := []string{"Gemm", "Relu", "Concat", "Gemm", "Gemm", "Sigmoid", "Concat", "Gather", "Softmax"}
+	if !slices.Equal(ops, want) {
+		t.Errorf("got nodes %v, want %v", ops, want)
+	}
+	initializers := []string{}
+	for _, tensor := range protoMessages(t, graph, 5) {
+		initializers = append(initializers, protoStrings(tensor, 8)...)
+	}
+	if len(initializers) != 7 {
+		t.Errorf("got initializers %v, want a weight and bias per Gemm and the output indices", initializers)
+	}
+}
+
+func TestExportONNXListsUnsupportedNeurons(t *testing.T) {
+	bp := NewDenseMLP([]int{2, 2}, "relu")
+	bp.Neurons[5] = &Neuron{ID: 5, Type: "lstm", Activation: "tanh", Connections: [][]float64{{1, 1}}}
+	bp.Neurons[6] = &Neuron{ID: 6, Type: "dense", Activation: "gelu", Connections: [][]float64{{1, 1}}}
+	path := filepath.Join(t.TempDir(), "model.onnx")
+
+	err := bp.ExportONNX(path)
+	if err == nil || !strings.Contains(err.Error(), "[5 6]") {
+		t.Fatalf("got error %v, want one listing neurons [5 6]", err)
+	}
+	if _, statErr := os.Stat(path); !os.IsNotExist(statErr) {
+		t.Error("a model was written despite the error")
+	}
+}
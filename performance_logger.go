@@ -25,10 +25,13 @@ type SessionPerformance struct {
 }
 
 // PerformanceLogger handles logging of session performances.
+// The CSV file stays open until Close is called; it is safe for concurrent use.
 type PerformanceLogger struct {
 	LogDir   string
 	FilePath string
 	mu       sync.Mutex
+	file     *os.File
+	writer   *csv.Writer
 }
 
 // NewPerformanceLogger initializes a new PerformanceLogger.
@@ -49,10 +52,8 @@ func NewPerformanceLogger(logDir string) (*PerformanceLogger, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to create CSV file: %v", err)
 	}
-	defer file.Close()
 
 	writer := csv.NewWriter(file)
-	defer writer.Flush()
 
 	// Write header row
 	header := []string{
@@ -66,30 +67,30 @@ func NewPerformanceLogger(logDir string) (*PerformanceLogger, error) {
 		"PredictedProbability",
 		"Timestamp",
 	}
-	if err := writer.Write(header); err != nil {
+	writer.Write(header)
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		file.Close()
 		return nil, fmt.Errorf("failed to write header to CSV: %v", err)
 	}
 
 	return &PerformanceLogger{
 		LogDir:   logDir,
 		FilePath: filePath,
+		file:     file,
+		writer:   writer,
 	}, nil
 }
 
 // Log appends a SessionPerformance record to the CSV file.
+// Each record is flushed to the file before Log returns, so the log survives a crash.
 func (pl *PerformanceLogger) Log(sp SessionPerformance) error {
 	pl.mu.Lock()
 	defer pl.mu.Unlock()
 
-	// Open the CSV file in append mode
-	file, err := os.OpenFile(pl.FilePath, os.O_APPEND|os.O_WRONLY, 0644)
-	if err != nil {
-		return fmt.Errorf("failed to open CSV file for appending: %v", err)
+	if pl.writer == nil {
+		return fmt.Errorf("performance logger is closed")
 	}
-	defer file.Close()
-
-	writer := csv.NewWriter(file)
-	defer writer.Flush()
 
 	// Prepare the data row
 	row := []string{
@@ -105,16 +106,40 @@ func (pl *PerformanceLogger) Log(sp SessionPerformance) error {
 	}
 
 	// Write the data row
-	if err := writer.Write(row); err != nil {
+	pl.writer.Write(row)
+	pl.writer.Flush()
+	if err := pl.writer.Error(); err != nil {
 		return fmt.Errorf("failed to write row to CSV: %v", err)
 	}
 
 	return nil
 }
 
+// Close flushes any buffered rows and closes the CSV file. Later calls to Log fail, and later calls to Close do nothing.
+func (pl *PerformanceLogger) Close() error {
+	pl.mu.Lock()
+	defer pl.mu.Unlock()
+
+	if pl.writer == nil {
+		return nil
+	}
+	pl.writer.Flush()
+	flushErr := pl.writer.Error()
+	closeErr := pl.file.Close()
+	pl.writer, pl.file = nil, nil
+	if flushErr != nil {
+		return fmt.Errorf("failed to flush CSV: %v", flushErr)
+	}
+	if closeErr != nil {
+		return fmt.Errorf("failed to close CSV file: %v", closeErr)
+	}
+	return nil
+}
+
 // EvaluateAndLogPerformance evaluates each session and logs the performance metrics.
 // This function runs independently of training processes.
-// You must pass the sessions you want to evaluate.
+// You must pass the sessions you want to evaluate. Sessions are evaluated concurrently with Predict, so the
+// blueprint's neuron values are left untouched.
 func (bp *Blueprint) EvaluateAndLogPerformance(sessions []Session, logger *PerformanceLogger) error {
 	var wg sync.WaitGroup
 	metricsCh := make(chan SessionPerformance, len(sessions))
@@ -125,8 +150,7 @@ func (bp *Blueprint) EvaluateAndLogPerformance(sessions []Session, logger *Perfo
 		go func(sessionID int, sess Session) {
 			defer wg.Done()
 
-			bp.RunNetwork(sess.InputVariables, sess.Timesteps)
-			predictedOutput := bp.GetOutputs()
+			predictedOutput := bp.Predict(sess.InputVariables, sess.Timesteps)
 
 			// Determine predicted class and its probability
 			probs := softmaxMap(predictedOutput)
@@ -134,7 +158,7 @@ func (bp *Blueprint) EvaluateAndLogPerformance(sessions []Session, logger *Perfo
 			expClass := argmaxMap(sess.ExpectedOutput)
 
 			// Calculate metrics
-			exactAcc, generousAcc, forgiveAcc := calculateAccuracies(predClass, expClass)
+			exactAcc, generousAcc, forgiveAcc := calculateAccuracies(predictedOutput, sess.ExpectedOutput, bp.decileStep())
 			errorMetric := 100.0 - exactAcc
 
 			metricsCh <- SessionPerformance{
@@ -167,17 +191,14 @@ func (bp *Blueprint) EvaluateAndLogPerformance(sessions []Session, logger *Perfo
 	return nil
 }
 
-// calculateAccuracies computes Exact, Generous, and Forgive accuracies based on prediction.
-func calculateAccuracies(predClass, expClass int) (exactAcc, generousAcc, forgiveAcc float64) {
-	if predClass == expClass {
-		exactAcc = 100.0
-		generousAcc = 100.0
-		forgiveAcc = 100.0
-	} else {
-		exactAcc = 0.0
-		generousAcc = 50.0 // Example: partial credit
-		forgiveAcc = 25.0  // Example: minimal credit
-	}
+// calculateAccuracies computes the Exact, Generous and Forgive accuracies of a single session as percentages,
+// using the measures of EvaluateModelPerformance: exact is 100 when the predicted class matches the expected
+// class, generous is the generous value scaled to 0-100, and forgive is 100 when the prediction is decile
+// consistent with the given bucket width.
+func calculateAccuracies(predicted, expected map[int]float64, decileStep float64) (exactAcc, generousAcc, forgiveAcc float64) {
+	exactAcc = ExactMetric{}.Score(predicted, expected)
+	generousAcc = GenerousMetric{}.Score(predicted, expected) * 100.0
+	forgiveAcc = ForgivenessMetric{Step: decileStep}.Score(predicted, expected)
 	return
 }
 
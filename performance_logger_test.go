@@ -0,0 +1,185 @@
+package blueprint
+
+import (
+	"encoding/csv"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// readPerformanceLog returns the rows of a performance log without its header.
+func readPerformanceLog(t *testing.T, path string) [][]string {
+	t.Helper()
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	records, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) == 0 || records[0][0] != "SessionID" {
+		t.Fatalf("performance log has no header: %v", records)
+	}
+	return records[1:]
+}
+
+func TestPerformanceLoggerKeepsEveryRowAfterClose(t *testing.T) {
+	logger, err := NewPerformanceLogger(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const goroutines, rowsEach = 8, 50
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < rowsEach; i++ {
+				if err := logger.Log(SessionPerformance{SessionID: g*rowsEach + i}); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	if err := logger.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := logger.Close(); err != nil {
+		t.Errorf("second Close returned %v, want nil", err)
+	}
+	if err := logger.Log(SessionPerformance{}); err == nil {
+		t.Error("Log after Close succeeded")
+	}
+
+	rows := readPerformanceLog(t, logger.FilePath)
+	ids := make([]int, len(rows))
+	for i, row := range rows {
+		if ids[i], err = strconv.Atoi(row[0]); err != nil {
+			t.Fatalf("row %d has session ID %q", i, row[0])
+		}
+	}
+	sort.Ints(ids)
+	if len(ids) != goroutines*rowsEach {
+		t.Fatalf("log has %d rows, want %d", len(ids), goroutines*rowsEach)
+	}
+	for i, id := range ids {
+		if id != i {
+			t.Fatalf("log is missing session %d", i)
+		}
+	}
+}
+
+func TestEvaluateAndLogPerformanceLogsEverySession(t *testing.T) {
+	bp := evalTestBlueprint()
+	sessions := []Session{}
+	for _, x := range []float64{-2, -1, 1, 2} {
+		sessions = append(sessions, Session{
+			InputVariables: map[int]float64{1: x},
+			ExpectedOutput: map[int]float64{2: boolFloat(x > 0), 3: boolFloat(x < 0)},
+			Timesteps:      1,
+		})
+	}
+	logger, err := NewPerformanceLogger(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := bp.EvaluateAndLogPerformance(sessions, logger); err != nil {
+		t.Fatal(err)
+	}
+	if err := logger.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	rows := readPerformanceLog(t, logger.FilePath)
+	if len(rows) != len(sessions) {
+		t.Fatalf("log has %d rows, want %d", len(rows), len(sessions))
+	}
+	for _, row := range rows {
+		if row[1] != "100.0000" {
+			t.Errorf("session %s has exact accuracy %s, want 100.0000", row[0], row[1])
+		}
+	}
+}
+
+func TestCalculateAccuraciesOfNearMiss(t *testing.T) {
+	expected := map[int]float64{2: 1, 3: 0}
+	cases := []struct {
+		name                           string
+		predicted                      map[int]float64
+		exact, generous, forgive, step float64
+	}{
+		// The wrong class wins narrowly, yet every output is 0.55 away from its target
+		{"near miss", map[int]float64{2: 0.45, 3: 0.55}, 0, 45, 100, 0.1},
+		{"closer miss", map[int]float64{2: 0.49, 3: 0.51}, 0, 49, 100, 0.1},
+		{"uneven errors", map[int]float64{2: 0.45, 3: 0.35}, 100, 55, 0, 0.1},
+		{"uneven errors, coarse step", map[int]float64{2: 0.45, 3: 0.35}, 100, 55, 100, 1},
+	}
+	for _, c := range cases {
+		exact, generous, forgive := calculateAccuracies(c.predicted, expected, c.step)
+		if exact != c.exact || math.Abs(generous-c.generous) > 1e-9 || forgive != c.forgive {
+			t.Errorf("%s: accuracies = %v/%v/%v, want %v/%v/%v", c.name, exact, generous, forgive, c.exact, c.generous, c.forgive)
+		}
+		if generous <= 0 || generous >= 100 {
+			t.Errorf("%s: generous accuracy %v is not strictly between 0 and 100", c.name, generous)
+		}
+	}
+}
+
+// logByReopening appends a row the way the logger did before it kept its file open.
+func logByReopening(path string, sp SessionPerformance) error {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	writer := csv.NewWriter(file)
+	writer.Write([]string{
+		fmt.Sprintf("%d", sp.SessionID), fmt.Sprintf("%.4f", sp.ExactAccuracy), fmt.Sprintf("%.4f", sp.GenerousAccuracy),
+		fmt.Sprintf("%.4f", sp.ForgiveAccuracy), fmt.Sprintf("%.4f", sp.ErrorMetric), fmt.Sprintf("%d", sp.PredictedClass),
+		fmt.Sprintf("%d", sp.ExpectedClass), fmt.Sprintf("%.4f", sp.PredictedProbability), sp.Timestamp,
+	})
+	writer.Flush()
+	return writer.Error()
+}
+
+func BenchmarkPerformanceLoggerLog(b *testing.B) {
+	logger, err := NewPerformanceLogger(b.TempDir())
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer logger.Close()
+	sp := SessionPerformance{ExactAccuracy: 100, Timestamp: time.Now().Format(time.RFC3339)}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		sp.SessionID = i
+		if err := logger.Log(sp); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkPerformanceLogReopening(b *testing.B) {
+	logger, err := NewPerformanceLogger(b.TempDir())
+	if err != nil {
+		b.Fatal(err)
+	}
+	logger.Close()
+	sp := SessionPerformance{ExactAccuracy: 100, Timestamp: time.Now().Format(time.RFC3339)}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		sp.SessionID = i
+		if err := logByReopening(logger.FilePath, sp); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package blueprint
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// numConnections returns the number of connections of the neuron across float64 and float32 storage.
+func (neuron *Neuron) numConnections() int {
+	return len(neuron.Connections) + len(neuron.Connections32)
+}
+
+// connection returns the source ID and weight of the i-th connection, widening float32 storage to float64.
+// Connections stored as float64 come first, followed by those stored as float32.
+func (neuron *Neuron) connection(i int) (int, float64) {
+	if i < len(neuron.Connections) {
+		return int(neuron.Connections[i][0]), neuron.Connections[i][1]
+	}
+	conn := neuron.Connections32[i-len(neuron.Connections)]
+	return int(conn[0]), float64(conn[1])
+}
+
+// keepConnections keeps only the connections at the given ascending indices, in the numbering of connection,
+// together with the LSTM gate weights at those indices.
+func (neuron *Neuron) keepConnections(indices []int) {
+	numConnections, numFloat64 := neuron.numConnections(), len(neuron.Connections)
+	for gate, weights := range neuron.GateWeights {
+		if len(weights) != numConnections {
+			continue
+		}
+		keptWeights := make([]float64, len(indices))
+		for k, i := range indices {
+			keptWeights[k] = weights[i]
+		}
+		neuron.GateWeights[gate] = keptWeights
+	}
+
+	connections, connections32 := [][]float64{}, [][2]float32{}
+	for _, i := range indices {
+		if i < numFloat64 {
+			connections = append(connections, neuron.Connections[i])
+		} else {
+			connections32 = append(connections32, neuron.Connections32[i-numFloat64])
+		}
+	}
+	if neuron.Connections != nil {
+		neuron.Connections = connections
+	}
+	if neuron.Connections32 != nil {
+		neuron.Connections32 = connections32
+	}
+}
+
+// setConnectionWeight overwrites the weight of the i-th connection in whichever storage holds it.
+func (neuron *Neuron) setConnectionWeight(i int, weight float64) {
+	if i < len(neuron.Connections) {
+		neuron.Connections[i][1] = weight
+		return
+	}
+	neuron.Connections32[i-len(neuron.Connections)][1] = float32(weight)
+}
+
+// setConnectionSource overwrites the source ID of the i-th connection in whichever storage holds it.
+func (neuron *Neuron) setConnectionSource(i int, sourceID int) {
+	if i < len(neuron.Connections) {
+		neuron.Connections[i][0] = float64(sourceID)
+		return
+	}
+	neuron.Connections32[i-len(neuron.Connections)][0] = float32(sourceID)
+}
+
+// ConvertToFloat32Storage moves every connection weight into float32 storage and enables LowPrecision.
+// Forward passes keep computing in float64, widening each weight as it is read, while the memory
+// used by connections drops to a fraction of the [][]float64 representation.
+// Source IDs above 2^24 cannot be represented exactly and cause an error without modifying the model.
+// Low-precision models are meant for inference: training and mutation methods only update float64
+// connections, so call ConvertToFloat64Storage before training.
+func (bp *Blueprint) ConvertToFloat32Storage() error {
+	const maxExactID = 1 << 24
+	for id, neuron := range bp.Neurons {
+		for _, conn := range neuron.Connections {
+			if conn[0] > maxExactID {
+				return fmt.Errorf("neuron %d has a connection from %d, which float32 cannot represent exactly", id, int(conn[0]))
+			}
+		}
+	}
+
+	for _, neuron := range bp.Neurons {
+		if len(neuron.Connections) == 0 {
+			continue
+		}
+		packed := make([][2]float32, 0, neuron.numConnections())
+		for _, conn := range neuron.Connections {
+			packed = append(packed, [2]float32{float32(conn[0]), float32(conn[1])})
+		}
+		neuron.Connections32 = append(packed, neuron.Connections32...)
+		neuron.Connections = nil
+	}
+	bp.LowPrecision = true
+	bp.invalidateCompiled()
+	return nil
+}
+
+// ConvertToFloat64Storage moves every connection back into the float64 Connections field and disables LowPrecision.
+// Weights keep the precision they had in float32 storage.
+func (bp *Blueprint) ConvertToFloat64Storage() {
+	for _, neuron := range bp.Neurons {
+		if len(neuron.Connections32) == 0 {
+			continue
+		}
+		for _, conn := range neuron.Connections32 {
+			neuron.Connections = append(neuron.Connections, []float64{float64(conn[0]), float64(conn[1])})
+		}
+		neuron.Connections32 = nil
+	}
+	bp.LowPrecision = false
+	bp.invalidateCompiled()
+}
+
+// ConnectionMemoryBytes estimates the heap memory used by the connection lists of all neurons,
+// counting slice headers and the float data they point to.
+func (bp *Blueprint) ConnectionMemoryBytes() int {
+	const sliceHeader = int(unsafe.Sizeof([]float64{}))
+	total := 0
+	for _, neuron := range bp.Neurons {
+		total += cap(neuron.Connections) * sliceHeader
+		for _, conn := range neuron.Connections {
+			total += cap(conn) * 8
+		}
+		total += cap(neuron.Connections32) * int(unsafe.Sizeof([2]float32{}))
+	}
+	return total
+}
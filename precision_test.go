@@ -0,0 +1,70 @@
+package blueprint
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFloat32StorageShrinksMemoryAndKeepsAccuracy(t *testing.T) {
+	randomSource.Seed(6)
+	bp := NewDenseMLP([]int{2, 8, 2}, "tanh") // Outputs 11 and 12
+	// Output 11 marks samples whose first input is the larger one
+	sessions := []Session{}
+	for i := 0; i < 16; i++ {
+		x, y := random.Float64(), random.Float64()
+		sessions = append(sessions, Session{
+			InputVariables: map[int]float64{1: x, 2: y},
+			ExpectedOutput: map[int]float64{11: boolFloat(x > y), 12: boolFloat(x <= y)},
+			Timesteps:      1,
+		})
+	}
+	if err := bp.TrainBackprop(sessions, 0.1, 2000); err != nil {
+		t.Fatal(err)
+	}
+	before := bp.Evaluate(sessions)
+	if before.ExactAccuracy <= 95 {
+		t.Fatalf("training reached only %.1f%% exact accuracy", before.ExactAccuracy)
+	}
+
+	low := bp.DeepCopy()
+	if err := low.ConvertToFloat32Storage(); err != nil {
+		t.Fatal(err)
+	}
+	after := low.Evaluate(sessions)
+
+	fullBytes, lowBytes := bp.ConnectionMemoryBytes(), low.ConnectionMemoryBytes()
+	t.Logf("connection memory: %d bytes in float64, %d bytes in float32", fullBytes, lowBytes)
+	// A float64 connection costs a slice header and two floats, a float32 one two float32s
+	if lowBytes*4 > fullBytes {
+		t.Errorf("float32 storage uses %d bytes, want at most a quarter of the %d bytes in float64", lowBytes, fullBytes)
+	}
+
+	if after.ExactAccuracy != before.ExactAccuracy {
+		t.Errorf("exact accuracy changed from %.2f%% to %.2f%%", before.ExactAccuracy, after.ExactAccuracy)
+	}
+	if delta := math.Abs(after.GenerousAccuracy - before.GenerousAccuracy); delta > 1e-4 {
+		t.Errorf("generous accuracy changed by %g, want at most 1e-4", delta)
+	}
+	if delta := math.Abs(after.ForgivenessAccuracy - before.ForgivenessAccuracy); delta > 1e-2 {
+		t.Errorf("forgiveness accuracy changed by %g percentage points, want at most 0.01", delta)
+	}
+}
+
+func TestWeightDifferenceComparesFloat32Storage(t *testing.T) {
+	original := &Neuron{Connections32: [][2]float32{{1, 0.5}, {2, -0.25}}}
+	restored := &Neuron{Connections32: [][2]float32{{1, 0.5}, {2, 0.25}}}
+	if diff := neuronWeightDifference(original, restored); diff != 0.5 {
+		t.Errorf("difference between float32 weights -0.25 and 0.25 = %v, want 0.5", diff)
+	}
+	if diff := neuronWeightDifference(original, nil); diff != 2 {
+		t.Errorf("difference against a missing neuron = %v, want the largest value 2", diff)
+	}
+
+	bp := NewDenseMLP([]int{2, 3, 2}, "relu")
+	if err := bp.ConvertToFloat32Storage(); err != nil {
+		t.Fatal(err)
+	}
+	if diff := bp.SerializationFidelity(); diff != 0 {
+		t.Errorf("float32 weights drifted by %v through JSON, want an exact round-trip", diff)
+	}
+}
@@ -0,0 +1,73 @@
+package blueprint
+
+import "math"
+
+const (
+	priorWarmStartIterations = 200
+	priorWarmStartTolerance  = 1e-6
+)
+
+// PriorWarmStart adjusts the biases of the output neurons so that, with every input set to zero, the softmaxed
+// outputs approximate targetDistribution, which maps output neuron IDs to class probabilities. The distribution
+// is normalized and outputs missing from it get a negligible probability. Linear outputs converge immediately;
+// saturating activations such as sigmoid or tanh bound the ratios they can express, in which case the outputs
+// are pushed as close to the target as the activation allows. Only output biases are changed.
+func (bp *Blueprint) PriorWarmStart(targetDistribution map[int]float64) {
+	total := 0.0
+	for id, p := range targetDistribution {
+		if _, exists := bp.Neurons[id]; !exists || !bp.isOutputNode(id) {
+			bp.warnf("Warning: PriorWarmStart ignores %d, which is not an output neuron.", id)
+			continue
+		}
+		if p > 0 {
+			total += p
+		}
+	}
+	if total <= 0 {
+		bp.warnf("Warning: PriorWarmStart needs a positive probability for at least one output neuron.")
+		return
+	}
+
+	target := make(map[int]float64, len(bp.OutputNodes))
+	for _, id := range bp.OutputNodes {
+		p := targetDistribution[id] / total
+		if p < minProbability || math.IsNaN(p) {
+			p = minProbability
+		}
+		target[id] = p
+	}
+
+	zeroInputs := make(map[int]float64, len(bp.InputNodes))
+	for _, id := range bp.InputNodes {
+		zeroInputs[id] = 0
+	}
+
+	// Shift each bias by the log ratio between the target and the current probability
+	for iteration := 0; iteration < priorWarmStartIterations; iteration++ {
+		bp.Forward(zeroInputs, 1)
+		outputs := bp.GetOutputs()
+
+		maxGap := 0.0
+		for _, id := range bp.OutputNodes {
+			neuron, exists := bp.Neurons[id]
+			if !exists {
+				continue
+			}
+			current := outputs[id]
+			maxGap = math.Max(maxGap, math.Abs(target[id]-current))
+			if current < minProbability {
+				current = minProbability
+			}
+			neuron.Bias += math.Log(target[id] / current)
+		}
+		if maxGap < priorWarmStartTolerance {
+			break
+		}
+	}
+	bp.invalidateCompiled()
+
+	if bp.Debug {
+		bp.Forward(zeroInputs, 1)
+		bp.debugf("PriorWarmStart: zero-input outputs %v", bp.GetOutputs())
+	}
+}
@@ -0,0 +1,87 @@
+package blueprint
+
+import "math"
+
+// PruneConnections removes every connection whose absolute weight is below threshold and returns how many were
+// removed. LSTM gate weights are removed along with their connections. Hidden neurons whose value no longer
+// reaches any other neuron are removed as well, repeatedly, since they cannot affect the outputs; input and output
+// neurons are always kept. Works on float64 and float32 connection storage.
+func (bp *Blueprint) PruneConnections(threshold float64) int {
+	removed := 0
+	for _, id := range bp.getAllNeuronIDs() {
+		neuron := bp.Neurons[id]
+		kept := make([]int, 0, neuron.numConnections())
+		for i := 0; i < neuron.numConnections(); i++ {
+			if _, weight := neuron.connection(i); math.Abs(weight) >= threshold {
+				kept = append(kept, i)
+			}
+		}
+		if len(kept) < neuron.numConnections() {
+			removed += neuron.numConnections() - len(kept)
+			neuron.keepConnections(kept)
+		}
+	}
+	bp.invalidateCompiled()
+
+	prunedNeurons := bp.pruneDeadNeurons()
+	bp.debugf("PruneConnections: removed %d connections below %g and %d dead neurons.", removed, threshold, prunedNeurons)
+	return removed
+}
+
+// PruneConnectionsIfSafe prunes the blueprint with PruneConnections only if a pruned copy loses no more than
+// maxDrop percentage points of any accuracy on sessions. Exact and forgiveness accuracy are already percentages;
+// generous accuracy, a fraction between 0 and 1, is compared after scaling it by 100, so a maxDrop of 1 allows it
+// to fall by 0.01. It returns the number of connections removed, or 0 when pruning was rejected and the blueprint
+// left unchanged.
+func (bp *Blueprint) PruneConnectionsIfSafe(threshold float64, sessions []Session, maxDrop float64) int {
+	pruned := bp.DeepCopy()
+	if pruned.PruneConnections(threshold) == 0 {
+		return 0
+	}
+	if reason := metricRegression(pruned.Evaluate(sessions), bp.Evaluate(sessions), maxDrop); reason != "" {
+		bp.infof("Pruning connections below %g rejected: %s.", threshold, reason)
+		return 0
+	}
+	return bp.PruneConnections(threshold)
+}
+
+// pruneDeadNeurons removes hidden neurons that no neuron reads from, repeating until none is left, and returns
+// how many were removed. Connections, NCA neighbourhoods and quantum neuron inputs all count as reads.
+func (bp *Blueprint) pruneDeadNeurons() int {
+	pruned := 0
+	for {
+		read := make(map[int]bool)
+		for _, neuron := range bp.Neurons {
+			for i := 0; i < neuron.numConnections(); i++ {
+				sourceID, _ := neuron.connection(i)
+				if sourceID != neuron.ID {
+					read[sourceID] = true
+				}
+			}
+			for _, id := range neuron.NeighborhoodIDs {
+				read[id] = true
+			}
+		}
+		for _, neuron := range bp.QuantumNeurons {
+			for _, conn := range neuron.Connections {
+				if len(conn) > 0 {
+					read[int(real(conn[0]))] = true
+				}
+			}
+		}
+
+		dead := []int{}
+		for _, id := range bp.getAllNeuronIDs() {
+			if !read[id] && !bp.isInputNode(id) && !bp.isOutputNode(id) {
+				dead = append(dead, id)
+			}
+		}
+		if len(dead) == 0 {
+			return pruned
+		}
+		for _, id := range dead {
+			bp.RemoveNeuron(id)
+		}
+		pruned += len(dead)
+	}
+}
@@ -0,0 +1,48 @@
+package blueprint
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestPruneConnectionsRemovesTinyEdges(t *testing.T) {
+	bp := NewBlueprint()
+	bp.AddInputNeurons([]int{1, 2})
+	bp.AddOutputNeurons([]int{3}, "linear")
+	bp.Neurons[3].Connections = [][]float64{{1, 0.8}, {2, 0.001}, {4, -0.002}, {5, 0.6}}
+	// Hidden neuron 4 is only read through a tiny edge, so it dies with it
+	bp.Neurons[4] = &Neuron{ID: 4, Type: "dense", Activation: "relu", Connections: [][]float64{{1, 0.5}}}
+	// Hidden neuron 5 keeps its strong edges and loses a tiny one along with the matching gate weights
+	bp.Neurons[5] = &Neuron{
+		ID: 5, Type: "lstm", Activation: "tanh",
+		Connections: [][]float64{{1, 0.3}, {2, -0.0005}, {1, 0.4}},
+		GateWeights: map[string][]float64{
+			"input": {1, 2, 3}, "forget": {4, 5, 6}, "output": {7, 8, 9}, "cell": {10, 11, 12},
+		},
+	}
+
+	if removed := bp.PruneConnections(0.01); removed != 3 {
+		t.Errorf("PruneConnections removed %d connections, want 3", removed)
+	}
+
+	if got, want := bp.Neurons[3].Connections, [][]float64{{1, 0.8}, {5, 0.6}}; !slices.EqualFunc(got, want, slices.Equal) {
+		t.Errorf("output connections = %v, want %v", got, want)
+	}
+	if _, exists := bp.Neurons[4]; exists {
+		t.Error("neuron 4 is no longer read by any neuron but was not pruned")
+	}
+	if got, want := bp.Neurons[5].Connections, [][]float64{{1, 0.3}, {1, 0.4}}; !slices.EqualFunc(got, want, slices.Equal) {
+		t.Errorf("neuron 5 connections = %v, want %v", got, want)
+	}
+	if got := bp.Neurons[5].GateWeights["forget"]; !slices.Equal(got, []float64{4, 6}) {
+		t.Errorf("forget gate weights = %v, want [4 6]", got)
+	}
+	for _, id := range []int{1, 2, 3} {
+		if _, exists := bp.Neurons[id]; !exists {
+			t.Errorf("input or output neuron %d was pruned", id)
+		}
+	}
+	if errs := bp.Validate(); len(errs) > 0 {
+		t.Errorf("pruned blueprint is invalid: %v", errs)
+	}
+}
@@ -4,7 +4,7 @@ import (
 	"fmt"
 	"math"
 	"math/cmplx"
-	"math/rand"
+	"slices"
 )
 
 // QuantumState represents a quantum state with amplitude and phase
@@ -21,17 +21,21 @@ type QuantumNeuron struct {
 	Entanglements []EntanglementInfo
 	Superposition []complex128
 	Connections   [][]complex128 // Quantum weights as complex numbers
+	CNOTTarget    int            // Quantum neuron flipped by the neuron's CNOT gates (0 uses the first entanglement partner)
 
 	// Additional fields for entanglement and measurement
 	EntanglementCreated bool
 	IsEntangled         bool
 	IsMeasured          bool
+
+	output float64 // Softmaxed measured value when the neuron is an output node, set by ApplySoftmax
 }
 
 // QuantumGate represents a quantum operation
 type QuantumGate struct {
-	Type   string // "Hadamard", "PauliX", "PauliY", "PauliZ", "CNOT"
+	Type   string // "Hadamard", "PauliX", "PauliY", "PauliZ", "Phase", "CNOT"
 	Matrix [][]complex128
+	Angle  float64 // Phase shift of a "Phase" gate in radians
 }
 
 // EntanglementInfo tracks quantum entanglement between neurons
@@ -41,43 +45,14 @@ type EntanglementInfo struct {
 	Strength  float64
 }
 
-// ProcessQuantumNeuron handles quantum operations
-func (bp *Blueprint) ProcessQuantumNeuron(neuron *QuantumNeuron) {
-	// Check if the neuron is entangled
-	if len(neuron.Entanglements) > 0 {
-		for _, entanglement := range neuron.Entanglements {
-			if partner, exists := bp.QuantumNeurons[entanglement.PartnerID]; exists {
-				switch entanglement.Type {
-				case "Bell":
-					// Create Bell state if not already created
-					if !neuron.EntanglementCreated && !partner.EntanglementCreated {
-						bp.createBellState(neuron, partner)
-						neuron.EntanglementCreated = true
-						partner.EntanglementCreated = true
-					}
-					// Apply quantum gates to both entangled qubits
-					for _, gate := range neuron.QuantumGates {
-						switch gate.Type {
-						case "Hadamard":
-							neuron.Superposition = applyHadamard(neuron.Superposition)
-							partner.Superposition = applyHadamard(partner.Superposition)
-							fmt.Printf("After Hadamard gate on Neuron %d and Neuron %d: \n", neuron.ID, partner.ID)
-							fmt.Printf("Neuron %d Superposition=%v\n", neuron.ID, neuron.Superposition)
-							fmt.Printf("Neuron %d Superposition=%v\n", partner.ID, partner.Superposition)
-						case "PauliX":
-							neuron.Superposition = applyPauliXToSuperposition(neuron.Superposition)
-							partner.Superposition = applyPauliXToSuperposition(partner.Superposition)
-							fmt.Printf("After PauliX gate on Neuron %d and Neuron %d: \n", neuron.ID, partner.ID)
-							fmt.Printf("Neuron %d Superposition=%v\n", neuron.ID, neuron.Superposition)
-							fmt.Printf("Neuron %d Superposition=%v\n", partner.ID, partner.Superposition)
-						}
-					}
-					// Measure entangled qubits
-					bp.measureEntangledQubits(neuron, partner)
-					return
-				}
-			}
-		}
+// ProcessQuantumNeuron handles quantum operations, measuring the neuron and setting IsMeasured.
+// A neuron with a Bell or GHZ entanglement, or with a CNOT gate, is processed together with its partners and
+// CNOT target in a QuantumRegister, see processInRegister. Any other neuron is processed on its own superposition.
+// It returns an error, leaving the neuron unchanged, when the target of a CNOT gate is missing.
+func (bp *Blueprint) ProcessQuantumNeuron(neuron *QuantumNeuron) error {
+	partners := bp.entangledPartners(neuron)
+	if len(partners) > 0 || hasQuantumGate(neuron, "CNOT") {
+		return bp.processInRegister(neuron, partners)
 	}
 
 	// Apply quantum gates to unentangled qubits
@@ -85,13 +60,16 @@ func (bp *Blueprint) ProcessQuantumNeuron(neuron *QuantumNeuron) {
 		switch gate.Type {
 		case "Hadamard":
 			neuron.Superposition = applyHadamard(neuron.Superposition)
-			fmt.Printf("After Hadamard gate on Neuron %d: Superposition=%v\n", neuron.ID, neuron.Superposition)
 		case "PauliX":
 			neuron.Superposition = applyPauliXToSuperposition(neuron.Superposition)
-			fmt.Printf("After PauliX gate on Neuron %d: Superposition=%v\n", neuron.ID, neuron.Superposition)
-		case "CNOT":
-			bp.applyCNOT(neuron)
+		case "PauliY":
+			neuron.Superposition = applyPauliY(neuron.Superposition)
+		case "PauliZ":
+			neuron.Superposition = applyPauliZ(neuron.Superposition)
+		case "Phase":
+			neuron.Superposition = applyPhase(neuron.Superposition, gate.Angle)
 		}
+		bp.debugf("After %s gate on Neuron %d: Superposition=%v", gate.Type, neuron.ID, neuron.Superposition)
 	}
 
 	// Normalize the superposition
@@ -100,7 +78,9 @@ func (bp *Blueprint) ProcessQuantumNeuron(neuron *QuantumNeuron) {
 	// Quantum measurement (collapses superposition)
 	measuredValue := bp.measureQuantumState(neuron.Superposition)
 	neuron.QuantumState.Amplitude = complex(measuredValue, 0)
-	fmt.Printf("Quantum Neuron %d measured value: %f\n", neuron.ID, measuredValue)
+	neuron.IsMeasured = true
+	bp.debugf("Quantum Neuron %d measured value: %f", neuron.ID, measuredValue)
+	return nil
 }
 
 // Helper functions for quantum operations
@@ -145,50 +125,35 @@ func applyPauliXToSuperposition(state []complex128) []complex128 {
 	return normalizeState(newState)
 }
 
-// applyCNOT applies the CNOT gate to entangle qubits.
-func (bp *Blueprint) applyCNOT(control *QuantumNeuron) {
-	targetID := control.ID + 1 // Adjust as needed
-	target, exists := bp.QuantumNeurons[targetID]
-	if !exists {
-		fmt.Printf("CNOT gate error: Target neuron %d not found.\n", targetID)
-		return
+// applyPauliY applies the Pauli-Y gate to the superposition, mapping |0⟩ to i|1⟩ and |1⟩ to -i|0⟩.
+func applyPauliY(state []complex128) []complex128 {
+	if len(state) != 2 {
+		// Invalid state
+		return state
 	}
+	newState := []complex128{-1i * state[1], 1i * state[0]}
+	return normalizeState(newState)
+}
 
-	// Apply CNOT operation on the superpositions
-	// For simplicity, only handling the basic case where both qubits have 2 basis states
-	if len(control.Superposition) != 2 || len(target.Superposition) != 2 {
-		fmt.Printf("CNOT operation not supported for current states.\n")
-		return
+// applyPauliZ applies the Pauli-Z gate to the superposition, flipping the sign of the |1⟩ amplitude.
+func applyPauliZ(state []complex128) []complex128 {
+	if len(state) != 2 {
+		// Invalid state
+		return state
 	}
+	newState := []complex128{state[0], -state[1]}
+	return normalizeState(newState)
+}
 
-	// Build joint state
-	jointState := make([]complex128, 4)
-	jointState[0] = control.Superposition[0] * target.Superposition[0] // |00⟩
-	jointState[1] = control.Superposition[0] * target.Superposition[1] // |01⟩
-	jointState[2] = control.Superposition[1] * target.Superposition[0] // |10⟩
-	jointState[3] = control.Superposition[1] * target.Superposition[1] // |11⟩
-
-	// Apply CNOT gate
-	// CNOT flips target qubit when control qubit is |1⟩
-	// Swap amplitudes of |10⟩ and |11⟩
-	jointState[2], jointState[3] = jointState[3], jointState[2]
-
-	// Update individual superpositions
-	controlSuperposition := []complex128{
-		jointState[0] + jointState[1], // Sum over target qubit
-		jointState[2] + jointState[3],
-	}
-	targetSuperposition := []complex128{
-		jointState[0] + jointState[2], // Sum over control qubit
-		jointState[1] + jointState[3],
+// applyPhase applies the phase gate to the superposition, multiplying the |1⟩ amplitude by e^(i angle).
+// An angle of π is the Pauli-Z gate.
+func applyPhase(state []complex128, angle float64) []complex128 {
+	if len(state) != 2 {
+		// Invalid state
+		return state
 	}
-
-	control.Superposition = normalizeState(controlSuperposition)
-	target.Superposition = normalizeState(targetSuperposition)
-
-	fmt.Printf("After CNOT gate:\n")
-	fmt.Printf("Control Neuron %d superposition: %v\n", control.ID, control.Superposition)
-	fmt.Printf("Target Neuron %d superposition: %v\n", target.ID, target.Superposition)
+	newState := []complex128{state[0], cmplx.Exp(complex(0, angle)) * state[1]}
+	return normalizeState(newState)
 }
 
 // measureQuantumState collapses the superposition based on quantum measurement postulates.
@@ -198,9 +163,9 @@ func (bp *Blueprint) measureQuantumState(superposition []complex128) float64 {
 		probabilities[i] = cmplx.Abs(amp) * cmplx.Abs(amp)
 	}
 
-	fmt.Printf("Measuring quantum state with probabilities: %v\n", probabilities)
+	bp.debugf("Measuring quantum state with probabilities: %v", probabilities)
 
-	rnd := rand.Float64()
+	rnd := random.Float64()
 	cumulative := 0.0
 	for i, prob := range probabilities {
 		cumulative += prob
@@ -211,50 +176,160 @@ func (bp *Blueprint) measureQuantumState(superposition []complex128) float64 {
 	return float64(len(superposition) - 1)
 }
 
-// measureEntangledQubits simulates the measurement of entangled qubits with correlated outcomes.
-func (bp *Blueprint) measureEntangledQubits(q1, q2 *QuantumNeuron) {
-	rnd := rand.Float64()
-	if rnd < 0.5 {
-		// Both qubits collapse to |0⟩
-		q1.Superposition = []complex128{1, 0}
-		q2.Superposition = []complex128{1, 0}
-		q1.QuantumState.Amplitude = 0
-		q2.QuantumState.Amplitude = 0
-		fmt.Printf("Both qubits collapsed to |0⟩\n")
-	} else {
-		// Both qubits collapse to |1⟩
-		q1.Superposition = []complex128{0, 1}
-		q2.Superposition = []complex128{0, 1}
-		q1.QuantumState.Amplitude = 1
-		q2.QuantumState.Amplitude = 1
-		fmt.Printf("Both qubits collapsed to |1⟩\n")
-	}
-	fmt.Printf("Quantum Neuron %d measured value: %f\n", q1.ID, real(q1.QuantumState.Amplitude))
-	fmt.Printf("Quantum Neuron %d measured value: %f\n", q2.ID, real(q2.QuantumState.Amplitude))
+// entangledPartners returns the IDs of the quantum neurons entangled with the neuron: the first existing Bell
+// partner, or else every existing GHZ partner. Cluster entanglements are not modeled.
+func (bp *Blueprint) entangledPartners(neuron *QuantumNeuron) []int {
+	partners := []int{}
+	for _, entanglement := range neuron.Entanglements {
+		if _, exists := bp.QuantumNeurons[entanglement.PartnerID]; !exists || entanglement.PartnerID == neuron.ID {
+			continue
+		}
+		switch entanglement.Type {
+		case "Bell":
+			return []int{entanglement.PartnerID}
+		case "GHZ":
+			if !slices.Contains(partners, entanglement.PartnerID) {
+				partners = append(partners, entanglement.PartnerID)
+			}
+		}
+	}
+	return partners
 }
 
-// createBellState entangles two qubits into a Bell state.
-func (bp *Blueprint) createBellState(q1, q2 *QuantumNeuron) {
-	// Indicate that the qubits are entangled
-	q1.IsEntangled = true
-	q2.IsEntangled = true
+// hasQuantumGate reports whether the neuron has a gate of the given type.
+func hasQuantumGate(neuron *QuantumNeuron, gateType string) bool {
+	for _, gate := range neuron.QuantumGates {
+		if gate.Type == gateType {
+			return true
+		}
+	}
+	return false
+}
+
+// processInRegister processes the neuron in a register shared with its entangled partners and its CNOT target.
+// The neuron and its partners are reset to |0⟩ and entangled into a Bell or GHZ state, the neuron's gates are
+// applied to its qubit, and the neuron and its partners are measured together, so their outcomes stay
+// correlated. The CNOT target is not measured: it keeps the state it collapsed to.
+func (bp *Blueprint) processInRegister(neuron *QuantumNeuron, partners []int) error {
+	// Resolve the CNOT target before changing any state
+	hasCNOT := hasQuantumGate(neuron, "CNOT")
+	targetID := 0
+	if hasCNOT {
+		var err error
+		if targetID, err = bp.cnotTarget(neuron); err != nil {
+			return err
+		}
+	}
+
+	group := []*QuantumNeuron{neuron}
+	for _, id := range partners {
+		group = append(group, bp.QuantumNeurons[id])
+	}
+	neuronIDs := []int{}
+	for _, member := range group {
+		if len(partners) > 0 {
+			member.Superposition = []complex128{1, 0}
+		}
+		neuronIDs = append(neuronIDs, member.ID)
+	}
+
+	// The CNOT target joins the register unless it is already entangled with the neuron
+	var target *QuantumNeuron
+	if hasCNOT && !slices.Contains(neuronIDs, targetID) {
+		target = bp.QuantumNeurons[targetID]
+		neuronIDs = append(neuronIDs, targetID)
+	}
+
+	register := bp.newQuantumRegister(neuronIDs)
+	if len(partners) > 0 {
+		bp.createGHZState(register, group...)
+	}
+	for _, gate := range neuron.QuantumGates {
+		var err error
+		switch gate.Type {
+		case "Hadamard":
+			err = register.ApplyGate(neuron.ID, hadamardGate)
+		case "PauliX":
+			err = register.ApplyGate(neuron.ID, pauliXGate)
+		case "PauliY":
+			err = register.ApplyGate(neuron.ID, pauliYGate)
+		case "PauliZ":
+			err = register.ApplyGate(neuron.ID, pauliZGate)
+		case "Phase":
+			err = register.ApplyGate(neuron.ID, phaseGate(gate.Angle))
+		case "CNOT":
+			err = register.ApplyCNOT(neuron.ID, targetID)
+		}
+		if err != nil {
+			return fmt.Errorf("%s gate on quantum neuron %d: %w", gate.Type, neuron.ID, err)
+		}
+	}
 
-	fmt.Printf("Created Bell state between Neuron %d and Neuron %d\n", q1.ID, q2.ID)
+	for _, member := range group {
+		outcome, _ := register.Measure(member.ID)
+		member.Superposition = []complex128{1, 0}
+		if outcome == 1 {
+			member.Superposition = []complex128{0, 1}
+		}
+		member.QuantumState.Amplitude = complex(float64(outcome), 0)
+		member.IsMeasured = true
+		bp.debugf("Quantum Neuron %d measured value: %f", member.ID, float64(outcome))
+	}
+	if target != nil {
+		if state, ok := register.QubitState(target.ID); ok {
+			target.Superposition = state
+		}
+		bp.debugf("CNOT target Neuron %d superposition: %v", target.ID, target.Superposition)
+	}
+	return nil
+}
+
+// cnotTarget returns the ID of the quantum neuron flipped by the neuron's CNOT gates: CNOTTarget when set,
+// otherwise the partner of its first entanglement.
+func (bp *Blueprint) cnotTarget(neuron *QuantumNeuron) (int, error) {
+	targetID := neuron.CNOTTarget
+	if targetID == 0 {
+		if len(neuron.Entanglements) == 0 {
+			return 0, fmt.Errorf("quantum neuron %d has a CNOT gate but neither a CNOTTarget nor an entanglement partner", neuron.ID)
+		}
+		targetID = neuron.Entanglements[0].PartnerID
+	}
+	if targetID == neuron.ID {
+		return 0, fmt.Errorf("quantum neuron %d is its own CNOT target", neuron.ID)
+	}
+	if _, exists := bp.QuantumNeurons[targetID]; !exists {
+		return 0, fmt.Errorf("CNOT target %d of quantum neuron %d does not exist", targetID, neuron.ID)
+	}
+	return targetID, nil
 }
 
-// createGHZState creates a GHZ state among multiple qubits.
-func (bp *Blueprint) createGHZState(neurons ...*QuantumNeuron) {
-	amplitude := complex(1/math.Sqrt(2), 0)
+// createGHZState entangles qubits that are all in |0⟩ into the GHZ state (|0...0⟩ + |1...1⟩)/√2 by applying a
+// Hadamard gate to the first and a CNOT from it to each of the others. For two qubits this is the Bell state
+// (|00⟩ + |11⟩)/√2.
+func (bp *Blueprint) createGHZState(register *QuantumRegister, neurons ...*QuantumNeuron) {
+	if len(neurons) == 0 {
+		return
+	}
+	register.ApplyGate(neurons[0].ID, hadamardGate)
+	for _, neuron := range neurons[1:] {
+		register.ApplyCNOT(neurons[0].ID, neuron.ID)
+	}
 	for _, neuron := range neurons {
-		neuron.Superposition = []complex128{amplitude, 0}
 		neuron.IsEntangled = true
+		neuron.EntanglementCreated = true
 	}
-	// Note: Proper GHZ state creation would require modeling the joint state of the qubits.
-	fmt.Printf("Created GHZ state among neurons: ")
-	for _, neuron := range neurons {
-		fmt.Printf("%d ", neuron.ID)
+
+	if bp.Debug {
+		state := "GHZ"
+		if len(neurons) == 2 {
+			state = "Bell"
+		}
+		neuronIDs := make([]int, len(neurons))
+		for i, neuron := range neurons {
+			neuronIDs[i] = neuron.ID
+		}
+		bp.debugf("Created %s state among neurons: %v", state, neuronIDs)
 	}
-	fmt.Printf("\n")
 }
 
 // QuantumLayer represents a collection of quantum neurons
@@ -263,3 +338,53 @@ type QuantumLayer struct {
 	Topology  string  // "Sequential", "Entangled", "Hybrid"
 	Coherence float64 // Quantum coherence time
 }
+
+// measuredValue returns the outcome of the neuron's last measurement, 0 or 1, which is the value classical
+// neurons connected to it receive.
+func (neuron *QuantumNeuron) measuredValue() float64 {
+	return real(neuron.QuantumState.Amplitude)
+}
+
+// encodeQuantumInputs sets the superposition of a quantum neuron with connections from its inputs. Each
+// connection is a pair {source ID, weight}, the ID in the real part of the first element and the complex weight
+// in the second, and the inputs sum to z = Σ weight · value of the source. The superposition is (|0⟩ + z|1⟩)
+// normalized: without input the neuron starts in |0⟩, the larger |z| the likelier it measures 1, and the phase of
+// z becomes the relative phase of |1⟩. Sources may be classical neurons or quantum neurons, which contribute
+// their measured value. A neuron without connections keeps its superposition.
+func (bp *Blueprint) encodeQuantumInputs(neuron *QuantumNeuron) {
+	if len(neuron.Connections) == 0 {
+		return
+	}
+	z := complex(0, 0)
+	for _, conn := range neuron.Connections {
+		if len(conn) < 2 {
+			continue
+		}
+		sourceID := int(real(conn[0]))
+		if source, exists := bp.Neurons[sourceID]; exists {
+			z += conn[1] * complex(source.Value, 0)
+		} else if source, exists := bp.QuantumNeurons[sourceID]; exists {
+			z += conn[1] * complex(source.measuredValue(), 0)
+		}
+	}
+	neuron.Superposition = normalizeState([]complex128{1, z})
+}
+
+// processQuantumNeurons encodes the inputs of every quantum neuron and processes it, in ascending ID order.
+// A neuron measured earlier in the same pass as a partner of an entangled neuron is skipped, so entangled
+// neurons share one measurement; their input encoding is replaced by the Bell or GHZ state.
+func (bp *Blueprint) processQuantumNeurons() {
+	for _, neuron := range bp.QuantumNeurons {
+		neuron.IsMeasured = false
+	}
+	for _, id := range bp.sortedQuantumNeuronIDs() {
+		neuron := bp.QuantumNeurons[id]
+		if neuron.IsMeasured {
+			continue
+		}
+		bp.encodeQuantumInputs(neuron)
+		if err := bp.ProcessQuantumNeuron(neuron); err != nil && bp.Debug {
+			bp.debugf("Warning: %v", err)
+		}
+	}
+}
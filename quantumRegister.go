@@ -0,0 +1,180 @@
+package blueprint
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+)
+
+// QuantumRegister holds the joint state of several qubits as 2^n complex amplitudes, so a gate on one qubit acts
+// on the whole state and entangled qubits keep their correlations when measured.
+type QuantumRegister struct {
+	NeuronIDs  []int        // Qubit k belongs to neuron NeuronIDs[k] and is the k-th most significant bit of a basis index
+	Amplitudes []complex128 // Amplitude of each basis state, |0...0⟩ first
+}
+
+// Single-qubit gate matrices, applied to the amplitudes of |0⟩ and |1⟩.
+var (
+	hadamardGate = [2][2]complex128{{complex(1/math.Sqrt2, 0), complex(1/math.Sqrt2, 0)}, {complex(1/math.Sqrt2, 0), complex(-1/math.Sqrt2, 0)}}
+	pauliXGate   = [2][2]complex128{{0, 1}, {1, 0}}
+	pauliYGate   = [2][2]complex128{{0, -1i}, {1i, 0}}
+	pauliZGate   = [2][2]complex128{{1, 0}, {0, -1}}
+)
+
+// phaseGate returns the matrix of the phase gate, which multiplies the |1⟩ amplitude by e^(i angle).
+func phaseGate(angle float64) [2][2]complex128 {
+	return [2][2]complex128{{1, 0}, {0, cmplx.Exp(complex(0, angle))}}
+}
+
+// NewQuantumRegister returns a register of one qubit per neuron ID, all in |0⟩.
+func NewQuantumRegister(neuronIDs []int) *QuantumRegister {
+	register := &QuantumRegister{
+		NeuronIDs:  append([]int{}, neuronIDs...),
+		Amplitudes: make([]complex128, 1<<len(neuronIDs)),
+	}
+	register.Amplitudes[0] = 1
+	return register
+}
+
+// newQuantumRegister returns a register holding the product of the superpositions of the given quantum neurons.
+// A neuron without a two-amplitude superposition starts in |0⟩.
+func (bp *Blueprint) newQuantumRegister(neuronIDs []int) *QuantumRegister {
+	register := &QuantumRegister{NeuronIDs: append([]int{}, neuronIDs...), Amplitudes: []complex128{1}}
+	for _, id := range neuronIDs {
+		qubit := []complex128{1, 0}
+		if neuron, exists := bp.QuantumNeurons[id]; exists && len(neuron.Superposition) == 2 {
+			qubit = normalizeState(neuron.Superposition)
+		}
+		amplitudes := make([]complex128, 2*len(register.Amplitudes))
+		for i, amp := range register.Amplitudes {
+			amplitudes[2*i] = amp * qubit[0]
+			amplitudes[2*i+1] = amp * qubit[1]
+		}
+		register.Amplitudes = amplitudes
+	}
+	return register
+}
+
+// mask returns the bit of the basis index that holds the neuron's qubit.
+func (r *QuantumRegister) mask(neuronID int) (int, error) {
+	for k, id := range r.NeuronIDs {
+		if id == neuronID {
+			return 1 << (len(r.NeuronIDs) - 1 - k), nil
+		}
+	}
+	return 0, fmt.Errorf("neuron %d has no qubit in the register", neuronID)
+}
+
+// ApplyGate applies a single-qubit gate to the neuron's qubit.
+func (r *QuantumRegister) ApplyGate(neuronID int, gate [2][2]complex128) error {
+	mask, err := r.mask(neuronID)
+	if err != nil {
+		return err
+	}
+	for i := range r.Amplitudes {
+		if i&mask != 0 {
+			continue
+		}
+		a0, a1 := r.Amplitudes[i], r.Amplitudes[i|mask]
+		r.Amplitudes[i] = gate[0][0]*a0 + gate[0][1]*a1
+		r.Amplitudes[i|mask] = gate[1][0]*a0 + gate[1][1]*a1
+	}
+	return nil
+}
+
+// ApplyCNOT flips the target qubit in every basis state where the control qubit is |1⟩.
+func (r *QuantumRegister) ApplyCNOT(controlID, targetID int) error {
+	if controlID == targetID {
+		return fmt.Errorf("CNOT control and target are both neuron %d", controlID)
+	}
+	control, err := r.mask(controlID)
+	if err != nil {
+		return err
+	}
+	target, err := r.mask(targetID)
+	if err != nil {
+		return err
+	}
+	for i := range r.Amplitudes {
+		if i&control != 0 && i&target == 0 {
+			r.Amplitudes[i], r.Amplitudes[i|target] = r.Amplitudes[i|target], r.Amplitudes[i]
+		}
+	}
+	return nil
+}
+
+// Probability returns the probability of measuring the neuron's qubit as |1⟩.
+func (r *QuantumRegister) Probability(neuronID int) (float64, error) {
+	mask, err := r.mask(neuronID)
+	if err != nil {
+		return 0, err
+	}
+	total, one := 0.0, 0.0
+	for i, amp := range r.Amplitudes {
+		p := cmplx.Abs(amp) * cmplx.Abs(amp)
+		total += p
+		if i&mask != 0 {
+			one += p
+		}
+	}
+	if total == 0 {
+		return 0, nil
+	}
+	return one / total, nil
+}
+
+// Measure measures the neuron's qubit, returning 0 or 1, and collapses the joint state accordingly: every
+// basis state that disagrees with the outcome drops out, so qubits entangled with it collapse with it.
+func (r *QuantumRegister) Measure(neuronID int) (int, error) {
+	p, err := r.Probability(neuronID)
+	if err != nil {
+		return 0, err
+	}
+	mask, _ := r.mask(neuronID)
+	outcome := 0
+	if random.Float64() < p {
+		outcome = 1
+	}
+	for i := range r.Amplitudes {
+		if (i&mask != 0) != (outcome == 1) {
+			r.Amplitudes[i] = 0
+		}
+	}
+	r.Amplitudes = normalizeState(r.Amplitudes)
+	return outcome, nil
+}
+
+// QubitState returns the normalized amplitudes of |0⟩ and |1⟩ of the neuron's qubit when the joint state is a
+// product of that qubit and the rest of the register, and false when the qubit is entangled with the rest.
+func (r *QuantumRegister) QubitState(neuronID int) ([]complex128, bool) {
+	mask, err := r.mask(neuronID)
+	if err != nil {
+		return nil, false
+	}
+
+	// Every pair of amplitudes that differ only in this qubit must be a multiple of the same qubit state
+	var qubit []complex128
+	largest := 0.0
+	for i, amp := range r.Amplitudes {
+		if i&mask != 0 {
+			continue
+		}
+		pair := []complex128{amp, r.Amplitudes[i|mask]}
+		if norm := cmplx.Abs(pair[0])*cmplx.Abs(pair[0]) + cmplx.Abs(pair[1])*cmplx.Abs(pair[1]); norm > largest {
+			largest = norm
+			qubit = normalizeState(pair)
+		}
+	}
+	if qubit == nil {
+		return nil, false
+	}
+	for i, amp := range r.Amplitudes {
+		if i&mask != 0 {
+			continue
+		}
+		if cmplx.Abs(amp*qubit[1]-r.Amplitudes[i|mask]*qubit[0]) > 1e-9 {
+			return nil, false
+		}
+	}
+	return qubit, true
+}
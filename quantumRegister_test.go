@@ -0,0 +1,64 @@
+package blueprint
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+)
+
+func TestBellPairOutcomesAreCorrelated(t *testing.T) {
+	randomSource.Seed(1)
+	const trials = 1000
+	ones := 0
+	for i := 0; i < trials; i++ {
+		register := NewQuantumRegister([]int{5, 9})
+		if err := register.ApplyGate(5, hadamardGate); err != nil {
+			t.Fatal(err)
+		}
+		if err := register.ApplyCNOT(5, 9); err != nil {
+			t.Fatal(err)
+		}
+		if i == 0 {
+			want := []complex128{complex(1/math.Sqrt2, 0), 0, 0, complex(1/math.Sqrt2, 0)}
+			for k, amp := range register.Amplitudes {
+				if cmplx.Abs(amp-want[k]) > 1e-12 {
+					t.Fatalf("Bell state amplitudes = %v, want %v", register.Amplitudes, want)
+				}
+			}
+			if _, ok := register.QubitState(9); ok {
+				t.Error("QubitState reported an entangled qubit as a product state")
+			}
+		}
+
+		first, _ := register.Measure(5)
+		second, _ := register.Measure(9)
+		if first != second {
+			t.Fatalf("trial %d measured %d and %d, want equal outcomes", i, first, second)
+		}
+		ones += first
+	}
+	if ones < trials*4/10 || ones > trials*6/10 {
+		t.Errorf("measured 1 in %d of %d trials, want about half", ones, trials)
+	}
+}
+
+func TestBellEntangledNeuronsMeasureTogether(t *testing.T) {
+	randomSource.Seed(2)
+	bp := NewBlueprint()
+	bp.QuantumNeurons = map[int]*QuantumNeuron{
+		5: {ID: 5, Superposition: []complex128{1, 0}, Entanglements: []EntanglementInfo{{PartnerID: 9, Type: "Bell"}}},
+		9: {ID: 9, Superposition: []complex128{1, 0}},
+	}
+	outcomes := map[float64]int{}
+	for i := 0; i < 200; i++ {
+		bp.processQuantumNeurons()
+		first, second := bp.QuantumNeurons[5].measuredValue(), bp.QuantumNeurons[9].measuredValue()
+		if first != second {
+			t.Fatalf("pass %d measured %v and %v, want equal outcomes", i, first, second)
+		}
+		outcomes[first]++
+	}
+	if outcomes[0] == 0 || outcomes[1] == 0 {
+		t.Errorf("outcomes %v, want both 0 and 1", outcomes)
+	}
+}
@@ -0,0 +1,143 @@
+package blueprint
+
+import "encoding/json"
+
+// jsonComplex is a complex number encoded in JSON as {"real": ..., "imag": ...}, since encoding/json rejects complex128.
+type jsonComplex complex128
+
+func (c jsonComplex) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Real float64 `json:"real"`
+		Imag float64 `json:"imag"`
+	}{real(c), imag(c)})
+}
+
+func (c *jsonComplex) UnmarshalJSON(data []byte) error {
+	var pair struct {
+		Real float64 `json:"real"`
+		Imag float64 `json:"imag"`
+	}
+	if err := json.Unmarshal(data, &pair); err != nil {
+		return err
+	}
+	*c = jsonComplex(complex(pair.Real, pair.Imag))
+	return nil
+}
+
+// quantumNeuronJSON is the JSON form of a QuantumNeuron. It carries "type": "quantum" so LoadNeurons recognizes it.
+type quantumNeuronJSON struct {
+	ID                  int                `json:"id"`
+	Type                string             `json:"type"`
+	Amplitude           jsonComplex        `json:"amplitude"`
+	Phase               float64            `json:"phase"`
+	QuantumGates        []quantumGateJSON  `json:"gates"`
+	Entanglements       []EntanglementInfo `json:"entanglements"`
+	Superposition       []jsonComplex      `json:"superposition"`
+	Connections         [][]jsonComplex    `json:"connections"`
+	CNOTTarget          int                `json:"cnot_target,omitempty"`
+	EntanglementCreated bool               `json:"entanglement_created"`
+	IsEntangled         bool               `json:"is_entangled"`
+	IsMeasured          bool               `json:"is_measured"`
+}
+
+type quantumGateJSON struct {
+	Type   string          `json:"type"`
+	Matrix [][]jsonComplex `json:"matrix,omitempty"`
+	Angle  float64         `json:"angle,omitempty"`
+}
+
+// MarshalJSON encodes the quantum neuron with every complex number written as a {"real", "imag"} pair.
+func (neuron *QuantumNeuron) MarshalJSON() ([]byte, error) {
+	encoded := quantumNeuronJSON{
+		ID:                  neuron.ID,
+		Type:                "quantum",
+		Amplitude:           jsonComplex(neuron.QuantumState.Amplitude),
+		Phase:               neuron.QuantumState.Phase,
+		Entanglements:       neuron.Entanglements,
+		Superposition:       toJSONComplexes(neuron.Superposition),
+		Connections:         toJSONComplexMatrix(neuron.Connections),
+		CNOTTarget:          neuron.CNOTTarget,
+		EntanglementCreated: neuron.EntanglementCreated,
+		IsEntangled:         neuron.IsEntangled,
+		IsMeasured:          neuron.IsMeasured,
+	}
+	for _, gate := range neuron.QuantumGates {
+		encoded.QuantumGates = append(encoded.QuantumGates, quantumGateJSON{
+			Type:   gate.Type,
+			Matrix: toJSONComplexMatrix(gate.Matrix),
+			Angle:  gate.Angle,
+		})
+	}
+	return json.Marshal(encoded)
+}
+
+// UnmarshalJSON decodes a quantum neuron written by MarshalJSON.
+func (neuron *QuantumNeuron) UnmarshalJSON(data []byte) error {
+	var decoded quantumNeuronJSON
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	*neuron = QuantumNeuron{
+		ID:                  decoded.ID,
+		QuantumState:        QuantumState{Amplitude: complex128(decoded.Amplitude), Phase: decoded.Phase},
+		Entanglements:       decoded.Entanglements,
+		Superposition:       fromJSONComplexes(decoded.Superposition),
+		Connections:         fromJSONComplexMatrix(decoded.Connections),
+		CNOTTarget:          decoded.CNOTTarget,
+		EntanglementCreated: decoded.EntanglementCreated,
+		IsEntangled:         decoded.IsEntangled,
+		IsMeasured:          decoded.IsMeasured,
+	}
+	for _, gate := range decoded.QuantumGates {
+		neuron.QuantumGates = append(neuron.QuantumGates, QuantumGate{
+			Type:   gate.Type,
+			Matrix: fromJSONComplexMatrix(gate.Matrix),
+			Angle:  gate.Angle,
+		})
+	}
+	return nil
+}
+
+func toJSONComplexes(s []complex128) []jsonComplex {
+	if s == nil {
+		return nil
+	}
+	c := make([]jsonComplex, len(s))
+	for i, v := range s {
+		c[i] = jsonComplex(v)
+	}
+	return c
+}
+
+func fromJSONComplexes(s []jsonComplex) []complex128 {
+	if s == nil {
+		return nil
+	}
+	c := make([]complex128, len(s))
+	for i, v := range s {
+		c[i] = complex128(v)
+	}
+	return c
+}
+
+func toJSONComplexMatrix(m [][]complex128) [][]jsonComplex {
+	if m == nil {
+		return nil
+	}
+	c := make([][]jsonComplex, len(m))
+	for i, row := range m {
+		c[i] = toJSONComplexes(row)
+	}
+	return c
+}
+
+func fromJSONComplexMatrix(m [][]jsonComplex) [][]complex128 {
+	if m == nil {
+		return nil
+	}
+	c := make([][]complex128, len(m))
+	for i, row := range m {
+		c[i] = fromJSONComplexes(row)
+	}
+	return c
+}
@@ -0,0 +1,72 @@
+package blueprint
+
+import (
+	"encoding/json"
+	"math"
+	"math/cmplx"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// bellPairBlueprint has quantum neurons 5 and 9 entangled as a Bell pair, neuron 5 holding (|0⟩ + e^(iπ/4)|1⟩)/√2.
+func bellPairBlueprint() *Blueprint {
+	h := 1 / math.Sqrt2
+	bp := NewBlueprint()
+	bp.QuantumNeurons = map[int]*QuantumNeuron{
+		5: {
+			ID:            5,
+			QuantumState:  QuantumState{Amplitude: complex(h, -h), Phase: math.Pi / 4},
+			Superposition: []complex128{complex(h, 0), cmplx.Rect(h, math.Pi/4)},
+			Entanglements: []EntanglementInfo{{PartnerID: 9, Type: "Bell", Strength: 1}},
+			Connections:   [][]complex128{{1, complex(0.5, -0.25)}},
+			QuantumGates:  []QuantumGate{{Type: "Phase", Angle: math.Pi / 3}, {Type: "Custom", Matrix: [][]complex128{{0, 1i}, {-1i, 0}}}},
+			IsEntangled:   true, EntanglementCreated: true,
+		},
+		9: {ID: 9, Superposition: []complex128{complex(h, 0), complex(0, h)}, IsEntangled: true, CNOTTarget: 5},
+	}
+	return bp
+}
+
+func TestQuantumNeuronsSurviveSaveAndLoad(t *testing.T) {
+	source := bellPairBlueprint()
+	path := filepath.Join(t.TempDir(), "bell.json")
+	if err := source.SaveToJSON(path); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var loaded Blueprint
+	if err := loaded.DeserializesFromJSON(string(data)); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(loaded.QuantumNeurons, source.QuantumNeurons) {
+		t.Fatalf("loaded quantum neurons differ:\n got %+v\nwant %+v", loaded.QuantumNeurons, source.QuantumNeurons)
+	}
+
+	// LoadNeurons recognizes the "quantum" type written by MarshalJSON
+	neurons, err := json.Marshal([]*QuantumNeuron{source.QuantumNeurons[5], source.QuantumNeurons[9]})
+	if err != nil {
+		t.Fatal(err)
+	}
+	bp := NewBlueprint()
+	if err := bp.LoadNeurons(string(neurons)); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(bp.QuantumNeurons, source.QuantumNeurons) {
+		t.Fatalf("LoadNeurons gave %+v, want %+v", bp.QuantumNeurons, source.QuantumNeurons)
+	}
+
+	// The loaded pair is still entangled
+	randomSource.Seed(1)
+	for i := 0; i < 50; i++ {
+		loaded.processQuantumNeurons()
+		if first, second := loaded.QuantumNeurons[5].measuredValue(), loaded.QuantumNeurons[9].measuredValue(); first != second {
+			t.Fatalf("loaded Bell pair measured %v and %v", first, second)
+		}
+	}
+}
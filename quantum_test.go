@@ -0,0 +1,184 @@
+package blueprint
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+)
+
+// mixedOutputBlueprint has a dense output neuron reading input 1 and a quantum output neuron prepared in |0⟩
+// that applies the given gates before it is measured.
+func mixedOutputBlueprint(gates ...QuantumGate) *Blueprint {
+	bp := NewBlueprint()
+	bp.AddInputNeurons([]int{1})
+	bp.Neurons[3] = &Neuron{ID: 3, Type: "dense", Activation: "linear", Connections: [][]float64{{1, 1}}}
+	bp.QuantumNeurons = map[int]*QuantumNeuron{
+		10: {ID: 10, Superposition: []complex128{1, 0}, QuantumGates: gates},
+	}
+	bp.AddOutputNodes([]int{3, 10})
+	return bp
+}
+
+func TestQuantumOutputNeuronInfluencesOutputs(t *testing.T) {
+	inputs := map[int]float64{1: 0}
+
+	ground := mixedOutputBlueprint()
+	ground.RunNetwork(inputs, 1)
+	outputs := ground.GetOutputs()
+	if len(outputs) != 2 {
+		t.Fatalf("got outputs %v, want both the dense and the quantum neuron", outputs)
+	}
+	// |0⟩ measures 0, like the dense neuron's value, so both share the probability
+	if math.Abs(outputs[3]-0.5) > 1e-9 || math.Abs(outputs[10]-0.5) > 1e-9 {
+		t.Errorf("with the quantum neuron in |0⟩ got outputs %v, want 0.5 each", outputs)
+	}
+
+	flipped := mixedOutputBlueprint(QuantumGate{Type: "PauliX"})
+	flipped.RunNetwork(inputs, 1)
+	outputs = flipped.GetOutputs()
+	// PauliX turns |0⟩ into |1⟩, which measures 1
+	want := math.Exp(1) / (1 + math.Exp(1))
+	if math.Abs(outputs[10]-want) > 1e-9 || math.Abs(outputs[3]-(1-want)) > 1e-9 {
+		t.Errorf("with the quantum neuron in |1⟩ got outputs %v, want %.4f for neuron 10 and %.4f for neuron 3",
+			outputs, want, 1-want)
+	}
+	if measured := flipped.QuantumNeurons[10].measuredValue(); measured != 1 {
+		t.Errorf("softmax changed the quantum neuron's measurement to %v", measured)
+	}
+}
+
+// sameState reports whether two amplitude vectors are equal within floating-point error.
+func sameState(got, want []complex128) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range want {
+		if cmplx.Abs(got[i]-want[i]) > 1e-12 {
+			return false
+		}
+	}
+	return true
+}
+
+func TestPauliAndPhaseGates(t *testing.T) {
+	h := complex(1/math.Sqrt2, 0)
+	cases := []struct {
+		name string
+		got  []complex128
+		want []complex128
+	}{
+		{"PauliZ on |+⟩", applyPauliZ([]complex128{h, h}), []complex128{h, -h}},
+		{"PauliZ on |0⟩", applyPauliZ([]complex128{1, 0}), []complex128{1, 0}},
+		{"PauliY on |0⟩", applyPauliY([]complex128{1, 0}), []complex128{0, 1i}},
+		{"PauliY on |1⟩", applyPauliY([]complex128{0, 1}), []complex128{-1i, 0}},
+		{"PauliY on unnormalized |0⟩", applyPauliY([]complex128{2, 0}), []complex128{0, 1i}},
+		{"Phase π/2 on |+⟩", applyPhase([]complex128{h, h}, math.Pi/2), []complex128{h, 1i * h}},
+		{"Phase π on |+⟩", applyPhase([]complex128{h, h}, math.Pi), []complex128{h, -h}},
+	}
+	for _, c := range cases {
+		if !sameState(c.got, c.want) {
+			t.Errorf("%s = %v, want %v", c.name, c.got, c.want)
+		}
+	}
+
+	// The gates are applied by ProcessQuantumNeuron, both on a neuron's own superposition and in a register
+	bp := mixedOutputBlueprint(QuantumGate{Type: "Hadamard"}, QuantumGate{Type: "PauliZ"})
+	if err := bp.ProcessQuantumNeuron(bp.QuantumNeurons[10]); err != nil {
+		t.Fatal(err)
+	}
+	if got := bp.QuantumNeurons[10].Superposition; !sameState(got, []complex128{h, -h}) {
+		t.Errorf("Hadamard then PauliZ gave %v, want %v", got, []complex128{h, -h})
+	}
+	bp = mixedOutputBlueprint(QuantumGate{Type: "PauliY"})
+	if err := bp.ProcessQuantumNeuron(bp.QuantumNeurons[10]); err != nil {
+		t.Fatal(err)
+	}
+	if got := bp.QuantumNeurons[10].Superposition; !sameState(got, []complex128{0, 1i}) {
+		t.Errorf("PauliY on |0⟩ gave %v, want i|1⟩", got)
+	}
+
+	register := NewQuantumRegister([]int{1, 2})
+	register.ApplyGate(2, pauliYGate)
+	if !sameState(register.Amplitudes, []complex128{0, 1i, 0, 0}) {
+		t.Errorf("PauliY on the second qubit of |00⟩ gave %v, want i|01⟩", register.Amplitudes)
+	}
+	register.ApplyGate(2, pauliZGate)
+	if !sameState(register.Amplitudes, []complex128{0, -1i, 0, 0}) {
+		t.Errorf("PauliZ on i|01⟩ gave %v, want -i|01⟩", register.Amplitudes)
+	}
+}
+
+func TestCNOTTargetsNonConsecutiveNeuron(t *testing.T) {
+	// cnotBlueprint has control 3 prepared in the given state, a bystander 4 and the CNOT target 7 in |0⟩
+	cnotBlueprint := func(control []complex128, gates ...QuantumGate) *Blueprint {
+		bp := NewBlueprint()
+		bp.QuantumNeurons = map[int]*QuantumNeuron{
+			3: {ID: 3, Superposition: control, CNOTTarget: 7, QuantumGates: append(gates, QuantumGate{Type: "CNOT"})},
+			4: {ID: 4, Superposition: []complex128{1, 0}},
+			7: {ID: 7, Superposition: []complex128{1, 0}},
+		}
+		return bp
+	}
+
+	for _, c := range []struct {
+		control, target []complex128
+	}{
+		{[]complex128{0, 1}, []complex128{0, 1}},
+		{[]complex128{1, 0}, []complex128{1, 0}},
+	} {
+		bp := cnotBlueprint(c.control)
+		if err := bp.ProcessQuantumNeuron(bp.QuantumNeurons[3]); err != nil {
+			t.Fatal(err)
+		}
+		if got := bp.QuantumNeurons[7].Superposition; !sameState(got, c.target) {
+			t.Errorf("control %v left target 7 in %v, want %v", c.control, got, c.target)
+		}
+		if got := bp.QuantumNeurons[4].Superposition; !sameState(got, []complex128{1, 0}) {
+			t.Errorf("CNOT changed neuron 4, which is not its target, to %v", got)
+		}
+	}
+
+	// Hadamard then CNOT entangles the pair, so the target always follows the control's outcome
+	randomSource.Seed(1)
+	outcomes := map[float64]int{}
+	for i := 0; i < 100; i++ {
+		bp := cnotBlueprint([]complex128{1, 0}, QuantumGate{Type: "Hadamard"})
+		if err := bp.ProcessQuantumNeuron(bp.QuantumNeurons[3]); err != nil {
+			t.Fatal(err)
+		}
+		measured := bp.QuantumNeurons[3].measuredValue()
+		want := []complex128{1 - complex(measured, 0), complex(measured, 0)}
+		if got := bp.QuantumNeurons[7].Superposition; !sameState(got, want) {
+			t.Fatalf("control measured %v but target 7 is in %v", measured, got)
+		}
+		outcomes[measured]++
+	}
+	if outcomes[0] == 0 || outcomes[1] == 0 {
+		t.Errorf("control outcomes %v, want both 0 and 1", outcomes)
+	}
+
+	bp := cnotBlueprint([]complex128{0, 1})
+	bp.QuantumNeurons[3].CNOTTarget = 8
+	if err := bp.ProcessQuantumNeuron(bp.QuantumNeurons[3]); err == nil {
+		t.Error("CNOT with a missing target returned no error")
+	}
+	if bp.QuantumNeurons[3].IsMeasured || !sameState(bp.QuantumNeurons[3].Superposition, []complex128{0, 1}) {
+		t.Error("CNOT with a missing target changed the control neuron")
+	}
+}
+
+func TestCompileRejectsQuantumNeurons(t *testing.T) {
+	bp := mixedOutputBlueprint()
+	// A classical neuron fed by the quantum one gets its measurement in Forward, which a plan cannot reproduce
+	bp.Neurons[4] = &Neuron{ID: 4, Type: "dense", Activation: "linear", Connections: [][]float64{{10, 1}}}
+	if _, err := bp.Compile(); err == nil {
+		t.Error("Compile accepted a blueprint with quantum neurons")
+	}
+
+	delete(bp.Neurons, 4)
+	bp.QuantumNeurons = nil
+	bp.OutputNodes = []int{3}
+	if _, err := bp.Compile(); err != nil {
+		t.Errorf("Compile rejected the blueprint once its quantum neurons were removed: %v", err)
+	}
+}
@@ -0,0 +1,48 @@
+package blueprint
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// lockedSource is a rand.Source that can be shared by the parallel NAS workers and reseeded at any time.
+type lockedSource struct {
+	mu  sync.Mutex
+	src rand.Source64
+}
+
+func (s *lockedSource) Int63() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.src.Int63()
+}
+
+func (s *lockedSource) Uint64() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.src.Uint64()
+}
+
+func (s *lockedSource) Seed(seed int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.src.Seed(seed)
+}
+
+// randomSource is seeded from the clock until SetRandomSeed is called.
+var randomSource = &lockedSource{src: rand.NewSource(time.Now().UnixNano()).(rand.Source64)}
+
+// random is the generator used by every stochastic routine of the package: weight initialization,
+// mutations, hill climbing, connection formation, NAS and evolutionary training.
+var random = rand.New(randomSource)
+
+// SetRandomSeed reseeds the package-wide generator behind weight initialization, mutations, hill climbing,
+// connection formation, NAS and evolutionary training, so a run can be reproduced. Two runs started after the
+// same seed on the same model and sessions produce identical blueprints, provided they run sequentially:
+// routines with several workers, such as ParallelNAS, TryAddConnections and LearnOneDataItemAtATime, draw
+// from the generator in whatever order the workers are scheduled. Without a call the generator is seeded from
+// the clock. The seed is shared by all blueprints.
+func (bp *Blueprint) SetRandomSeed(seed int64) {
+	randomSource.Seed(seed)
+}
@@ -0,0 +1,26 @@
+package blueprint
+
+// L2Penalty returns the sum of the squared connection weights of the model.
+// Biases and the weights of LSTM gates, CNN kernels and attention are not included.
+func (bp *Blueprint) L2Penalty() float64 {
+	total := 0.0
+	for _, neuron := range bp.Neurons {
+		for i := 0; i < neuron.numConnections(); i++ {
+			_, weight := neuron.connection(i)
+			total += weight * weight
+		}
+	}
+	return total
+}
+
+// EvaluateModelPerformanceRegularized returns the metrics of EvaluateModelPerformance followed by a regularized
+// score: the average of the exact, generous and forgiveness accuracies as percentages, the default fitness of
+// EvolutionaryTrain, minus l2Lambda times L2Penalty. Of two models with the same accuracy, the one with smaller weights scores higher.
+// The penalty is a sum over every connection, so it grows with the number of connections as well as their
+// magnitude: with weights of typical size w a network with n connections is penalized about l2Lambda*n*w²
+// points, and l2Lambda should shrink as the networks being compared grow.
+func (bp *Blueprint) EvaluateModelPerformanceRegularized(sessions []Session, l2Lambda float64) (float64, float64, float64, int, float64, int, float64) {
+	exact, generous, forgiveness, exactErrors, generousError, forgivenessErrors := bp.EvaluateModelPerformance(sessions)
+	score := evolutionConfig{}.fitness(exact, generous, forgiveness) - l2Lambda*bp.L2Penalty()
+	return exact, generous, forgiveness, exactErrors, generousError, forgivenessErrors, score
+}
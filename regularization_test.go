@@ -0,0 +1,32 @@
+package blueprint
+
+import "testing"
+
+func TestRegularizedScorePrefersSmallerWeights(t *testing.T) {
+	sessions := []Session{
+		{InputVariables: map[int]float64{1: 1}, ExpectedOutput: map[int]float64{2: 1, 3: 0}, Timesteps: 1},
+		{InputVariables: map[int]float64{1: -1}, ExpectedOutput: map[int]float64{2: 0, 3: 1}, Timesteps: 1},
+	}
+	small := evalTestBlueprint()
+	// The same network with a large weight into a neuron no output reads, so its outputs are unchanged
+	large := evalTestBlueprint()
+	large.Neurons[4] = &Neuron{ID: 4, Type: "dense", Activation: "linear", Connections: [][]float64{{1, 10}}}
+
+	smallExact, smallGenerous, smallForgiveness, _, _, _, smallScore := small.EvaluateModelPerformanceRegularized(sessions, 0.01)
+	largeExact, largeGenerous, largeForgiveness, _, _, _, largeScore := large.EvaluateModelPerformanceRegularized(sessions, 0.01)
+	if smallExact != largeExact || smallGenerous != largeGenerous || smallForgiveness != largeForgiveness {
+		t.Fatalf("accuracies differ: %v %v %v against %v %v %v",
+			smallExact, smallGenerous, smallForgiveness, largeExact, largeGenerous, largeForgiveness)
+	}
+	if smallScore <= largeScore {
+		t.Errorf("the smaller-weight model scored %v, not above the larger-weight model's %v", smallScore, largeScore)
+	}
+	if want := 0.01 * 100; smallScore-largeScore < want-1e-9 || smallScore-largeScore > want+1e-9 {
+		t.Errorf("scores differ by %v, want l2Lambda times the extra penalty, %v", smallScore-largeScore, want)
+	}
+
+	_, _, _, _, _, _, unpenalized := small.EvaluateModelPerformanceRegularized(sessions, 0)
+	if want := (smallExact + smallGenerous*100 + smallForgiveness) / 3; unpenalized != want {
+		t.Errorf("score without penalty is %v, want the fitness %v", unpenalized, want)
+	}
+}
@@ -0,0 +1,144 @@
+package blueprint
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"math"
+)
+
+// serializationTolerance is the largest weight drift accepted from a JSON round-trip.
+const serializationTolerance = 1e-12
+
+// gobBlueprint holds the serializable part of a Blueprint for gob encoding,
+// which cannot handle the function values stored in ScalarActivationMap.
+type gobBlueprint struct {
+	Neurons        map[int]*Neuron
+	QuantumNeurons map[int]*QuantumNeuron
+	InputNodes     []int
+	OutputNodes    []int
+}
+
+// SerializeToGob serializes the Blueprint to gob's binary format, which preserves every float bit-for-bit.
+func (bp *Blueprint) SerializeToGob() ([]byte, error) {
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(gobBlueprint{
+		Neurons:        bp.Neurons,
+		QuantumNeurons: bp.QuantumNeurons,
+		InputNodes:     bp.InputNodes,
+		OutputNodes:    bp.OutputNodes,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize Blueprint to gob: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// DeserializeFromGob restores the Blueprint from data produced by SerializeToGob.
+func (bp *Blueprint) DeserializeFromGob(data []byte) error {
+	var decoded gobBlueprint
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&decoded); err != nil {
+		return fmt.Errorf("failed to deserialize Blueprint from gob: %w", err)
+	}
+
+	bp.invalidateCompiled()
+	bp.Neurons = decoded.Neurons
+	bp.QuantumNeurons = decoded.QuantumNeurons
+	bp.InputNodes = decoded.InputNodes
+	bp.OutputNodes = decoded.OutputNodes
+	if bp.Neurons == nil {
+		bp.Neurons = make(map[int]*Neuron)
+	}
+	if bp.QuantumNeurons == nil {
+		bp.QuantumNeurons = make(map[int]*QuantumNeuron)
+	}
+	if bp.ScalarActivationMap == nil {
+		bp.InitializeActivationFunctions()
+	}
+	return nil
+}
+
+// SerializationFidelity round-trips the Blueprint through JSON and returns the largest absolute
+// difference introduced in any bias or weight. Values that do not survive the round-trip at all
+// count as their full magnitude, and a model that cannot be serialized returns +Inf.
+// A warning recommending the gob path is printed when the drift exceeds the tolerance.
+func (bp *Blueprint) SerializationFidelity() float64 {
+	data, err := bp.SerializeToJSON()
+	if err != nil {
+		bp.warnf("Warning: Blueprint cannot be serialized to JSON (%v). Use SerializeToGob instead.", err)
+		return math.Inf(1)
+	}
+
+	restored := &Blueprint{}
+	if err := restored.DeserializesFromJSON(data); err != nil {
+		bp.warnf("Warning: Blueprint JSON cannot be deserialized (%v). Use SerializeToGob instead.", err)
+		return math.Inf(1)
+	}
+
+	maxDiff := 0.0
+	for id, neuron := range bp.Neurons {
+		maxDiff = math.Max(maxDiff, neuronWeightDifference(neuron, restored.Neurons[id]))
+	}
+
+	if maxDiff > serializationTolerance {
+		bp.warnf("Warning: JSON round-trip changed weights by up to %g. Use SerializeToGob for lossless storage.", maxDiff)
+	}
+	return maxDiff
+}
+
+// neuronWeightDifference returns the largest absolute difference between the trainable values of two neurons.
+// A nil or shorter restored neuron is compared against zero for every missing value.
+func neuronWeightDifference(original, restored *Neuron) float64 {
+	if restored == nil {
+		restored = &Neuron{}
+	}
+
+	maxDiff := math.Abs(original.Bias - restored.Bias)
+	if original.Type == "rnn" {
+		maxDiff = math.Max(maxDiff, math.Abs(original.RecurrentWeight-restored.RecurrentWeight))
+	}
+	compare := func(a, b []float64) {
+		for i, v := range a {
+			other := 0.0
+			if i < len(b) {
+				other = b[i]
+			}
+			maxDiff = math.Max(maxDiff, math.Abs(v-other))
+		}
+	}
+
+	// Low-precision models keep their connections in Connections32, so both storages are compared
+	for i := 0; i < original.numConnections(); i++ {
+		sourceID, weight := original.connection(i)
+		var other []float64
+		if i < restored.numConnections() {
+			otherID, otherWeight := restored.connection(i)
+			other = []float64{float64(otherID), otherWeight}
+		}
+		compare([]float64{float64(sourceID), weight}, other)
+	}
+	for gate, weights := range original.GateWeights {
+		compare(weights, restored.GateWeights[gate])
+	}
+	for i, kernel := range original.Kernels {
+		var other []float64
+		if i < len(restored.Kernels) {
+			other = restored.Kernels[i]
+		}
+		compare(kernel, other)
+	}
+	compare(original.AttentionWeights, restored.AttentionWeights)
+
+	if original.BatchNormParams != nil {
+		params := BatchNormParams{}
+		if restored.BatchNormParams != nil {
+			params = *restored.BatchNormParams
+		}
+		compare(
+			[]float64{original.BatchNormParams.Gamma, original.BatchNormParams.Beta, original.BatchNormParams.Mean, original.BatchNormParams.Var, original.BatchNormParams.Momentum},
+			[]float64{params.Gamma, params.Beta, params.Mean, params.Var, params.Momentum},
+		)
+	}
+
+	return maxDiff
+}
@@ -3,20 +3,19 @@ package blueprint
 
 import (
 	"fmt"
-	"math/rand"
 	"runtime"
 	"sync"
 )
 
 // NeuronAdditionAttempt holds the result of a neuron or connection modification attempt.
 type NeuronAdditionAttempt struct {
-	ModificationType string  // "insert_neuron", "add_connection", "modify_activation", "remove_connection", "adjust_weight"
-	NeuronType       string  // Applicable if ModificationType is "insert_neuron"
-	SourceID         int     // Applicable if ModificationType is "add_connection", "remove_connection", or "adjust_weight"
-	TargetID         int     // Applicable if ModificationType is "add_connection" or "remove_connection"
-	Weight           float64 // Applicable if ModificationType is "add_connection" or "adjust_weight"
-	Activation       string  // Applicable if ModificationType is "modify_activation"
-	ModelJSON        string
+	ModificationType string     // "insert_neuron", "add_connection", "modify_activation", "remove_connection", "adjust_weight"
+	NeuronType       string     // Applicable if ModificationType is "insert_neuron"
+	SourceID         int        // Applicable if ModificationType is "add_connection", "remove_connection", or "adjust_weight"
+	TargetID         int        // Applicable if ModificationType is "add_connection" or "remove_connection"
+	Weight           float64    // Applicable if ModificationType is "add_connection" or "adjust_weight"
+	Activation       string     // Applicable if ModificationType is "modify_activation"
+	Model            *Blueprint // The modified model
 	ExactAcc         float64
 	GenerousAcc      float64
 	ForgiveAcc       float64
@@ -32,13 +31,13 @@ func (bp *Blueprint) LearnOneDataItemAtATime(
 	neuronTypes []string,
 	batchSize int, // Number of sessions to process at a time
 ) {
-	fmt.Println("Starting LearnOneDataItemAtATime phase...")
+	bp.infof("Starting LearnOneDataItemAtATime phase...")
 
 	// Set default batch size if not specified or invalid
 	if batchSize <= 0 {
 		batchSize = 5
 	}
-	fmt.Printf("Batch size set to %d sessions.\n", batchSize)
+	bp.infof("Batch size set to %d sessions.", batchSize)
 
 	// Evaluate initial overall performance
 	initialExact, initialGenerous, initialForgive, _, _, _ :=
@@ -50,7 +49,10 @@ func (bp *Blueprint) LearnOneDataItemAtATime(
 		numWorkers = 1
 	}
 
-	fmt.Printf("Utilizing %d worker(s) for modification attempts.\n", numWorkers)
+	bp.infof("Utilizing %d worker(s) for modification attempts.", numWorkers)
+
+	// Modification types are chosen by a bandit that learns which ones help this model
+	modBandit := bp.modificationTypeBandit()
 
 	// Process sessions in batches
 	for i := 0; i < len(sessions); i += batchSize {
@@ -61,11 +63,18 @@ func (bp *Blueprint) LearnOneDataItemAtATime(
 		batch := sessions[i:end]
 		batchIdx := i/batchSize + 1
 
-		fmt.Printf("\nProcessing Batch %d/%d...\n", batchIdx, (len(sessions)+batchSize-1)/batchSize)
+		bp.infof("Processing Batch %d/%d...", batchIdx, (len(sessions)+batchSize-1)/batchSize)
 
 		// Channel to collect beneficial attempts for this batch
 		attemptCh := make(chan NeuronAdditionAttempt, len(batch)*maxAttemptsPerSession*5) // Adjust buffer as needed
 
+		// Score the unmodified model on each session so modifications can be rewarded
+		baselines := make([]EvaluationResult, len(batch))
+		for j, sess := range batch {
+			baseExact, baseGenerous, baseForgive, _, _, _ := bp.EvaluateModelPerformance([]Session{sess})
+			baselines[j] = EvaluationResult{ExactAccuracy: baseExact, GenerousAccuracy: baseGenerous, ForgivenessAccuracy: baseForgive}
+		}
+
 		// WaitGroup for worker goroutines within the batch
 		var wgWorkers sync.WaitGroup
 
@@ -74,10 +83,10 @@ func (bp *Blueprint) LearnOneDataItemAtATime(
 			wgWorkers.Add(1)
 			go func(workerID int) {
 				defer wgWorkers.Done()
-				for _, sess := range batch {
+				for j, sess := range batch {
 					for attempt := 0; attempt < maxAttemptsPerSession; attempt++ {
 						// Perform random modification and evaluate the improvement
-						attemptResult := bp.performRandomModification(sess, neuronTypes)
+						attemptResult := bp.performRandomModification(sess, neuronTypes, modBandit, baselines[j])
 
 						// Send attempt to channel if it has improvement
 						if attemptResult != nil {
@@ -116,13 +125,7 @@ func (bp *Blueprint) LearnOneDataItemAtATime(
 
 		// Update the model if the best attempt improves the performance
 		if bestBatchAttempt != nil {
-			// Create a new Blueprint from the best batch model
-			newBlueprint := &Blueprint{}
-			err := newBlueprint.DeserializesFromJSON(bestBatchAttempt.ModelJSON)
-			if err != nil {
-				fmt.Printf("Batch %d: Error deserializing best batch model: %v\n", batchIdx, err)
-				continue
-			}
+			newBlueprint := bestBatchAttempt.Model
 
 			// Re-evaluate the overall model
 			newExact, newGenerous, newForgive, _, _, _ :=
@@ -131,43 +134,46 @@ func (bp *Blueprint) LearnOneDataItemAtATime(
 			// Commit the update and adjust initial metrics
 			if validateImprovement(newExact, newGenerous, newForgive, initialExact, initialGenerous, initialForgive) {
 				*bp = *newBlueprint // Update the main model with the new blueprint
+				bp.modBandit = modBandit
 				initialExact, initialGenerous, initialForgive = newExact, newGenerous, newForgive
 
-				fmt.Printf("\nBatch %d: Model improved! Updating the main model.\n", batchIdx)
-				fmt.Printf("New Accuracies - Exact: %.6f%%, Generous: %.6f%%, Forgiveness: %.6f%%\n",
+				bp.infof("Batch %d: Model improved! Updating the main model.", batchIdx)
+				bp.infof("New Accuracies - Exact: %.6f%%, Generous: %.6f%%, Forgiveness: %.6f%%",
 					newExact, newGenerous, newForgive)
 			} else {
-				fmt.Printf("\nBatch %d: No beneficial modifications were found.\n", batchIdx)
+				bp.infof("Batch %d: No beneficial modifications were found.", batchIdx)
 			}
 		}
 
 	}
 
-	fmt.Println("LearnOneDataItemAtATime phase completed.")
+	bp.infof("LearnOneDataItemAtATime phase completed.")
+	for _, stat := range modBandit.Stats() {
+		bp.infof("Modification %s: %d attempts, %.1f%% improved the session.", stat.Arm, stat.Pulls, stat.MeanReward*100)
+	}
 }
 
-// randomModificationType randomly selects a modification type.
-func randomModificationType() string {
-	modTypes := []string{
-		"insert_neuron",
-		"add_connection",
-		"modify_activation",
-		"remove_connection",
-		"adjust_weight",
-	}
-	return modTypes[rand.Intn(len(modTypes))]
+// modificationTypes are the modifications LearnOneDataItemAtATime can attempt.
+var modificationTypes = []string{
+	"insert_neuron",
+	"add_connection",
+	"modify_activation",
+	"remove_connection",
+	"adjust_weight",
 }
 
-// randomActivationFunction selects a random activation function.
-func randomActivationFunction() string {
-	activations := []string{
-		"relu",
-		"sigmoid",
-		"tanh",
-		"leaky_relu",
-		"softmax",
+// modificationTypeBandit returns the blueprint's modification type bandit, creating it if needed.
+func (bp *Blueprint) modificationTypeBandit() *Bandit {
+	if bp.modBandit == nil {
+		bp.modBandit = NewBandit(modificationTypes)
 	}
-	return activations[rand.Intn(len(activations))]
+	return bp.modBandit
+}
+
+// ModificationStats returns how often each modification type tried by LearnOneDataItemAtATime
+// improved the model on the session it was tried on. MeanReward is the improvement rate.
+func (bp *Blueprint) ModificationStats() []ArmStats {
+	return bp.modificationTypeBandit().Stats()
 }
 
 // calculateImprovement ensures at least one metric improves without others degrading.
@@ -185,93 +191,79 @@ func validateImprovement(newExact, newGenerous, newForgive, initialExact, initia
 // Returns -1 if no hidden neuron is found.
 func (bp *Blueprint) getRandomHiddenNeuron() int {
 	hiddenNeurons := []int{}
-	for id, neuron := range bp.Neurons {
-		if neuron.Type != "input" && neuron.Type != "output" {
+	for _, id := range bp.getAllNeuronIDs() {
+		if neuron := bp.Neurons[id]; neuron.Type != "input" && neuron.Type != "output" {
 			hiddenNeurons = append(hiddenNeurons, id)
 		}
 	}
 	if len(hiddenNeurons) == 0 {
 		return -1
 	}
-	return hiddenNeurons[rand.Intn(len(hiddenNeurons))]
+	return hiddenNeurons[random.Intn(len(hiddenNeurons))]
 }
 
 // getRandomExistingConnectionPair selects a random existing connection pair.
+// Connections are stored on their target, so the pair is read as (conn[0], owning neuron).
 // Returns -1, -1 if no existing connection is found.
 func (bp *Blueprint) getRandomExistingConnectionPair() (int, int) {
-	existingConnections := [][]float64{}
-	for sourceID, neuron := range bp.Neurons {
-		for _, conn := range neuron.Connections {
-			targetID := int(conn[0])
-			existingConnections = append(existingConnections, []float64{float64(sourceID), float64(targetID)})
+	existingConnections := [][2]int{}
+	for _, targetID := range bp.getAllNeuronIDs() {
+		neuron := bp.Neurons[targetID]
+		for i := 0; i < neuron.numConnections(); i++ {
+			sourceID, _ := neuron.connection(i)
+			existingConnections = append(existingConnections, [2]int{sourceID, targetID})
 		}
 	}
 	if len(existingConnections) == 0 {
 		return -1, -1
 	}
-	selected := existingConnections[rand.Intn(len(existingConnections))]
-	return int(selected[0]), int(selected[1])
+	selected := existingConnections[random.Intn(len(existingConnections))]
+	return selected[0], selected[1]
 }
 
-// modifyActivationFunction changes the activation function of a neuron.
+// modifyActivationFunction changes the activation function of a neuron to any built-in or registered activation.
 func (bp *Blueprint) modifyActivationFunction(neuronID int, newActivation string) error {
 	neuron, exists := bp.Neurons[neuronID]
 	if !exists {
 		return fmt.Errorf("neuron ID %d does not exist", neuronID)
 	}
+	if !bp.hasActivation(newActivation) {
+		return fmt.Errorf("unknown activation %q", newActivation)
+	}
 	neuron.Activation = newActivation
+	bp.invalidateCompiled()
 	return nil
 }
 
 // getConnectionWeight retrieves the weight of a connection between sourceID and targetID.
 // Returns 0.0 if connection does not exist.
 func (bp *Blueprint) getConnectionWeight(sourceID, targetID int) float64 {
-	sourceNeuron, exists := bp.Neurons[sourceID]
-	if !exists {
-		return 0.0
-	}
-	for _, conn := range sourceNeuron.Connections {
-		if int(conn[0]) == targetID {
-			return conn[1]
-		}
-	}
-	return 0.0
+	weight, _ := bp.GetWeight(sourceID, targetID)
+	return weight
 }
 
-// performRandomModification executes a random modification and evaluates its impact.
-func (bp *Blueprint) performRandomModification(sess Session, neuronTypes []string) *NeuronAdditionAttempt {
-	// Randomly decide the modification type
-	modType := randomModificationType()
-
-	// Serialize the current model
-	modelJSON, err := bp.SerializeToJSON()
-	if err != nil {
-		fmt.Printf("Error serializing model: %v\n", err)
-		return nil
-	}
-
-	// Deserialize into a new Blueprint
-	newBP := &Blueprint{}
-	err = newBP.DeserializesFromJSON(modelJSON)
-	if err != nil {
-		fmt.Printf("Error deserializing model: %v\n", err)
-		return nil
-	}
+// performRandomModification executes a modification chosen by the bandit and evaluates its impact.
+// The bandit is rewarded when the modified model beats baseline, the unmodified model's score on the session.
+func (bp *Blueprint) performRandomModification(sess Session, neuronTypes []string, modBandit *Bandit, baseline EvaluationResult) *NeuronAdditionAttempt {
+	// Decide the modification type
+	modType := modBandit.Select()
 
-	// Perform the modification
+	// Modify a copy of the current model
+	newBP := bp.DeepCopy()
+	var err error
 	switch modType {
 	case "insert_neuron":
-		neuronType := neuronTypes[rand.Intn(len(neuronTypes))]
+		neuronType := neuronTypes[random.Intn(len(neuronTypes))]
 		err = newBP.InsertNeuronWithRandomConnections(neuronType)
 	case "add_connection":
 		sourceID, targetID := bp.getRandomConnectionPair()
 		if sourceID != -1 && targetID != -1 {
-			err = newBP.addConnection(sourceID, targetID, rand.Float64()*2-1)
+			err = newBP.addConnection(sourceID, targetID, random.Float64()*2-1)
 		}
 	case "modify_activation":
 		neuronID := bp.getRandomHiddenNeuron()
 		if neuronID != -1 {
-			err = newBP.modifyActivationFunction(neuronID, randomActivationFunction())
+			err = newBP.modifyActivationFunction(neuronID, newBP.randomActivation())
 		}
 	case "remove_connection":
 		sourceID, targetID := bp.getRandomExistingConnectionPair()
@@ -280,8 +272,8 @@ func (bp *Blueprint) performRandomModification(sess Session, neuronTypes []strin
 		}
 	case "adjust_weight":
 		sourceID, targetID := bp.getRandomExistingConnectionPair()
-		if sourceID != -1 && targetID != -1 {
-			err = newBP.addConnection(sourceID, targetID, bp.getConnectionWeight(sourceID, targetID)+(rand.Float64()*0.2-0.1))
+		if sourceID != -1 && targetID != -1 && !bp.isFrozen(targetID) {
+			err = newBP.setConnectionWeight(sourceID, targetID, bp.getConnectionWeight(sourceID, targetID)+(random.Float64()*0.2-0.1))
 		}
 	}
 
@@ -294,12 +286,17 @@ func (bp *Blueprint) performRandomModification(sess Session, neuronTypes []strin
 	newExact, newGenerous, newForgive, _, _, _ :=
 		newBP.EvaluateModelPerformance(tempSessions)
 
+	if validateImprovement(newExact, newGenerous, newForgive,
+		baseline.ExactAccuracy, baseline.GenerousAccuracy, baseline.ForgivenessAccuracy) {
+		modBandit.Update(modType, 1)
+	}
+
 	improvement := calculateImprovement(newExact, newGenerous, newForgive, 0, 0, 0) // Improvement per session
 
 	if improvement > 0 {
 		return &NeuronAdditionAttempt{
 			ModificationType: modType,
-			ModelJSON:        modelJSON,
+			Model:            newBP,
 			ExactAcc:         newExact,
 			GenerousAcc:      newGenerous,
 			ForgiveAcc:       newForgive,
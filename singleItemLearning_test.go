@@ -0,0 +1,71 @@
+package blueprint
+
+import "testing"
+
+func TestConnectionPairsFollowStorageDirection(t *testing.T) {
+	randomSource.Seed(5)
+	bp := NewBlueprint()
+	bp.AddInputNeurons([]int{1, 2})
+	bp.AddOutputNeurons([]int{3}, "linear")
+	if err := bp.addConnection(1, 3, 0.75); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 10; i++ {
+		sourceID, targetID := bp.getRandomExistingConnectionPair()
+		if sourceID != 1 || targetID != 3 {
+			t.Fatalf("got pair (%d, %d), want the only connection (1, 3)", sourceID, targetID)
+		}
+		if weight := bp.getConnectionWeight(sourceID, targetID); weight != 0.75 {
+			t.Errorf("getConnectionWeight(%d, %d) = %v, want 0.75", sourceID, targetID, weight)
+		}
+	}
+	if weight := bp.getConnectionWeight(3, 1); weight != 0 {
+		t.Errorf("the reversed edge has weight %v, want 0", weight)
+	}
+
+	sourceID, targetID := bp.getRandomConnectionPair()
+	if sourceID == -1 || bp.connectionExists(sourceID, targetID) {
+		t.Fatalf("getRandomConnectionPair returned (%d, %d), want a pair that is not connected yet", sourceID, targetID)
+	}
+	if err := bp.addConnection(sourceID, targetID, -0.5); err != nil {
+		t.Fatal(err)
+	}
+	if weight := bp.getConnectionWeight(sourceID, targetID); weight != -0.5 {
+		t.Errorf("the new connection (%d, %d) has weight %v, want -0.5", sourceID, targetID, weight)
+	}
+}
+
+func TestLearnOneDataItemAtATimeKeepsLoggerAndActivations(t *testing.T) {
+	randomSource.Seed(2)
+	// Raising the weight into output 2 helps every session, so some modification is kept
+	bp, _ := softRegressionTask()
+	sessions := []Session{}
+	for _, x := range []float64{0.5, 1, 1.5, 2} {
+		sessions = append(sessions, Session{
+			InputVariables: map[int]float64{1: x},
+			ExpectedOutput: map[int]float64{2: 0.9, 3: 0.1},
+			Timesteps:      1,
+		})
+	}
+	double := func(x float64) float64 { return 2 * x }
+	bp.RegisterActivation("double", double)
+	logger := &captureLogger{}
+	bp.Logger = logger
+
+	// An unknown neuron type makes every insertion fail, leaving the edits of the weights to improve the model
+	bp.LearnOneDataItemAtATime(sessions, 50, []string{"unknown"}, 4)
+
+	if logger.count("Model improved!") == 0 {
+		t.Fatal("no batch improved the model, so nothing replaced it")
+	}
+	if bp.Logger != logger {
+		t.Error("the improved model lost the caller's Logger")
+	}
+	if logger.count("LearnOneDataItemAtATime phase completed.") != 1 {
+		t.Error("messages after the model was replaced did not reach the Logger")
+	}
+	if !bp.hasActivation("double") {
+		t.Error("the improved model lost the registered activation")
+	}
+}
@@ -0,0 +1,37 @@
+package blueprint
+
+// maxScoreHistory bounds the number of sampled scores kept per model.
+const maxScoreHistory = 1024
+
+// recordScore appends a sampled evaluation of the model to its score history, dropping the oldest
+// entry once the history is full.
+func (bp *Blueprint) recordScore(result EvaluationResult) {
+	if len(bp.scoreHistory) >= maxScoreHistory {
+		bp.scoreHistory = append(bp.scoreHistory[:0], bp.scoreHistory[1:]...)
+	}
+	bp.scoreHistory = append(bp.scoreHistory, result)
+}
+
+// SmoothedMetrics returns an exponential moving average of the sampled evaluations recorded for this model,
+// oldest first, so a single lucky or unlucky sample only moves the estimate by alpha. ParallelNAS records one
+// evaluation per new sample while the model is the best found, and starts over when a new best is promoted.
+// An alpha of 1, or one outside (0, 1], returns the latest sample. The result is zero if nothing was recorded.
+func (bp *Blueprint) SmoothedMetrics(alpha float64) EvaluationResult {
+	if len(bp.scoreHistory) == 0 {
+		return EvaluationResult{}
+	}
+	if alpha <= 0 || alpha >= 1 {
+		return bp.scoreHistory[len(bp.scoreHistory)-1]
+	}
+
+	smoothed := bp.scoreHistory[0]
+	for _, r := range bp.scoreHistory[1:] {
+		smoothed.ExactAccuracy = alpha*r.ExactAccuracy + (1-alpha)*smoothed.ExactAccuracy
+		smoothed.GenerousAccuracy = alpha*r.GenerousAccuracy + (1-alpha)*smoothed.GenerousAccuracy
+		smoothed.ForgivenessAccuracy = alpha*r.ForgivenessAccuracy + (1-alpha)*smoothed.ForgivenessAccuracy
+		smoothed.AverageGenerousError = alpha*r.AverageGenerousError + (1-alpha)*smoothed.AverageGenerousError
+		smoothed.ExactErrorCount = r.ExactErrorCount
+		smoothed.ForgivenessErrorCount = r.ForgivenessErrorCount
+	}
+	return smoothed
+}
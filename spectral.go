@@ -0,0 +1,184 @@
+package blueprint
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+const (
+	spectralMaxIterations = 1000
+	spectralTolerance     = 1e-10
+)
+
+// AdjacencyMatrix returns the weighted adjacency matrix of the network together with the neuron ID
+// of each row and column in ascending order. Entry (i, j) holds the summed weight of the connections
+// from neuron ids[j] into neuron ids[i], so multiplying the matrix by a vector of neuron values
+// propagates them one step through the network. Connections from missing neurons are ignored.
+func (bp *Blueprint) AdjacencyMatrix() ([]int, *mat.Dense) {
+	ids := bp.getAllNeuronIDs()
+	sort.Ints(ids)
+	if len(ids) == 0 {
+		return ids, nil
+	}
+
+	index := make(map[int]int, len(ids))
+	for i, id := range ids {
+		index[id] = i
+	}
+
+	adjacency := mat.NewDense(len(ids), len(ids), nil)
+	for row, id := range ids {
+		neuron := bp.Neurons[id]
+		for i := 0; i < neuron.numConnections(); i++ {
+			sourceID, weight := neuron.connection(i)
+			if col, exists := index[sourceID]; exists {
+				adjacency.Set(row, col, adjacency.At(row, col)+weight)
+			}
+		}
+	}
+	return ids, adjacency
+}
+
+// SpectralRadius estimates the largest eigenvalue magnitude of the adjacency matrix using power iteration.
+// A value well above 1 predicts exploding recurrent dynamics over many timesteps, below 1 decaying ones,
+// and a feed-forward network always has a spectral radius of 0.
+// The estimate is the geometric mean of the per-step growth of the iterated vector, which also converges
+// when the dominant eigenvalues form a complex or a positive/negative pair.
+func (bp *Blueprint) SpectralRadius() (float64, error) {
+	_, adjacency := bp.AdjacencyMatrix()
+	if adjacency == nil {
+		return 0, fmt.Errorf("blueprint has no neurons")
+	}
+	n, _ := adjacency.Dims()
+
+	// A random start vector is almost surely not orthogonal to the dominant eigenvector
+	rng := rand.New(rand.NewSource(1))
+	x := mat.NewVecDense(n, nil)
+	for i := 0; i < n; i++ {
+		x.SetVec(i, rng.Float64()+0.5)
+	}
+	x.ScaleVec(1/x.Norm(2), x)
+
+	y := mat.NewVecDense(n, nil)
+	growths := make([]float64, 0, spectralMaxIterations)
+	for k := 0; k < spectralMaxIterations; k++ {
+		y.MulVec(adjacency, x)
+		norm := y.Norm(2)
+		if norm == 0 || math.IsNaN(norm) {
+			// The vector vanished, so the matrix is nilpotent along it
+			if math.IsNaN(norm) {
+				return 0, fmt.Errorf("adjacency matrix contains non-finite weights")
+			}
+			return 0, nil
+		}
+		if math.IsInf(norm, 1) {
+			return math.Inf(1), nil
+		}
+		growths = append(growths, norm)
+		x.ScaleVec(1/norm, y)
+
+		// A real dominant eigenvalue makes the growth settle immediately
+		if k > 0 && math.Abs(norm-growths[k-1]) <= spectralTolerance*norm {
+			return norm, nil
+		}
+	}
+
+	// Average the log growth over the second half to smooth out rotation between eigenvectors
+	logSum := 0.0
+	tail := growths[len(growths)/2:]
+	for _, growth := range tail {
+		logSum += math.Log(growth)
+	}
+	return math.Exp(logSum / float64(len(tail))), nil
+}
+
+// SpectralNormalize divides the weights of the connections into every layer returned by ComputeLayers by the
+// largest singular value of that layer's weight matrix, which has a row per neuron of the layer and a column
+// per distinct source feeding it. Each layer then amplifies the norm of its inputs by at most 1, bounding the
+// Lipschitz constant of the network. The singular values are estimated with power iteration and biases are
+// left unchanged. An error is returned if the network has a cycle or non-finite weights.
+func (bp *Blueprint) SpectralNormalize() error {
+	layers, err := bp.ComputeLayers()
+	if err != nil {
+		return err
+	}
+
+	for l := 1; l < len(layers); l++ {
+		// Build the layer's weight matrix over the distinct sources of its neurons
+		var sources []int
+		column := make(map[int]int)
+		for _, id := range layers[l] {
+			neuron := bp.Neurons[id]
+			for i := 0; i < neuron.numConnections(); i++ {
+				sourceID, _ := neuron.connection(i)
+				if _, seen := column[sourceID]; !seen {
+					column[sourceID] = len(sources)
+					sources = append(sources, sourceID)
+				}
+			}
+		}
+		if len(sources) == 0 {
+			continue
+		}
+		weights := mat.NewDense(len(layers[l]), len(sources), nil)
+		for row, id := range layers[l] {
+			neuron := bp.Neurons[id]
+			for i := 0; i < neuron.numConnections(); i++ {
+				sourceID, weight := neuron.connection(i)
+				col := column[sourceID]
+				weights.Set(row, col, weights.At(row, col)+weight)
+			}
+		}
+
+		sigma := largestSingularValue(weights)
+		if math.IsNaN(sigma) || math.IsInf(sigma, 0) {
+			return fmt.Errorf("layer %d contains non-finite weights", l)
+		}
+		if sigma == 0 {
+			continue
+		}
+		for _, id := range layers[l] {
+			neuron := bp.Neurons[id]
+			for i := 0; i < neuron.numConnections(); i++ {
+				_, weight := neuron.connection(i)
+				neuron.setConnectionWeight(i, weight/sigma)
+			}
+		}
+		bp.debugf("SpectralNormalize: layer %d divided by %f", l, sigma)
+	}
+
+	bp.invalidateCompiled()
+	return nil
+}
+
+// largestSingularValue estimates the largest singular value of m by power iteration on mᵀm.
+func largestSingularValue(m *mat.Dense) float64 {
+	_, cols := m.Dims()
+	rng := rand.New(rand.NewSource(1))
+	v := mat.NewVecDense(cols, nil)
+	for i := 0; i < cols; i++ {
+		v.SetVec(i, rng.Float64()+0.5)
+	}
+	v.ScaleVec(1/v.Norm(2), v)
+
+	var mv, mtmv mat.VecDense
+	sigma := 0.0
+	for k := 0; k < spectralMaxIterations; k++ {
+		mv.MulVec(m, v)
+		next := mv.Norm(2)
+		if next == 0 || math.IsNaN(next) || math.IsInf(next, 0) {
+			return next
+		}
+		mtmv.MulVec(m.T(), &mv)
+		v.ScaleVec(1/mtmv.Norm(2), &mtmv)
+		if math.Abs(next-sigma) <= spectralTolerance*next {
+			return next
+		}
+		sigma = next
+	}
+	return sigma
+}
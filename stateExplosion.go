@@ -0,0 +1,64 @@
+package blueprint
+
+import (
+	"math"
+	"sort"
+)
+
+// stateExplosionThreshold is the recurrent state magnitude above which DetectStateExplosion reports an explosion.
+const stateExplosionThreshold = 1e6
+
+// isRecurrentNeuron reports whether a neuron carries state from one timestep to the next.
+func isRecurrentNeuron(neuron *Neuron) bool {
+	return neuron.Type == "rnn" || neuron.Type == "lstm"
+}
+
+// clampRecurrentState bounds the value, and for LSTM neurons the cell state, of every recurrent neuron
+// to [-StateClamp, StateClamp]. NaN states are reset to 0.
+func (bp *Blueprint) clampRecurrentState() {
+	for _, neuron := range bp.Neurons {
+		if !isRecurrentNeuron(neuron) {
+			continue
+		}
+		neuron.Value = clampState(neuron.Value, bp.StateClamp)
+		if neuron.Type == "lstm" {
+			neuron.CellState = clampState(neuron.CellState, bp.StateClamp)
+		}
+	}
+}
+
+// clampState bounds a recurrent state to [-limit, limit], resetting NaN to 0.
+func clampState(v, limit float64) float64 {
+	if math.IsNaN(v) {
+		return 0
+	}
+	return math.Max(-limit, math.Min(limit, v))
+}
+
+// DetectStateExplosion runs the network on inputs like Forward and reports whether the value or cell state of
+// any RNN or LSTM neuron became NaN, infinite or larger than 1e6 in magnitude, and the first timestep at which
+// it did, or -1. StateClamp is applied as in Forward, so a clamp below the threshold prevents explosions.
+func (bp *Blueprint) DetectStateExplosion(inputs map[int]float64, timesteps int) (bool, int) {
+	bp.setInputValues(inputs)
+
+	neuronIDs := bp.getAllNeuronIDs()
+	sort.Ints(neuronIDs)
+
+	exploded := func(v float64) bool {
+		return math.IsNaN(v) || math.Abs(v) > stateExplosionThreshold
+	}
+	for t := 0; t < timesteps; t++ {
+		bp.forwardTimestep(neuronIDs, t)
+		for _, id := range neuronIDs {
+			neuron := bp.Neurons[id]
+			if !isRecurrentNeuron(neuron) {
+				continue
+			}
+			if exploded(neuron.Value) || (neuron.Type == "lstm" && exploded(neuron.CellState)) {
+				bp.debugf("Recurrent state of neuron %d exploded at timestep %d: Value=%f", id, t, neuron.Value)
+				return true, t
+			}
+		}
+	}
+	return false, -1
+}
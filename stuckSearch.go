@@ -0,0 +1,100 @@
+package blueprint
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// defaultStuckIterations is the number of iterations without any change after which a search is reported as stuck.
+const defaultStuckIterations = 50
+
+// scoreDistance returns the largest difference between any metric of a and b.
+func scoreDistance(a, b EvaluationResult) float64 {
+	return math.Max(math.Abs(a.ExactAccuracy-b.ExactAccuracy),
+		math.Max(math.Abs(a.GenerousAccuracy-b.GenerousAccuracy), math.Abs(a.ForgivenessAccuracy-b.ForgivenessAccuracy)))
+}
+
+// stuckWatchdog counts consecutive iterations in which the best model did not change and no candidate
+// scored differently from it, which a converged search hardly ever produces but a broken one always does.
+// A model with perfect exact accuracy is exempt, since its candidates can legitimately all tie with it.
+type stuckWatchdog struct {
+	limit  int
+	run    int
+	warned bool
+	warnf  func(format string, args ...any)
+}
+
+// newStuckWatchdog creates a watchdog that fires after limit stuck iterations (0 uses the default, negative disables it)
+// and reports through warnf.
+func newStuckWatchdog(limit int, warnf func(format string, args ...any)) *stuckWatchdog {
+	if limit == 0 {
+		limit = defaultStuckIterations
+	}
+	return &stuckWatchdog{limit: limit, warnf: warnf}
+}
+
+// observe records one iteration and reports a warning the first time the run of stuck iterations reaches the limit.
+func (w *stuckWatchdog) observe(best EvaluationResult, improved bool, candidateSpread float64) {
+	if w.limit < 0 {
+		return
+	}
+	if improved || candidateSpread != 0 || best.ExactAccuracy >= 100 {
+		w.run = 0
+		w.warned = false
+		return
+	}
+	w.run++
+	if w.run >= w.limit && !w.warned {
+		w.warned = true
+		w.warnf("WARNING: the search may be broken, not converged: for %d iterations the best model has not changed "+
+			"and every candidate scored exactly the same as it. Check that activations are initialized and that "+
+			"inserted neurons actually affect the outputs.", w.run)
+	}
+}
+
+// DiagnoseStuckSearch inspects a training history for signs of a broken search and returns a description of
+// the problem, or an empty string if none was found. A search is considered stuck when, for at least 50
+// trailing iterations, the best metrics did not change and every candidate scored identically to the best
+// model, unless the model already reached perfect exact accuracy. Likely causes found on the blueprint
+// itself are added to the description.
+func (bp *Blueprint) DiagnoseStuckSearch(history TrainingHistory) string {
+	entries := history.Entries
+	run := 0
+	for i := len(entries) - 1; i > 0; i-- {
+		current, previous := entries[i], entries[i-1]
+		if current.CandidateSpread != 0 ||
+			current.ExactAccuracy != previous.ExactAccuracy ||
+			current.GenerousAccuracy != previous.GenerousAccuracy ||
+			current.ForgivenessAccuracy != previous.ForgivenessAccuracy {
+			break
+		}
+		run++
+	}
+	if run < defaultStuckIterations {
+		return ""
+	}
+	last := entries[len(entries)-1]
+	if last.ExactAccuracy >= 100 {
+		return ""
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "search appears stuck: for the last %d iterations the metrics stayed at Exact=%.2f%%, Generous=%.4f, "+
+		"Forgiveness=%.2f%% and every candidate scored identically", run, last.ExactAccuracy, last.GenerousAccuracy, last.ForgivenessAccuracy)
+
+	if len(bp.ScalarActivationMap) == 0 {
+		sb.WriteString("; the activation map is not initialized, so every neuron falls back to a linear activation")
+	}
+	if len(bp.OutputNodes) == 0 {
+		sb.WriteString("; the blueprint has no output nodes")
+	}
+	for _, id := range bp.OutputNodes {
+		if neuron, exists := bp.Neurons[id]; !exists {
+			fmt.Fprintf(&sb, "; output node %d has no neuron", id)
+		} else if neuron.numConnections() == 0 {
+			fmt.Fprintf(&sb, "; output neuron %d has no incoming connections", id)
+		}
+	}
+	return sb.String()
+}
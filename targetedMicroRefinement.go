@@ -1,12 +1,71 @@
 package blueprint
 
 import (
-	"fmt"
+	"context"
 	"math"
-	"math/rand"
-	"time"
 )
 
+// RefinementOption configures optional behaviour of TargetedMicroRefinement.
+type RefinementOption func(*refinementConfig)
+
+// refinementConfig holds the settings applied by RefinementOptions.
+type refinementConfig struct {
+	progress          ProgressFunc
+	perturbationScale float64
+	perturbationDecay float64
+	evaluationBatch   int
+}
+
+// Default perturbation schedule of TargetedMicroRefinement: a constant standard deviation of 0.01.
+const (
+	defaultPerturbationScale = 0.01
+	defaultPerturbationDecay = 1.0
+)
+
+// WithPerturbationScale sets the standard deviation of the weight tweaks made in the first refinement iteration.
+// Values of 0 or less keep the default of 0.01.
+func WithPerturbationScale(scale float64) RefinementOption {
+	return func(cfg *refinementConfig) {
+		if scale > 0 {
+			cfg.perturbationScale = scale
+		}
+	}
+}
+
+// WithPerturbationDecay multiplies the perturbation scale by factor after every refinement iteration, so later
+// iterations make finer tweaks. Factors outside (0, 1] are ignored and the scale stays constant.
+func WithPerturbationDecay(factor float64) RefinementOption {
+	return func(cfg *refinementConfig) {
+		if factor > 0 && factor <= 1 {
+			cfg.perturbationDecay = factor
+		}
+	}
+}
+
+// WithEvaluationBatch makes TargetedMicroRefinement check each iteration on a random mini-batch of size sessions,
+// drawn anew every iteration, before and after its weight tweaks. The full session set is only evaluated when the
+// mini-batch improves without any accuracy decreasing, and once more at the end, which saves most of the
+// per-iteration evaluation cost on large session sets. A size of 0 or at least the number of sessions evaluates
+// the full set every iteration, the default.
+func WithEvaluationBatch(size int) RefinementOption {
+	return func(cfg *refinementConfig) {
+		cfg.evaluationBatch = size
+	}
+}
+
+// perturbationScaleAt returns the perturbation scale used in the given 1-based refinement iteration.
+func (cfg *refinementConfig) perturbationScaleAt(iteration int) float64 {
+	return cfg.perturbationScale * math.Pow(cfg.perturbationDecay, float64(iteration-1))
+}
+
+// WithRefinementProgress makes TargetedMicroRefinement call fn with the model's metrics after every iteration.
+// Returning an error from fn stops the refinement, keeping the weight tweaks made so far.
+func WithRefinementProgress(fn ProgressFunc) RefinementOption {
+	return func(cfg *refinementConfig) {
+		cfg.progress = fn
+	}
+}
+
 // TargetedMicroRefinement attempts to improve the model by focusing on "near-miss" samples
 // and making small weight tweaks. It updates only if any accuracy improves without others decreasing.
 func (bp *Blueprint) TargetedMicroRefinement(
@@ -15,26 +74,50 @@ func (bp *Blueprint) TargetedMicroRefinement(
 	sampleSubsetSize int,
 	connectionTrialsPerSample int,
 	improvementThreshold float64,
+	opts ...RefinementOption,
 ) {
-	rand.Seed(time.Now().UnixNano())
+	_ = bp.TargetedMicroRefinementWithContext(context.Background(), sessions, maxIterations, sampleSubsetSize,
+		connectionTrialsPerSample, improvementThreshold, opts...)
+}
+
+// TargetedMicroRefinementWithContext is TargetedMicroRefinement that stops once ctx is cancelled. Cancellation is
+// checked before every iteration and every sample; the weight tweaks kept so far stay in place and ctx.Err() is
+// returned. A progress callback that returns an error stops the refinement the same way, and its error is
+// returned. A refinement that runs to the end returns nil.
+func (bp *Blueprint) TargetedMicroRefinementWithContext(
+	ctx context.Context,
+	sessions []Session,
+	maxIterations int,
+	sampleSubsetSize int,
+	connectionTrialsPerSample int,
+	improvementThreshold float64,
+	opts ...RefinementOption,
+) error {
+	cfg := refinementConfig{
+		perturbationScale: defaultPerturbationScale,
+		perturbationDecay: defaultPerturbationDecay,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
 
 	exactAcc, generousAcc, forgiveAcc, _, _, _ := bp.EvaluateModelPerformance(sessions)
-	fmt.Printf("Starting TargetedMicroRefinement: Exact=%.6f%%, Generous=%.6f%%, Forgiveness=%.6f%%\n",
+	bp.infof("Starting TargetedMicroRefinement: Exact=%.6f%%, Generous=%.6f%%, Forgiveness=%.6f%%",
 		exactAcc, generousAcc, forgiveAcc)
 
 	if exactAcc > improvementThreshold {
-		fmt.Println("Already beyond improvement threshold. No refinement needed.")
-		return
+		bp.infof("Already beyond improvement threshold. No refinement needed.")
+		return nil
 	}
 
 	// Find near-miss samples at 80% generous cutoff
 	nearMissSamples := bp.findNearMissSamples(sessions, 0.8)
 	if len(nearMissSamples) == 0 {
-		fmt.Println("No near-miss samples found at 80% generous cutoff. Trying 50% cutoff...")
+		bp.infof("No near-miss samples found at 80%% generous cutoff. Trying 50%% cutoff...")
 		nearMissSamples = bp.findNearMissSamples(sessions, 0.5)
 		if len(nearMissSamples) == 0 {
-			fmt.Println("No near-miss samples found even at 50% cutoff. Nothing to refine.")
-			return
+			bp.infof("No near-miss samples found even at 50%% cutoff. Nothing to refine.")
+			return nil
 		}
 	}
 
@@ -43,35 +126,66 @@ func (bp *Blueprint) TargetedMicroRefinement(
 	lastGenerousAcc := generousAcc
 	lastForgiveAcc := forgiveAcc
 
+	// The latest full-set metrics, which stay in place for iterations whose mini-batch shows no improvement
+	newExactAcc, newGenerousAcc, newForgiveAcc := exactAcc, generousAcc, forgiveAcc
+	useBatch := cfg.evaluationBatch > 0 && cfg.evaluationBatch < len(sessions)
+	fullEvaluations, lastEvaluationFull := 1, true
+
 	for iter := 1; iter <= maxIterations; iter++ {
-		fmt.Printf("--- Refine Iteration %d ---\n", iter)
+		if err := ctx.Err(); err != nil {
+			bp.infof("TargetedMicroRefinement cancelled after iteration %d.", iter-1)
+			return err
+		}
+		scale := cfg.perturbationScaleAt(iter)
+		bp.infof("--- Refine Iteration %d (perturbation scale %.6f) ---", iter, scale)
+
+		var batch []Session
+		var batchBefore EvaluationResult
+		if useBatch {
+			batch = sampleSessions(sessions, cfg.evaluationBatch, random.Int63())
+			batchBefore = bp.Evaluate(batch)
+		}
 
 		subset := sampleSubset(nearMissSamples, sampleSubsetSize)
 		for _, s := range subset {
+			if err := ctx.Err(); err != nil {
+				bp.infof("TargetedMicroRefinement cancelled during iteration %d.", iter)
+				return err
+			}
 			criticalConnections := bp.identifyCriticalConnections()
-			_ = bp.refineSampleWeights(s, criticalConnections, connectionTrialsPerSample)
+			_ = bp.refineSampleWeights(s, criticalConnections, connectionTrialsPerSample, scale)
 		}
 
-		newExactAcc, newGenerousAcc, newForgiveAcc, _, _, _ :=
-			bp.EvaluateModelPerformance(sessions)
+		lastEvaluationFull = true
+		if useBatch {
+			batchAfter := bp.Evaluate(batch)
+			if !improvesWithoutRegression(batchBefore, batchAfter) {
+				lastEvaluationFull = false
+				bp.infof("No improvement on the mini-batch of %d sessions. Skipping the full evaluation.", len(batch))
+			}
+		}
+		if lastEvaluationFull {
+			newExactAcc, newGenerousAcc, newForgiveAcc, _, _, _ = bp.EvaluateModelPerformance(sessions)
+			fullEvaluations++
+		}
 
-		fmt.Printf("After iteration %d:\n", iter)
-		fmt.Printf("Exact=%.6f%% (was %.6f%%), Generous=%.6f%% (was %.6f%%), Forgiveness=%.6f%% (was %.6f%%)\n",
+		bp.infof("After iteration %d:", iter)
+		bp.infof("Exact=%.6f%% (was %.6f%%), Generous=%.6f%% (was %.6f%%), Forgiveness=%.6f%% (was %.6f%%)",
 			newExactAcc, lastExactAcc, newGenerousAcc, lastGenerousAcc, newForgiveAcc, lastForgiveAcc)
 
 		// Check for improvement without regression
 		improvement := false
 		if newExactAcc >= lastExactAcc && newGenerousAcc >= lastGenerousAcc && newForgiveAcc >= lastForgiveAcc {
 			if newExactAcc > lastExactAcc {
-				fmt.Println("Exact accuracy improved!")
+				bp.infof("Exact accuracy improved!")
 				improvement = true
 			}
 			if newGenerousAcc > lastGenerousAcc {
-				fmt.Println("Generous accuracy improved!")
+				bp.infof("Generous accuracy improved!")
 				improvement = true
 			}
 			if newForgiveAcc > lastForgiveAcc {
-				fmt.Println("Forgiveness accuracy improved!")
+				bp.infof("Forgiveness accuracy improved!")
 				improvement = true
 			}
 		}
@@ -83,19 +197,53 @@ func (bp *Blueprint) TargetedMicroRefinement(
 			noImprovementCount = 0
 		} else {
 			noImprovementCount++
-			fmt.Printf("No improvement in metrics this iteration. Count=%d\n", noImprovementCount)
+			bp.infof("No improvement in metrics this iteration. Count=%d", noImprovementCount)
+		}
+
+		metrics := NASMetrics{
+			Iteration:           iter,
+			ExactAccuracy:       newExactAcc,
+			GenerousAccuracy:    newGenerousAcc,
+			ForgivenessAccuracy: newForgiveAcc,
+			NeuronCount:         len(bp.Neurons),
+			Improved:            improvement,
+		}
+		if err := cfg.progress.call(iter, metrics); err != nil {
+			bp.infof("TargetedMicroRefinement stopped by the progress callback: %v", err)
+			return err
 		}
 
 		if newExactAcc >= improvementThreshold {
-			fmt.Printf("Reached improvement threshold of %.6f%% exact accuracy.\n", improvementThreshold)
+			bp.infof("Reached improvement threshold of %.6f%% exact accuracy.", improvementThreshold)
 			break
 		}
 
 		if noImprovementCount > 5 {
-			fmt.Println("No improvement in several iterations. Stopping refinement.")
+			bp.infof("No improvement in several iterations. Stopping refinement.")
 			break
 		}
 	}
+
+	if useBatch {
+		if !lastEvaluationFull {
+			newExactAcc, newGenerousAcc, newForgiveAcc, _, _, _ = bp.EvaluateModelPerformance(sessions)
+			fullEvaluations++
+		}
+		bp.infof("TargetedMicroRefinement finished after %d full evaluations: Exact=%.6f%%, Generous=%.6f%%, Forgiveness=%.6f%%",
+			fullEvaluations, newExactAcc, newGenerousAcc, newForgiveAcc)
+	}
+	return nil
+}
+
+// improvesWithoutRegression reports whether after has a higher accuracy than before on some metric and a lower
+// accuracy on none.
+func improvesWithoutRegression(before, after EvaluationResult) bool {
+	if after.ExactAccuracy < before.ExactAccuracy || after.GenerousAccuracy < before.GenerousAccuracy ||
+		after.ForgivenessAccuracy < before.ForgivenessAccuracy {
+		return false
+	}
+	return after.ExactAccuracy > before.ExactAccuracy || after.GenerousAccuracy > before.GenerousAccuracy ||
+		after.ForgivenessAccuracy > before.ForgivenessAccuracy
 }
 
 // findNearMissSamples identifies sessions where the network is close but not exact based on generousCutoff.
@@ -120,7 +268,7 @@ func (bp *Blueprint) findNearMissSamples(sessions []Session, generousCutoff floa
 		}
 	}
 
-	fmt.Printf("Checked %d samples for near-miss at cutoff=%.2f%%, found %d qualifying.\n",
+	bp.infof("Checked %d samples for near-miss at cutoff=%.2f%%, found %d qualifying.",
 		countChecked, generousCutoff*100.0, countQualified)
 	return nearMiss
 }
@@ -130,7 +278,7 @@ func sampleSubset(sessions []Session, n int) []Session {
 	if len(sessions) <= n {
 		return sessions
 	}
-	rand.Shuffle(len(sessions), func(i, j int) { sessions[i], sessions[j] = sessions[j], sessions[i] })
+	random.Shuffle(len(sessions), func(i, j int) { sessions[i], sessions[j] = sessions[j], sessions[i] })
 	return sessions[:n]
 }
 
@@ -140,30 +288,40 @@ func (bp *Blueprint) identifyCriticalConnections() []int {
 	return bp.OutputNodes
 }
 
-// refineSampleWeights tries small perturbations on weights for one sample.
+// refineSampleWeights tries small perturbations on weights for one sample, drawn with standard deviation scale.
+// Frozen neurons among criticalNeurons are skipped.
 func (bp *Blueprint) refineSampleWeights(
 	sample Session,
 	criticalNeurons []int,
 	trials int,
+	scale float64,
 ) bool {
 	initialError := bp.sampleError(sample)
 	improved := false
 
+	trainable := make([]int, 0, len(criticalNeurons))
+	for _, id := range criticalNeurons {
+		if _, ok := bp.Neurons[id]; ok && !bp.isFrozen(id) {
+			trainable = append(trainable, id)
+		}
+	}
+	criticalNeurons = trainable
+
 	if len(criticalNeurons) == 0 {
-		fmt.Println("No critical neurons identified. Skipping this sample.")
+		bp.infof("No unfrozen critical neurons identified. Skipping this sample.")
 		return false
 	}
 
 	for trial := 0; trial < trials; trial++ {
-		nID := criticalNeurons[rand.Intn(len(criticalNeurons))]
+		nID := criticalNeurons[random.Intn(len(criticalNeurons))]
 		neuron, ok := bp.Neurons[nID]
 		if !ok || len(neuron.Connections) == 0 {
 			continue
 		}
 
-		cIndex := rand.Intn(len(neuron.Connections))
+		cIndex := random.Intn(len(neuron.Connections))
 		oldWeight := neuron.Connections[cIndex][1]
-		delta := rand.NormFloat64() * 0.01
+		delta := random.NormFloat64() * scale
 
 		// Try positive delta
 		neuron.Connections[cIndex][1] = oldWeight + delta
@@ -171,7 +329,7 @@ func (bp *Blueprint) refineSampleWeights(
 		if newError < initialError {
 			initialError = newError
 			improved = true
-			fmt.Printf("Improved sample error with +delta=%.6f on connection %d of neuron %d\n", delta, cIndex, nID)
+			bp.infof("Improved sample error with +delta=%.6f on connection %d of neuron %d", delta, cIndex, nID)
 		} else {
 			// revert and try negative delta
 			neuron.Connections[cIndex][1] = oldWeight - delta
@@ -179,7 +337,7 @@ func (bp *Blueprint) refineSampleWeights(
 			if newError < initialError {
 				initialError = newError
 				improved = true
-				fmt.Printf("Improved sample error with -delta=%.6f on connection %d of neuron %d\n", delta, cIndex, nID)
+				bp.infof("Improved sample error with -delta=%.6f on connection %d of neuron %d", delta, cIndex, nID)
 			} else {
 				// revert to original if no improvement
 				neuron.Connections[cIndex][1] = oldWeight
@@ -188,7 +346,7 @@ func (bp *Blueprint) refineSampleWeights(
 	}
 
 	if !improved {
-		fmt.Println("No improvements made on this sample after all trials.")
+		bp.infof("No improvements made on this sample after all trials.")
 	}
 	return improved
 }
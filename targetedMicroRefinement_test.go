@@ -0,0 +1,130 @@
+package blueprint
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestPerturbationDecayShrinksLaterTweaks(t *testing.T) {
+	randomSource.Seed(3)
+	bp, sessions := softRegressionTask()
+	logger := &captureLogger{}
+	bp.Logger = logger
+
+	// A threshold beyond 100% keeps refining once every sample is classified correctly
+	bp.TargetedMicroRefinement(sessions, 8, 4, 4, 101, WithPerturbationScale(0.2), WithPerturbationDecay(0.5))
+
+	// Every iteration logs its scale, followed by the signed tweaks that improved a sample
+	scales := []float64{}
+	tweaks := 0
+	for _, message := range logger.messages {
+		var iteration int
+		var scale, delta float64
+		if _, err := fmt.Sscanf(message, "info: --- Refine Iteration %d (perturbation scale %f) ---", &iteration, &scale); err == nil {
+			scales = append(scales, scale)
+			continue
+		}
+		_, text, found := strings.Cut(message, "delta=")
+		if !found || len(scales) == 0 {
+			continue
+		}
+		if _, err := fmt.Sscanf(text, "%f", &delta); err != nil {
+			t.Fatalf("cannot parse the tweak in %q: %v", message, err)
+		}
+		tweaks++
+		if current := scales[len(scales)-1]; math.Abs(delta) > 5*current {
+			t.Errorf("iteration %d made a tweak of %v, far beyond its scale %v", len(scales), delta, current)
+		}
+	}
+
+	if len(scales) < 3 {
+		t.Fatalf("refinement ran %d iterations, want at least 3 to compare", len(scales))
+	}
+	for i, scale := range scales {
+		if want := 0.2 * math.Pow(0.5, float64(i)); math.Abs(scale-want) > 1e-6 {
+			t.Errorf("iteration %d used scale %v, want %v", i+1, scale, want)
+		}
+	}
+	if tweaks == 0 {
+		t.Error("no tweak improved a sample")
+	}
+}
+
+func TestPerturbationScheduleOptions(t *testing.T) {
+	cfg := refinementConfig{perturbationScale: defaultPerturbationScale, perturbationDecay: defaultPerturbationDecay}
+	for _, opt := range []RefinementOption{WithPerturbationScale(-1), WithPerturbationDecay(0), WithPerturbationDecay(1.5)} {
+		opt(&cfg)
+	}
+	if got := cfg.perturbationScaleAt(10); got != defaultPerturbationScale {
+		t.Errorf("scale at iteration 10 after invalid options = %v, want the constant default %v", got, defaultPerturbationScale)
+	}
+
+	WithPerturbationScale(0.4)(&cfg)
+	WithPerturbationDecay(0.9)(&cfg)
+	for iteration := 2; iteration <= 10; iteration++ {
+		if cfg.perturbationScaleAt(iteration) >= cfg.perturbationScaleAt(iteration-1) {
+			t.Fatalf("scale at iteration %d is not below iteration %d", iteration, iteration-1)
+		}
+	}
+	if got := cfg.perturbationScaleAt(1); got != 0.4 {
+		t.Errorf("scale at iteration 1 = %v, want 0.4", got)
+	}
+}
+
+// refinementRun refines a fresh soft regression network on 40 samples and returns its output MAE before and
+// after, its log and the number of full-set evaluations the refinement made.
+func refinementRun(seed int64, opts ...RefinementOption) (before, after float64, logger *captureLogger, fullEvaluations int) {
+	randomSource.Seed(seed)
+	bp, _ := softRegressionTask()
+	sessions := []Session{}
+	for i := 0; i < 40; i++ {
+		x := -2 + 4*float64(i)/39
+		p := 1 / (1 + math.Exp(-2*x))
+		sessions = append(sessions, Session{
+			InputVariables: map[int]float64{1: x},
+			ExpectedOutput: map[int]float64{2: p, 3: 1 - p},
+			Timesteps:      1,
+		})
+	}
+	logger = &captureLogger{}
+	bp.Logger = logger
+
+	before = outputMAE(bp, sessions)
+	bp.TargetedMicroRefinement(sessions, 30, 4, 4, 101, opts...)
+	after = outputMAE(bp, sessions)
+
+	// Without a mini-batch the initial check and every iteration evaluate the full set
+	fullEvaluations = 1 + logger.count("--- Refine Iteration")
+	for _, message := range logger.messages {
+		if _, text, found := strings.Cut(message, "finished after "); found {
+			fmt.Sscanf(text, "%d full evaluations", &fullEvaluations)
+		}
+	}
+	return before, after, logger, fullEvaluations
+}
+
+func TestEvaluationBatchSavesFullEvaluations(t *testing.T) {
+	_, _, _, fullCount := refinementRun(5)
+	before, after, logger, batchCount := refinementRun(5, WithEvaluationBatch(4))
+
+	// Only iterations whose mini-batch improved evaluate the full set, plus the initial check and, when the
+	// last iteration skipped it, a final one
+	iterations := logger.count("--- Refine Iteration")
+	skipped := logger.count("Skipping the full evaluation")
+	if skipped == 0 {
+		t.Fatal("no iteration skipped the full evaluation")
+	}
+	if want := 1 + iterations - skipped; batchCount != want && batchCount != want+1 {
+		t.Errorf("mini-batch refinement made %d full evaluations over %d iterations with %d skipped, want %d or %d",
+			batchCount, iterations, skipped, want, want+1)
+	}
+	if batchCount >= fullCount || batchCount >= iterations {
+		t.Errorf("mini-batch refinement made %d full evaluations in %d iterations, want fewer than one per iteration and than the %d without it",
+			batchCount, iterations, fullCount)
+	}
+	if after > before/10 {
+		t.Errorf("mini-batch refinement reduced the MAE from %.4f only to %.4f", before, after)
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"crypto/tls"
 	"encoding/json"
 	"fmt"
-	"log"
 	"net"
 	"runtime"
 	"time"
@@ -30,6 +29,7 @@ type Hub struct {
 	addClientChan    chan *Client
 	removeClientChan chan string
 	clients          map[string]*Client
+	Logger           Logger // Receives the server's messages; nil discards them
 }
 
 type InitializationRequest struct {
@@ -48,15 +48,23 @@ func NewHub() *Hub {
 	}
 }
 
+// logger returns the hub's Logger, or one that discards every message when none is set.
+func (h *Hub) logger() Logger {
+	if h.Logger == nil {
+		return noopLogger{}
+	}
+	return h.Logger
+}
+
 func (h *Hub) Run() {
 	for {
 		select {
 		case client := <-h.addClientChan:
 			h.clients[client.Addr] = client
-			fmt.Printf("Added client: %s\n", client.Addr)
+			h.logger().Infof("Added client: %s", client.Addr)
 		case addr := <-h.removeClientChan:
 			delete(h.clients, addr)
-			fmt.Printf("Removed client: %s\n", addr)
+			h.logger().Infof("Removed client: %s", addr)
 		}
 	}
 }
@@ -65,10 +73,10 @@ func (h *Hub) Broadcast(msg []byte) {
 	for addr, client := range h.clients {
 		_, err := client.Conn.Write(msg)
 		if err != nil {
-			fmt.Printf("Error broadcasting to client %s: %v\n", addr, err)
+			h.logger().Warnf("Error broadcasting to client %s: %v", addr, err)
 			h.RemoveClient(addr)
 		} else {
-			fmt.Println("msg sent to: ", client.Addr)
+			h.logger().Debugf("msg sent to: %s", client.Addr)
 		}
 	}
 }
@@ -93,7 +101,7 @@ func handleConnection(client *Client, password string) {
 	}
 
 	if string(buf[:n]) != password {
-		fmt.Printf("Client %s provided wrong password\n", client.Addr)
+		hub.logger().Warnf("Client %s provided wrong password", client.Addr)
 		hub.RemoveClient(client.Addr)
 		return
 	}
@@ -113,11 +121,11 @@ func handleConnection(client *Client, password string) {
 		client.LastSeen = time.Now()
 		message := string(buf[:n])
 		var response []byte
-		fmt.Printf("Received data from %s: %s\n", addr, message)
+		hub.logger().Debugf("Received data from %s: %s", addr, message)
 
 		var js map[string]interface{}
 		errCheckJson := json.Unmarshal([]byte(message), &js)
-		fmt.Println(js)
+		hub.logger().Debugf("%v", js)
 
 		if errCheckJson != nil {
 			switch message {
@@ -129,14 +137,16 @@ func handleConnection(client *Client, password string) {
 				// Get RAM info
 				vmStat, err := mem.VirtualMemory()
 				if err != nil {
-					log.Fatalf("Error getting memory info: %v", err)
+					hub.logger().Warnf("Error getting memory info: %v", err)
+					continue
 				}
 				ramInfo := fmt.Sprintf("%.2fGB", float64(vmStat.Total)/(1024*1024*1024))
 
 				// Get CPU info
 				cpuInfo, err := cpu.Info()
 				if err != nil {
-					log.Fatalf("Error getting CPU info: %v", err)
+					hub.logger().Warnf("Error getting CPU info: %v", err)
+					continue
 				}
 				cpuModel := ""
 				if len(cpuInfo) > 0 {
@@ -158,7 +168,7 @@ func handleConnection(client *Client, password string) {
 
 				response, err = json.Marshal(serverInfo)
 				if err != nil {
-					fmt.Printf("Error marshaling JSON: %v\n", err)
+					hub.logger().Warnf("Error marshaling JSON: %v", err)
 					return
 				}
 				client.Conn.Write(response)
@@ -168,7 +178,7 @@ func handleConnection(client *Client, password string) {
 			}
 		} else {
 			if value, ok := js["type"]; ok {
-				fmt.Println("Key exists, value: ", value)
+				hub.logger().Debugf("Key exists, value: %v", value)
 				switch js["type"] {
 				case "initializationPopulation":
 					//initializationPopulation(js)
@@ -183,7 +193,7 @@ func handleConnection(client *Client, password string) {
 
 					response, err = json.Marshal(serverInfo)
 					if err != nil {
-						fmt.Printf("Error marshaling JSON: %v\n", err)
+						hub.logger().Warnf("Error marshaling JSON: %v", err)
 						return
 					}
 
@@ -210,43 +220,45 @@ func handleConnection(client *Client, password string) {
 					break
 				}
 			} else {
-				fmt.Println("Key does not exist")
+				hub.logger().Debugf("Key does not exist")
 			}
 		}
 
 		/*var responseData InitializationRequest
 		err := json.Unmarshal([]byte(msg.Data), &responseData)
 		if err != nil {
-			log.Println("json unmarshal data:", err)
+			hub.logger().Warnf("json unmarshal data: %v", err)
 			break
 		}*/
 
 	}
 }
 
-func startTcpServer(password string) {
+// startTcpServer serves clients on port 12346, sending its messages to logger, which may be nil to discard them.
+func startTcpServer(password string, logger Logger) {
 	hub = NewHub()
+	hub.Logger = logger
 	go hub.Run()
 
 	tlsConfig, err := generateTLSConfig()
 	if err != nil {
-		fmt.Printf("Error generating TLS config: %v\n", err)
+		hub.logger().Warnf("Error generating TLS config: %v", err)
 		return
 	}
 
 	listener, err := tls.Listen("tcp", ":12346", tlsConfig)
 	if err != nil {
-		fmt.Printf("Error starting server: %v\n", err)
+		hub.logger().Warnf("Error starting server: %v", err)
 		return
 	}
 	defer listener.Close()
 
-	fmt.Println("Server is listening on port 12346...")
+	hub.logger().Infof("Server is listening on port 12346...")
 
 	for {
 		conn, err := listener.Accept()
 		if err != nil {
-			fmt.Printf("Error accepting connection: %v\n", err)
+			hub.logger().Warnf("Error accepting connection: %v", err)
 			continue
 		}
 
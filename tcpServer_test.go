@@ -0,0 +1,24 @@
+package blueprint
+
+import "testing"
+
+func TestHubSendsMessagesToLogger(t *testing.T) {
+	hub := NewHub()
+	logger := &captureLogger{}
+	hub.Logger = logger
+	go hub.Run()
+
+	hub.AddClient(&Client{Addr: "client-a"})
+	// Run handles one request at a time, so the addition has been logged once the removal is received
+	hub.RemoveClient("client-a")
+
+	if logger.count("info: Added client: client-a") != 1 {
+		t.Error("the Logger did not receive the added client")
+	}
+
+	// Without a Logger the hub discards its messages
+	quiet := NewHub()
+	go quiet.Run()
+	quiet.AddClient(&Client{Addr: "client-b"})
+	quiet.RemoveClient("client-b")
+}
@@ -0,0 +1,89 @@
+package blueprint
+
+import (
+	"math"
+	"sort"
+)
+
+// TemporalStep is one timestep of a TemporalSession.
+type TemporalStep struct {
+	InputVariables map[int]float64 // Inputs applied at this timestep (neuron ID to value)
+	ExpectedOutput map[int]float64 // Outputs expected after this timestep (neuron ID to value)
+}
+
+// TemporalSession is a sequence of timesteps whose outputs are checked one step at a time, unlike a Session,
+// which only checks the outputs after its last timestep.
+type TemporalSession struct {
+	Steps []TemporalStep
+}
+
+// RunTemporalSession runs the network for one timestep per step of the session, setting that step's inputs
+// first, and returns the softmaxed outputs after every step. The state carries over from step to step and, like
+// RunNetwork, starts from the current neuron values; the output neurons are softmaxed after the last step.
+func (bp *Blueprint) RunTemporalSession(session TemporalSession) []map[int]float64 {
+	neuronIDs := bp.getAllNeuronIDs()
+	sort.Ints(neuronIDs)
+
+	outputs := make([]map[int]float64, len(session.Steps))
+	for t, step := range session.Steps {
+		bp.setInputValues(step.InputVariables)
+		bp.forwardTimestep(neuronIDs, t)
+		outputs[t] = softmaxMap(bp.GetOutputs())
+	}
+	if len(session.Steps) > 0 {
+		bp.ApplySoftmax()
+	}
+	return outputs
+}
+
+// EvaluateTemporalConsistency runs every session with RunTemporalSession in evaluation mode and returns the mean
+// of their temporal consistency scores, between 0 and 100, see calculateTemporalConsistency.
+func (bp *Blueprint) EvaluateTemporalConsistency(sessions []TemporalSession) float64 {
+	defer bp.evalMode()()
+	if len(sessions) == 0 {
+		return 0.0
+	}
+
+	total := 0.0
+	for _, session := range sessions {
+		predicted := bp.RunTemporalSession(session)
+		expected := make([]map[int]float64, len(session.Steps))
+		for t, step := range session.Steps {
+			expected[t] = step.ExpectedOutput
+		}
+		total += calculateTemporalConsistency(predicted, expected)
+	}
+	return total / float64(len(sessions))
+}
+
+// calculateTemporalConsistency evaluates how closely the step-to-step changes of the predicted outputs follow
+// those of the expected outputs, both indexed by timestep and keyed by neuron ID. Each output expected at two
+// consecutive timesteps scores 1 minus the difference between its predicted and expected change, floored at 0,
+// and the mean score is returned as a percentage. Sequences with no such pair score 0.
+func calculateTemporalConsistency(predicted, expected []map[int]float64) float64 {
+	temporalStability := 0.0
+	numComparisons := 0
+
+	for t := 1; t < len(expected) && t < len(predicted); t++ {
+		for id, currentExpected := range expected[t] {
+			prevExpected, ok := expected[t-1][id]
+			if !ok {
+				continue
+			}
+			// Outputs the network does not produce count as unchanged at 0
+			predictedDelta := math.Abs(predicted[t][id] - predicted[t-1][id])
+			expectedDelta := math.Abs(currentExpected - prevExpected)
+
+			// Penalize large inconsistencies
+			temporalStability += math.Max(0, 1-math.Abs(predictedDelta-expectedDelta))
+			numComparisons++
+		}
+	}
+
+	if numComparisons == 0 {
+		return 0.0
+	}
+
+	// Normalize stability score
+	return temporalStability / float64(numComparisons) * 100.0
+}
@@ -0,0 +1,50 @@
+package blueprint
+
+import (
+	"math"
+	"testing"
+)
+
+func TestTemporalConsistencyOfThreeSteps(t *testing.T) {
+	// Output IDs 1 and 2 are also valid timestep indices, so mixing the two up would change the score
+	expected := []map[int]float64{{1: 0.2, 2: 0.8}, {1: 0.4, 2: 0.6}, {1: 0.5, 2: 0.5}}
+	smooth := []map[int]float64{{1: 0.1, 2: 0.9}, {1: 0.3, 2: 0.7}, {1: 0.4, 2: 0.6}}
+	jumpy := []map[int]float64{{1: 0.2, 2: 0.8}, {1: 0.3, 2: 0.7}, {1: 0.8, 2: 0.2}}
+
+	if got := calculateTemporalConsistency(smooth, expected); math.Abs(got-100) > 1e-9 {
+		t.Errorf("score of predictions changing exactly as expected = %v, want 100", got)
+	}
+	// Changes of 0.1 and 0.5 where 0.2 and 0.1 are expected score 0.9 and 0.6 for each output
+	if got := calculateTemporalConsistency(jumpy, expected); math.Abs(got-75) > 1e-9 {
+		t.Errorf("score of jumpy predictions = %v, want 75", got)
+	}
+	if got := calculateTemporalConsistency(smooth[:1], expected[:1]); got != 0 {
+		t.Errorf("score of a single timestep = %v, want 0", got)
+	}
+}
+
+func TestEvaluateTemporalConsistencyFollowsInputs(t *testing.T) {
+	bp := evalTestBlueprint()
+	steady := func(inputs ...float64) TemporalSession {
+		session := TemporalSession{}
+		for _, x := range inputs {
+			session.Steps = append(session.Steps, TemporalStep{
+				InputVariables: map[int]float64{1: x},
+				ExpectedOutput: map[int]float64{2: 0.5, 3: 0.5},
+			})
+		}
+		return session
+	}
+
+	if got := bp.EvaluateTemporalConsistency([]TemporalSession{steady(0, 0, 0)}); math.Abs(got-100) > 1e-9 {
+		t.Errorf("score of constant outputs with constant expectations = %v, want 100", got)
+	}
+
+	// Output 2 is sigmoid(2x) after the softmax, so inputs 0, 2 and -2 move both outputs by 0.482 and then 0.964
+	// while the expectations stay put
+	first := 1/(1+math.Exp(-4)) - 0.5
+	want := (1 - first + 1 - 2*first) / 2 * 100
+	if got := bp.EvaluateTemporalConsistency([]TemporalSession{steady(0, 2, -2)}); math.Abs(got-want) > 1e-9 {
+		t.Errorf("score of outputs jumping under steady expectations = %v, want %v", got, want)
+	}
+}
@@ -1,51 +1,52 @@
 package blueprint
 
-import (
-	"fmt"
-
-	"golang.org/x/exp/rand"
-)
+import "context"
 
 // blueprint.go
 
 // HillClimbWeightUpdate performs random perturbations on the network's weights.
 // It keeps the changes if the performance improves.
 func (bp *Blueprint) HillClimbWeightUpdate(sessions []Session) bool {
-	// Clone the current blueprint to test changes
-	candidateBP := bp.Clone()
-	if candidateBP == nil {
-		fmt.Println("Failed to clone blueprint for weight update.")
+	// Float32 connections are frozen, so there may be nothing to perturb
+	if bp.LowPrecision {
+		bp.infof("Weight updates require float64 storage. Call ConvertToFloat64Storage first.")
 		return false
 	}
 
+	// Clone the current blueprint to test changes
+	candidateBP := bp.DeepCopy()
+
 	// Define the maximum change per weight
 	const maxWeightChange = 0.1
 
 	// Randomly select a neuron and a connection to perturb
 	neuronIDs := bp.getAllNeuronIDs()
 	if len(neuronIDs) == 0 {
-		fmt.Println("No neurons available for weight update.")
+		bp.infof("No neurons available for weight update.")
 		return false
 	}
 
-	// Select a random neuron (excluding input neurons)
-	var targetNeuron *Neuron
-	for {
-		randomNeuronID := neuronIDs[rand.Intn(len(neuronIDs))]
-		if !bp.isInputNode(randomNeuronID) {
-			targetNeuron = candidateBP.Neurons[randomNeuronID]
-			if targetNeuron != nil && len(targetNeuron.Connections) > 0 {
-				break
-			}
+	// Select a random neuron (excluding input neurons and neurons frozen by the user or by NAS)
+	eligibleIDs := []int{}
+	for _, id := range neuronIDs {
+		neuron := candidateBP.Neurons[id]
+		if !bp.isInputNode(id) && !bp.isFrozen(id) && len(neuron.Connections) > 0 {
+			eligibleIDs = append(eligibleIDs, id)
 		}
 	}
+	if len(eligibleIDs) == 0 {
+		bp.infof("No connections available for weight update.")
+		return false
+	}
+	targetNeuron := candidateBP.Neurons[eligibleIDs[random.Intn(len(eligibleIDs))]]
 
 	// Select a random connection from the target neuron
-	connIndex := rand.Intn(len(targetNeuron.Connections))
+	connIndex := random.Intn(len(targetNeuron.Connections))
 	originalWeight := targetNeuron.Connections[connIndex][1]
 
-	// Perturb the weight by a small random value
-	perturbation := (rand.Float64()*2 - 1) * maxWeightChange // Random change between -maxWeightChange and +maxWeightChange
+	// Perturb the weight by a small random value, scaled by the layer's learning-rate multiplier
+	perturbation := (random.Float64()*2 - 1) * maxWeightChange // Random change between -maxWeightChange and +maxWeightChange
+	perturbation *= learningRateMultiplier(bp.layerLearningRates(), targetNeuron.ID)
 	targetNeuron.Connections[connIndex][1] += perturbation
 
 	// Evaluate the candidate blueprint's performance
@@ -63,17 +64,37 @@ func (bp *Blueprint) HillClimbWeightUpdate(sessions []Session) bool {
 	if improved {
 		// Accept the changes
 		*bp = *candidateBP
-		if bp.Debug {
-			fmt.Printf("Weight Update Accepted: Neuron %d Connection %d Weight changed from %.4f to %.4f\n",
-				targetNeuron.ID, connIndex, originalWeight, targetNeuron.Connections[connIndex][1])
-		}
+		bp.debugf("Weight Update Accepted: Neuron %d Connection %d Weight changed from %.4f to %.4f",
+			targetNeuron.ID, connIndex, originalWeight, targetNeuron.Connections[connIndex][1])
 		return true
 	} else {
 		// Reject the changes
-		if bp.Debug {
-			fmt.Printf("Weight Update Rejected: Neuron %d Connection %d Weight remains at %.4f\n",
-				targetNeuron.ID, connIndex, originalWeight)
-		}
+		bp.debugf("Weight Update Rejected: Neuron %d Connection %d Weight remains at %.4f",
+			targetNeuron.ID, connIndex, originalWeight)
 		return false
 	}
 }
+
+// HillClimb runs up to maxIters HillClimbWeightUpdate steps and stops early once patience consecutive steps
+// fail to improve the model. A patience of 0 or less runs every step. It reports whether any step was kept.
+func (bp *Blueprint) HillClimb(sessions []Session, maxIters, patience int) (improved bool) {
+	return bp.hillClimb(context.Background(), sessions, maxIters, patience)
+}
+
+// hillClimb is HillClimb that also stops before the next step once ctx is cancelled.
+func (bp *Blueprint) hillClimb(ctx context.Context, sessions []Session, maxIters, patience int) (improved bool) {
+	failures := 0
+	for i := 0; i < maxIters && ctx.Err() == nil; i++ {
+		if bp.HillClimbWeightUpdate(sessions) {
+			improved = true
+			failures = 0
+			continue
+		}
+		failures++
+		if patience > 0 && failures >= patience {
+			bp.debugf("Hill climbing stopped after %d of %d steps without improvement for %d steps.", i+1, maxIters, patience)
+			break
+		}
+	}
+	return improved
+}
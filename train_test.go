@@ -0,0 +1,64 @@
+package blueprint
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// captureLogger records every message it receives, prefixed with its level.
+type captureLogger struct {
+	mu       sync.Mutex
+	messages []string
+}
+
+func (l *captureLogger) record(level, format string, args ...any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.messages = append(l.messages, level+": "+fmt.Sprintf(format, args...))
+}
+
+func (l *captureLogger) Debugf(format string, args ...any) { l.record("debug", format, args...) }
+func (l *captureLogger) Infof(format string, args ...any)  { l.record("info", format, args...) }
+func (l *captureLogger) Warnf(format string, args ...any)  { l.record("warn", format, args...) }
+
+// count returns how many recorded messages contain substr.
+func (l *captureLogger) count(substr string) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	n := 0
+	for _, message := range l.messages {
+		if strings.Contains(message, substr) {
+			n++
+		}
+	}
+	return n
+}
+
+func TestHillClimbStopsAfterPatience(t *testing.T) {
+	randomSource.Seed(11)
+	// The softmax is saturated on the right class, so no small perturbation can improve any metric
+	bp := NewBlueprint()
+	bp.AddInputNeurons([]int{1})
+	bp.AddOutputNeurons([]int{2, 3}, "linear")
+	bp.Neurons[2].Connections = [][]float64{{1, 1000}}
+	bp.Neurons[3].Connections = [][]float64{{1, -1000}}
+	sessions := []Session{{InputVariables: map[int]float64{1: 1}, ExpectedOutput: map[int]float64{2: 1, 3: 0}, Timesteps: 1}}
+
+	logger := &captureLogger{}
+	bp.Logger = logger
+	bp.Debug = true
+	if bp.HillClimb(sessions, 1000, 5) {
+		t.Error("HillClimb reported an improvement on a solved network")
+	}
+	if steps := logger.count("Weight Update"); steps != 5 {
+		t.Errorf("HillClimb ran %d steps, want it to stop after the 5 of its patience", steps)
+	}
+
+	logger.messages = nil
+	bp.HillClimb(sessions, 20, 0)
+	if steps := logger.count("Weight Update"); steps != 20 {
+		t.Errorf("HillClimb without patience ran %d steps, want all 20", steps)
+	}
+}
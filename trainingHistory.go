@@ -0,0 +1,132 @@
+package blueprint
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+)
+
+// TrainingHistory records the metrics of a training or search run, one entry per iteration.
+type TrainingHistory struct {
+	Entries []NASMetrics
+}
+
+// Record appends the metrics of one iteration to the history.
+func (h *TrainingHistory) Record(m NASMetrics) {
+	h.Entries = append(h.Entries, m)
+}
+
+// History returns the buffered entries as a TrainingHistory, oldest first.
+func (b *MetricsBuffer) History() TrainingHistory {
+	return TrainingHistory{Entries: b.Recent(0)}
+}
+
+// SaveJSON writes the history to a JSON file.
+func (h TrainingHistory) SaveJSON(path string) error {
+	data, err := json.MarshalIndent(h, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to serialize training history: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write training history: %w", err)
+	}
+	return nil
+}
+
+// LoadTrainingHistory reads a history written by SaveJSON.
+func LoadTrainingHistory(path string) (TrainingHistory, error) {
+	var h TrainingHistory
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return h, fmt.Errorf("failed to read training history: %w", err)
+	}
+	if err := json.Unmarshal(data, &h); err != nil {
+		return h, fmt.Errorf("failed to deserialize training history: %w", err)
+	}
+	return h, nil
+}
+
+// ExportSVG renders the exact, generous and forgiveness accuracy curves as a line chart and writes it to path.
+// The y axis spans 0-100%; generous accuracy is a 0-1 score and is drawn multiplied by 100.
+func (h TrainingHistory) ExportSVG(path string) error {
+	if len(h.Entries) == 0 {
+		return fmt.Errorf("training history is empty")
+	}
+
+	const (
+		width, height = 800.0, 400.0
+		left, right   = 60.0, 160.0 // Room for the y labels and the legend
+		top, bottom   = 20.0, 40.0
+		plotWidth     = width - left - right
+		plotHeight    = height - top - bottom
+	)
+
+	first, last := h.Entries[0].Iteration, h.Entries[len(h.Entries)-1].Iteration
+	span := float64(last - first)
+	if span <= 0 {
+		span = 1
+	}
+	x := func(iteration int) float64 {
+		return left + float64(iteration-first)/span*plotWidth
+	}
+	y := func(percent float64) float64 {
+		if percent < 0 || math.IsNaN(percent) {
+			percent = 0
+		} else if percent > 100 {
+			percent = 100
+		}
+		return top + (1-percent/100)*plotHeight
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, `<svg xmlns="http://www.w3.org/2000/svg" width="%.0f" height="%.0f" viewBox="0 0 %.0f %.0f" font-family="sans-serif" font-size="12">`+"\n",
+		width, height, width, height)
+	fmt.Fprintf(&sb, `<rect width="%.0f" height="%.0f" fill="white"/>`+"\n", width, height)
+
+	// Grid lines and y axis labels every 20%
+	for percent := 0.0; percent <= 100; percent += 20 {
+		fmt.Fprintf(&sb, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="#ddd"/>`+"\n", left, y(percent), left+plotWidth, y(percent))
+		fmt.Fprintf(&sb, `<text x="%.1f" y="%.1f" text-anchor="end">%.0f%%</text>`+"\n", left-6, y(percent)+4, percent)
+	}
+
+	// Axes and x axis labels
+	fmt.Fprintf(&sb, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="black"/>`+"\n", left, top, left, top+plotHeight)
+	fmt.Fprintf(&sb, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="black"/>`+"\n", left, top+plotHeight, left+plotWidth, top+plotHeight)
+	fmt.Fprintf(&sb, `<text x="%.1f" y="%.1f" text-anchor="middle">%d</text>`+"\n", left, top+plotHeight+16, first)
+	fmt.Fprintf(&sb, `<text x="%.1f" y="%.1f" text-anchor="middle">%d</text>`+"\n", left+plotWidth, top+plotHeight+16, last)
+	fmt.Fprintf(&sb, `<text x="%.1f" y="%.1f" text-anchor="middle">Iteration</text>`+"\n", left+plotWidth/2, height-8)
+
+	curves := []struct {
+		label string
+		color string
+		value func(m NASMetrics) float64
+	}{
+		{"Exact", "#1f77b4", func(m NASMetrics) float64 { return m.ExactAccuracy }},
+		{"Generous (x100)", "#2ca02c", func(m NASMetrics) float64 { return m.GenerousAccuracy * 100 }},
+		{"Forgiveness", "#d62728", func(m NASMetrics) float64 { return m.ForgivenessAccuracy }},
+	}
+	for i, curve := range curves {
+		var d strings.Builder
+		for j, m := range h.Entries {
+			command := "L"
+			if j == 0 {
+				command = "M"
+			}
+			fmt.Fprintf(&d, "%s%.1f,%.1f ", command, x(m.Iteration), y(curve.value(m)))
+		}
+		fmt.Fprintf(&sb, `<path d="%s" fill="none" stroke="%s" stroke-width="2"/>`+"\n", strings.TrimSpace(d.String()), curve.color)
+
+		legendY := top + 10 + float64(i)*20
+		fmt.Fprintf(&sb, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="%s" stroke-width="2"/>`+"\n",
+			left+plotWidth+15, legendY, left+plotWidth+35, legendY, curve.color)
+		fmt.Fprintf(&sb, `<text x="%.1f" y="%.1f">%s</text>`+"\n", left+plotWidth+40, legendY+4, curve.label)
+	}
+	sb.WriteString("</svg>\n")
+
+	if err := os.WriteFile(path, []byte(sb.String()), 0644); err != nil {
+		return fmt.Errorf("failed to write SVG: %w", err)
+	}
+	return nil
+}
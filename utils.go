@@ -5,16 +5,18 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"log"
 	"math"
-	"math/rand"
 	"net/http"
 	"os"
 	"path/filepath"
+	"sort"
 )
 
-// Softmax activation function (applied across a slice)
+// Softmax activation function (applied across a slice). An empty slice yields an empty result.
 func Softmax(inputs []float64) []float64 {
+	if len(inputs) == 0 {
+		return []float64{}
+	}
 	max := inputs[0]
 	for _, v := range inputs {
 		if v > max {
@@ -35,6 +37,10 @@ func Softmax(inputs []float64) []float64 {
 
 // LoadNeurons loads neurons from a JSON string
 func (bp *Blueprint) LoadNeurons(jsonData string) error {
+	bp.invalidateCompiled()
+	if bp.ScalarActivationMap == nil {
+		bp.InitializeActivationFunctions()
+	}
 
 	var rawNeurons []json.RawMessage
 	if err := json.Unmarshal([]byte(jsonData), &rawNeurons); err != nil {
@@ -77,16 +83,12 @@ func (bp *Blueprint) LoadNeurons(jsonData string) error {
 					{0.2, 0.5}, // Default Kernel 0
 					{0.3, 0.4}, // Default Kernel 1
 				}
-				if bp.Debug {
-					fmt.Printf("CNN Neuron %d: No kernels provided. Initialized with default kernels.\n", cnnNeuron.ID)
-				}
+				bp.debugf("CNN Neuron %d: No kernels provided. Initialized with default kernels.", cnnNeuron.ID)
 			}
 			// Ensure activation is set to "relu"; if not, default to "relu"
 			if cnnNeuron.Activation == "" {
 				cnnNeuron.Activation = "relu"
-				if bp.Debug {
-					fmt.Printf("CNN Neuron %d: Activation not provided. Set to 'relu'.\n", cnnNeuron.ID)
-				}
+				bp.debugf("CNN Neuron %d: Activation not provided. Set to 'relu'.", cnnNeuron.ID)
 			}
 			bp.Neurons[cnnNeuron.ID] = &cnnNeuron
 
@@ -95,19 +97,20 @@ func (bp *Blueprint) LoadNeurons(jsonData string) error {
 			if err := json.Unmarshal(rawNeuron, &bnNeuron); err != nil {
 				return err
 			}
-			// Initialize BatchNormParams
-			bnNeuron.BatchNormParams = &BatchNormParams{
-				Gamma: 1.0,
-				Beta:  0.0,
-				Mean:  0.0,
-				Var:   1.0,
+			// Initialize BatchNormParams unless the running statistics were saved with the neuron
+			if bnNeuron.BatchNormParams == nil {
+				bnNeuron.BatchNormParams = &BatchNormParams{
+					Gamma:    1.0,
+					Beta:     0.0,
+					Mean:     0.0,
+					Var:      1.0,
+					Momentum: defaultBatchNormMomentum,
+				}
 			}
 			// Ensure activation is set; default to "linear" if not provided
 			if bnNeuron.Activation == "" {
 				bnNeuron.Activation = "linear"
-				if bp.Debug {
-					fmt.Printf("BatchNorm Neuron %d: Activation not provided. Set to 'linear'.\n", bnNeuron.ID)
-				}
+				bp.debugf("BatchNorm Neuron %d: Activation not provided. Set to 'linear'.", bnNeuron.ID)
 			}
 			bp.Neurons[bnNeuron.ID] = &bnNeuron
 
@@ -116,8 +119,8 @@ func (bp *Blueprint) LoadNeurons(jsonData string) error {
 			if err := json.Unmarshal(rawNeuron, &neuron); err != nil {
 				return err
 			}
-			// Initialize gate weights for LSTM neurons
-			if neuron.Type == "lstm" {
+			// Initialize gate weights for LSTM neurons that were saved without them
+			if neuron.Type == "lstm" && len(neuron.GateWeights) == 0 {
 				neuron.GateWeights = map[string][]float64{
 					"input":  bp.RandomWeights(len(neuron.Connections)),
 					"forget": bp.RandomWeights(len(neuron.Connections)),
@@ -128,9 +131,7 @@ func (bp *Blueprint) LoadNeurons(jsonData string) error {
 			// Ensure activation is set; default to "linear" if not provided
 			if neuron.Activation == "" {
 				neuron.Activation = "linear"
-				if bp.Debug {
-					fmt.Printf("Neuron %d: Activation not provided. Set to 'linear'.\n", neuron.ID)
-				}
+				bp.debugf("Neuron %d: Activation not provided. Set to 'linear'.", neuron.ID)
 			}
 			bp.Neurons[neuron.ID] = &neuron
 		}
@@ -153,7 +154,7 @@ func (bp *Blueprint) SaveToJSON(fileName string) error {
 		return fmt.Errorf("failed to write JSON to file '%s': %v", fileName, err)
 	}
 
-	fmt.Printf("Blueprint saved successfully to '%s'\n", fileName)
+	bp.infof("Blueprint saved successfully to '%s'", fileName)
 	return nil
 }
 
@@ -212,7 +213,7 @@ func (bp *Blueprint) UnzipFile(gzFile string, targetDir string) error {
 		return fmt.Errorf("error during unzipping %s: %v", gzFile, err)
 	}
 
-	log.Printf("Unzipped %s successfully to %s\n", gzFile, outFile)
+	bp.infof("Unzipped %s successfully to %s", gzFile, outFile)
 	return nil
 }
 
@@ -226,16 +227,44 @@ func (bp *Blueprint) SerializeToJSON() (string, error) {
 }
 
 // FromJSON deserializes the Blueprint from a JSON string.
+// The activation functions, which are not serialized, are restored as in Clone.
+// Low-precision models have any float64 connections converted to float32 storage on load.
 func (bp *Blueprint) DeserializesFromJSON(data string) error {
-	return json.Unmarshal([]byte(data), bp)
+	bp.invalidateCompiled()
+	if err := json.Unmarshal([]byte(data), bp); err != nil {
+		return err
+	}
+	if bp.Neurons == nil {
+		bp.Neurons = make(map[int]*Neuron)
+	}
+	if bp.ScalarActivationMap == nil {
+		bp.InitializeActivationFunctions()
+	}
+	bp.initializeMissingLSTMWeights()
+	if bp.LowPrecision {
+		return bp.ConvertToFloat32Storage()
+	}
+	return nil
+}
+
+// initializeMissingLSTMWeights gives LSTM neurons that were stored without gate weights a fresh set,
+// so models written by hand or by older tools still run.
+func (bp *Blueprint) initializeMissingLSTMWeights() {
+	for _, neuron := range bp.Neurons {
+		if neuron.Type == "lstm" && len(neuron.GateWeights) == 0 && neuron.numConnections() > 0 {
+			bp.initializeLSTMWeights(neuron)
+		}
+	}
 }
 
-// getAllNeuronIDs retrieves the IDs of all neurons in the blueprint.
+// getAllNeuronIDs retrieves the IDs of all neurons in the blueprint in ascending order,
+// so random choices among them are reproducible under SetRandomSeed.
 func (bp *Blueprint) getAllNeuronIDs() []int {
 	neuronIDs := []int{}
 	for id := range bp.Neurons {
 		neuronIDs = append(neuronIDs, id)
 	}
+	sort.Ints(neuronIDs)
 	return neuronIDs
 }
 
@@ -248,7 +277,7 @@ func (bp *Blueprint) getRandomConnectionPair() (int, int) {
 	}
 
 	// Shuffle neuron IDs to randomize selection
-	rand.Shuffle(len(neuronIDs), func(i, j int) { neuronIDs[i], neuronIDs[j] = neuronIDs[j], neuronIDs[i] })
+	random.Shuffle(len(neuronIDs), func(i, j int) { neuronIDs[i], neuronIDs[j] = neuronIDs[j], neuronIDs[i] })
 
 	for _, source := range neuronIDs {
 		for _, target := range neuronIDs {
@@ -271,26 +300,12 @@ func getMaxFloat() float64 {
 	return math.MaxFloat64
 }
 
+// ValidateConnections reports whether every output node can be reached from an input node by following
+// connections, warning about the first output that cannot. See Validate for a full structural check.
 func (bp *Blueprint) ValidateConnections() bool {
-	visited := map[int]bool{}
-	var dfs func(int)
-	dfs = func(id int) {
-		if visited[id] {
-			return
-		}
-		visited[id] = true
-		for _, conn := range bp.Neurons[id].Connections {
-			dfs(int(conn[0]))
-		}
-	}
-	for _, inputID := range bp.InputNodes {
-		dfs(inputID)
-	}
-	for _, outputID := range bp.OutputNodes {
-		if !visited[outputID] {
-			fmt.Printf("Output Neuron %d is not connected.\n", outputID)
-			return false
-		}
+	if unreachable := bp.unreachableOutputs(); len(unreachable) > 0 {
+		bp.warnf("Output Neuron %d is not connected.", unreachable[0])
+		return false
 	}
 	return true
 }
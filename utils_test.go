@@ -0,0 +1,28 @@
+package blueprint
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDeserializedModelAppliesSigmoid(t *testing.T) {
+	source := NewBlueprint()
+	source.AddInputNeurons([]int{1})
+	source.Neurons[2] = &Neuron{ID: 2, Type: "dense", Activation: "sigmoid", Connections: [][]float64{{1, 1}}}
+	source.AddOutputNeurons([]int{3}, "linear")
+	source.Neurons[3].Connections = [][]float64{{2, 1}}
+	data, err := source.SerializeToJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var bp Blueprint // No activation map, as for any model read from disk
+	if err := bp.DeserializesFromJSON(data); err != nil {
+		t.Fatal(err)
+	}
+	bp.RunNetwork(map[int]float64{1: 2}, 1)
+
+	if got, want := bp.Neurons[2].Value, 1/(1+math.Exp(-2)); math.Abs(got-want) > 1e-12 {
+		t.Errorf("sigmoid neuron has value %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,264 @@
+package blueprint
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Validate checks the blueprint for structural problems and returns every issue found: input and output nodes
+// without a neuron, connections and NCA neighbourhoods referring to missing neurons, LSTM gate weights that do not
+// match the connections, CNN neurons without kernels or with misshapen ones, pooling neurons with negative window
+// sizes, output nodes no input reaches and inconsistent entanglements. An empty result means no problems were
+// detected.
+func (bp *Blueprint) Validate() []error {
+	var errs []error
+	errs = append(errs, bp.validateNodeLists()...)
+	errs = append(errs, bp.validateNeurons()...)
+	for _, id := range bp.unreachableOutputs() {
+		if _, exists := bp.Neurons[id]; exists { // Missing output neurons are already reported
+			errs = append(errs, fmt.Errorf("output neuron %d is not reachable from any input", id))
+		}
+	}
+	errs = append(errs, bp.ValidateEntanglements()...)
+	return errs
+}
+
+// validateNodeLists checks that every input and output node has a neuron.
+func (bp *Blueprint) validateNodeLists() []error {
+	var errs []error
+	for _, id := range bp.InputNodes {
+		if _, exists := bp.Neurons[id]; !exists {
+			errs = append(errs, fmt.Errorf("input node %d has no neuron", id))
+		}
+	}
+	for _, id := range bp.OutputNodes {
+		if _, exists := bp.Neurons[id]; !exists {
+			errs = append(errs, fmt.Errorf("output node %d has no neuron", id))
+		}
+	}
+	return errs
+}
+
+// hasNeuron reports whether id is a classical or quantum neuron of the blueprint.
+func (bp *Blueprint) hasNeuron(id int) bool {
+	if _, exists := bp.Neurons[id]; exists {
+		return true
+	}
+	_, exists := bp.QuantumNeurons[id]
+	return exists
+}
+
+// validateNeurons checks every classical neuron in ascending ID order.
+func (bp *Blueprint) validateNeurons() []error {
+	var errs []error
+	for _, id := range bp.getAllNeuronIDs() {
+		neuron := bp.Neurons[id]
+		if neuron == nil {
+			errs = append(errs, fmt.Errorf("neuron %d is nil", id))
+			continue
+		}
+		if neuron.ID != id {
+			errs = append(errs, fmt.Errorf("neuron stored under ID %d has ID %d", id, neuron.ID))
+		}
+		for i := 0; i < neuron.numConnections(); i++ {
+			if sourceID, _ := neuron.connection(i); !bp.hasNeuron(sourceID) {
+				errs = append(errs, fmt.Errorf("neuron %d has a connection from missing neuron %d", id, sourceID))
+			}
+		}
+		for _, neighbourID := range neuron.NeighborhoodIDs {
+			if !bp.hasNeuron(neighbourID) {
+				errs = append(errs, fmt.Errorf("NCA neuron %d has missing neuron %d in its neighbourhood", id, neighbourID))
+			}
+		}
+
+		switch neuron.Type {
+		case "lstm":
+			// Missing gate weights are initialized on load, so only a partial or stale set is a problem
+			if len(neuron.GateWeights) == 0 {
+				break
+			}
+			for _, gate := range []string{"input", "forget", "output", "cell"} {
+				if n := len(neuron.GateWeights[gate]); n != neuron.numConnections() {
+					errs = append(errs, fmt.Errorf("LSTM neuron %d has %d %s gate weights for %d connections",
+						id, n, gate, neuron.numConnections()))
+				}
+			}
+		case "cnn":
+			if len(neuron.Kernels) == 0 {
+				errs = append(errs, fmt.Errorf("CNN neuron %d has no kernels", id))
+			}
+			is2D := neuron.InputShape[0] > 0 && neuron.InputShape[1] > 0
+			for k, kernel := range neuron.Kernels {
+				if _, ok := neuron.kernelShape(kernel, is2D); !ok {
+					errs = append(errs, fmt.Errorf("CNN neuron %d kernel %d has %d weights, which do not fit its shape", id, k, len(kernel)))
+				}
+			}
+			if neuron.Padding != "" && neuron.Padding != "valid" && neuron.Padding != "same" {
+				errs = append(errs, fmt.Errorf("CNN neuron %d has unknown padding %q", id, neuron.Padding))
+			}
+		case "maxpool", "avgpool":
+			if neuron.PoolSize < 0 || neuron.Stride < 0 {
+				errs = append(errs, fmt.Errorf("pooling neuron %d has pool size %d and stride %d, which must not be negative",
+					id, neuron.PoolSize, neuron.Stride))
+			}
+		}
+	}
+	return errs
+}
+
+// unreachableOutputs returns the output nodes, in the order of OutputNodes, that no path of connections leads to
+// from an input node. Quantum neurons count as intermediate neurons. The search is iterative, so cycles and deep
+// networks are safe.
+func (bp *Blueprint) unreachableOutputs() []int {
+	// Connections are stored on their target, so index them by source
+	targets := make(map[int][]int)
+	for id, neuron := range bp.Neurons {
+		if neuron == nil {
+			continue
+		}
+		for i := 0; i < neuron.numConnections(); i++ {
+			sourceID, _ := neuron.connection(i)
+			targets[sourceID] = append(targets[sourceID], id)
+		}
+	}
+	for id, neuron := range bp.QuantumNeurons {
+		for _, conn := range neuron.Connections {
+			if len(conn) > 0 {
+				sourceID := int(real(conn[0]))
+				targets[sourceID] = append(targets[sourceID], id)
+			}
+		}
+	}
+
+	reached := make(map[int]bool)
+	queue := []int{}
+	for _, id := range bp.InputNodes {
+		if !reached[id] {
+			reached[id] = true
+			queue = append(queue, id)
+		}
+	}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		for _, target := range targets[id] {
+			if !reached[target] {
+				reached[target] = true
+				queue = append(queue, target)
+			}
+		}
+	}
+
+	var unreachable []int
+	for _, id := range bp.OutputNodes {
+		if !reached[id] {
+			unreachable = append(unreachable, id)
+		}
+	}
+	return unreachable
+}
+
+// sortedQuantumNeuronIDs returns the IDs of all quantum neurons in ascending order.
+func (bp *Blueprint) sortedQuantumNeuronIDs() []int {
+	ids := make([]int, 0, len(bp.QuantumNeurons))
+	for id := range bp.QuantumNeurons {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+// findEntanglement returns the index of the neuron's entanglement with partnerID, or -1 if there is none.
+func findEntanglement(neuron *QuantumNeuron, partnerID int) int {
+	for i, entanglement := range neuron.Entanglements {
+		if entanglement.PartnerID == partnerID {
+			return i
+		}
+	}
+	return -1
+}
+
+// ValidateEntanglements checks that every entanglement refers to an existing quantum neuron other than
+// itself, that the partner records the entanglement back, and that both sides agree on its type and strength.
+func (bp *Blueprint) ValidateEntanglements() []error {
+	var errs []error
+	for _, id := range bp.sortedQuantumNeuronIDs() {
+		neuron := bp.QuantumNeurons[id]
+		seen := make(map[int]bool)
+		for _, entanglement := range neuron.Entanglements {
+			partnerID := entanglement.PartnerID
+			if partnerID == id {
+				errs = append(errs, fmt.Errorf("quantum neuron %d is entangled with itself", id))
+				continue
+			}
+			if seen[partnerID] {
+				errs = append(errs, fmt.Errorf("quantum neuron %d lists its entanglement with %d more than once", id, partnerID))
+				continue
+			}
+			seen[partnerID] = true
+
+			partner, exists := bp.QuantumNeurons[partnerID]
+			if !exists {
+				errs = append(errs, fmt.Errorf("quantum neuron %d is entangled with missing neuron %d", id, partnerID))
+				continue
+			}
+			idx := findEntanglement(partner, id)
+			if idx < 0 {
+				errs = append(errs, fmt.Errorf("quantum neuron %d is entangled with %d, but %d does not reference %d", id, partnerID, partnerID, id))
+				continue
+			}
+
+			// Report disagreements once per pair
+			reverse := partner.Entanglements[idx]
+			if id < partnerID && (reverse.Type != entanglement.Type || reverse.Strength != entanglement.Strength) {
+				errs = append(errs, fmt.Errorf("quantum neurons %d and %d disagree on their entanglement: %s/%g vs %s/%g",
+					id, partnerID, entanglement.Type, entanglement.Strength, reverse.Type, reverse.Strength))
+			}
+		}
+	}
+	return errs
+}
+
+// RepairEntanglements makes the entanglement graph consistent and returns the number of changes made.
+// Self-entanglements, duplicates and entanglements with missing neurons are removed, missing reverse
+// entanglements are added, and when the two sides of a pair disagree the lower neuron ID's entry wins.
+func (bp *Blueprint) RepairEntanglements() int {
+	changes := 0
+
+	// Drop invalid and duplicate entries first so the pairs below are well defined
+	for _, id := range bp.sortedQuantumNeuronIDs() {
+		neuron := bp.QuantumNeurons[id]
+		kept := neuron.Entanglements[:0]
+		seen := make(map[int]bool)
+		for _, entanglement := range neuron.Entanglements {
+			_, exists := bp.QuantumNeurons[entanglement.PartnerID]
+			if entanglement.PartnerID == id || seen[entanglement.PartnerID] || !exists {
+				changes++
+				continue
+			}
+			seen[entanglement.PartnerID] = true
+			kept = append(kept, entanglement)
+		}
+		neuron.Entanglements = kept
+	}
+
+	// Mirror every entanglement onto its partner, letting the lower ID win on disagreement
+	for _, id := range bp.sortedQuantumNeuronIDs() {
+		neuron := bp.QuantumNeurons[id]
+		for _, entanglement := range neuron.Entanglements {
+			partner := bp.QuantumNeurons[entanglement.PartnerID]
+			mirrored := EntanglementInfo{PartnerID: id, Type: entanglement.Type, Strength: entanglement.Strength}
+			idx := findEntanglement(partner, id)
+			switch {
+			case idx < 0:
+				partner.Entanglements = append(partner.Entanglements, mirrored)
+				changes++
+			case id < entanglement.PartnerID && partner.Entanglements[idx] != mirrored:
+				partner.Entanglements[idx] = mirrored
+				changes++
+			}
+		}
+	}
+
+	return changes
+}
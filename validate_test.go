@@ -0,0 +1,55 @@
+package blueprint
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateReportsEveryProblem(t *testing.T) {
+	bp := NewBlueprint()
+	bp.AddInputNeurons([]int{1})
+	bp.AddOutputNeurons([]int{2, 3}, "linear")
+	bp.InputNodes = append(bp.InputNodes, 9)   // No neuron
+	bp.OutputNodes = append(bp.OutputNodes, 8) // No neuron
+	bp.Neurons[2].Connections = [][]float64{{1, 1}, {7, 0.5}}
+	// Output 3 only reads from neuron 4, which no input leads to
+	bp.Neurons[3].Connections = [][]float64{{4, 1}}
+	bp.Neurons[4] = &Neuron{
+		ID: 4, Type: "lstm", Activation: "tanh",
+		Connections: [][]float64{{3, 0.1}, {4, 0.2}},
+		GateWeights: map[string][]float64{
+			"input": {1, 2}, "forget": {3, 4}, "output": {5}, "cell": {6, 7},
+		},
+	}
+	bp.Neurons[5] = &Neuron{ID: 5, Type: "cnn", Activation: "relu", Connections: [][]float64{{1, 1}}}
+
+	var messages []string
+	for _, err := range bp.Validate() {
+		messages = append(messages, err.Error())
+	}
+	for _, want := range []string{
+		"input node 9 has no neuron",
+		"output node 8 has no neuron",
+		"neuron 2 has a connection from missing neuron 7",
+		"LSTM neuron 4 has 1 output gate weights for 2 connections",
+		"CNN neuron 5 has no kernels",
+		"output neuron 3 is not reachable from any input",
+	} {
+		found := false
+		for _, message := range messages {
+			found = found || strings.Contains(message, want)
+		}
+		if !found {
+			t.Errorf("Validate did not report %q; got %q", want, messages)
+		}
+	}
+	if len(messages) != 6 {
+		t.Errorf("Validate reported %d problems, want 6: %q", len(messages), messages)
+	}
+}
+
+func TestValidateAcceptsDenseNetwork(t *testing.T) {
+	if errs := NewDenseMLP([]int{3, 4, 2}, "relu").Validate(); len(errs) > 0 {
+		t.Errorf("Validate reported problems with a dense network: %v", errs)
+	}
+}
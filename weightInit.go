@@ -0,0 +1,79 @@
+package blueprint
+
+import (
+	"fmt"
+	"math"
+)
+
+// weightInitializer returns a generator of connection weights for a neuron with fanIn incoming connections:
+// "uniform" draws from [-sqrt(3/fanIn), sqrt(3/fanIn)], which has variance 1/fanIn, "xavier" from a normal
+// distribution with variance 1/fanIn and "he" from one with variance 2/fanIn, which suits ReLU neurons.
+func weightInitializer(strategy string, fanIn int) (func() float64, error) {
+	fanIn = max(fanIn, 1)
+	switch strategy {
+	case "uniform":
+		limit := math.Sqrt(3 / float64(fanIn))
+		return func() float64 { return (random.Float64()*2 - 1) * limit }, nil
+	case "xavier":
+		stdDev := math.Sqrt(1 / float64(fanIn))
+		return func() float64 { return random.NormFloat64() * stdDev }, nil
+	case "he":
+		stdDev := math.Sqrt(2 / float64(fanIn))
+		return func() float64 { return random.NormFloat64() * stdDev }, nil
+	}
+	return nil, fmt.Errorf("unknown weight initialization strategy %q, expected uniform, xavier or he", strategy)
+}
+
+// InitializeWeights redraws the incoming connection weights of every neuron except input and frozen ones with the
+// given strategy, "uniform", "xavier" or "he", scaled by the neuron's number of connections; see
+// weightInitializer. LSTM gate weights are redrawn the same way. Biases are left unchanged. An unknown strategy
+// returns an error without changing any weight.
+func (bp *Blueprint) InitializeWeights(strategy string) error {
+	if _, err := weightInitializer(strategy, 1); err != nil {
+		return err
+	}
+	for _, id := range bp.getAllNeuronIDs() {
+		neuron := bp.Neurons[id]
+		if neuron.Type == "input" || bp.isFrozen(id) {
+			continue
+		}
+		_ = bp.initializeNeuronWeights(neuron, strategy)
+	}
+	bp.invalidateCompiled()
+	bp.debugf("Initialized weights with the %s strategy.", strategy)
+	return nil
+}
+
+// initializeNeuronWeights redraws the incoming connection weights of one neuron, and its LSTM gate weights when
+// they match its connections, with the given strategy.
+func (bp *Blueprint) initializeNeuronWeights(neuron *Neuron, strategy string) error {
+	numConnections := neuron.numConnections()
+	draw, err := weightInitializer(strategy, numConnections)
+	if err != nil {
+		return err
+	}
+	for i := 0; i < numConnections; i++ {
+		neuron.setConnectionWeight(i, draw())
+	}
+	for _, gate := range sortedGateNames(neuron.GateWeights) {
+		weights := neuron.GateWeights[gate]
+		if len(weights) != numConnections {
+			continue
+		}
+		for i := range weights {
+			weights[i] = draw()
+		}
+	}
+	return nil
+}
+
+// initializeInsertedWeights applies the WeightInit strategy, if one is set, to the incoming weights of a neuron
+// inserted by a mutation. An unknown strategy is reported and the mutation's own weights are kept.
+func (bp *Blueprint) initializeInsertedWeights(neuron *Neuron) {
+	if bp.WeightInit == "" {
+		return
+	}
+	if err := bp.initializeNeuronWeights(neuron, bp.WeightInit); err != nil {
+		bp.warnf("Warning: Neuron %d keeps its random weights: %v", neuron.ID, err)
+	}
+}
@@ -0,0 +1,51 @@
+package blueprint
+
+import (
+	"math"
+	"testing"
+)
+
+func TestInitializeWeightsVariance(t *testing.T) {
+	randomSource.Seed(3)
+	const fanIn, neurons = 200, 50
+
+	bp := NewBlueprint()
+	inputIDs := make([]int, fanIn)
+	for i := range inputIDs {
+		inputIDs[i] = i + 1
+	}
+	bp.AddInputNeurons(inputIDs)
+	for id := fanIn + 1; id <= fanIn+neurons; id++ {
+		neuron := &Neuron{ID: id, Type: "dense", Activation: "relu"}
+		for _, source := range inputIDs {
+			neuron.Connections = append(neuron.Connections, []float64{float64(source), 0})
+		}
+		bp.Neurons[id] = neuron
+	}
+
+	for strategy, want := range map[string]float64{"he": 2.0 / fanIn, "xavier": 1.0 / fanIn, "uniform": 1.0 / fanIn} {
+		if err := bp.InitializeWeights(strategy); err != nil {
+			t.Fatal(err)
+		}
+		sum, sumSquares, n := 0.0, 0.0, 0.0
+		for id := fanIn + 1; id <= fanIn+neurons; id++ {
+			for _, conn := range bp.Neurons[id].Connections {
+				sum += conn[1]
+				sumSquares += conn[1] * conn[1]
+				n++
+			}
+		}
+		mean := sum / n
+		variance := sumSquares/n - mean*mean
+		if math.Abs(variance-want) > 0.05*want {
+			t.Errorf("%s weights have variance %.6f, want about %.6f", strategy, variance, want)
+		}
+		if math.Abs(mean) > 0.01 {
+			t.Errorf("%s weights have mean %.4f, want about 0", strategy, mean)
+		}
+	}
+
+	if err := bp.InitializeWeights("orthogonal"); err == nil {
+		t.Error("expected an error for an unknown strategy")
+	}
+}
@@ -0,0 +1,91 @@
+package blueprint
+
+import (
+	"math"
+	"sort"
+)
+
+// ConnectionRef identifies one connection and the weight it had when it was reported.
+type ConnectionRef struct {
+	SourceID int
+	TargetID int
+	Weight   float64
+}
+
+// weightStats returns the mean and population standard deviation of every connection weight.
+func (bp *Blueprint) weightStats() (mean, std float64, count int) {
+	sum, sumSquares := 0.0, 0.0
+	for _, neuron := range bp.Neurons {
+		for i := 0; i < neuron.numConnections(); i++ {
+			_, weight := neuron.connection(i)
+			sum += weight
+			sumSquares += weight * weight
+			count++
+		}
+	}
+	if count == 0 {
+		return 0, 0, 0
+	}
+	mean = sum / float64(count)
+	variance := sumSquares/float64(count) - mean*mean
+	if variance < 0 {
+		variance = 0
+	}
+	return mean, math.Sqrt(variance), count
+}
+
+// WeightOutliers returns the connections whose weight lies more than zThreshold standard deviations
+// from the mean of all connection weights, ordered by target and then source neuron ID.
+// Nothing is reported when every weight is equal.
+func (bp *Blueprint) WeightOutliers(zThreshold float64) []ConnectionRef {
+	mean, std, _ := bp.weightStats()
+	if std == 0 {
+		return nil
+	}
+
+	var outliers []ConnectionRef
+	for _, targetID := range bp.getAllNeuronIDs() {
+		neuron := bp.Neurons[targetID]
+		for i := 0; i < neuron.numConnections(); i++ {
+			sourceID, weight := neuron.connection(i)
+			if math.Abs(weight-mean) > zThreshold*std {
+				outliers = append(outliers, ConnectionRef{SourceID: sourceID, TargetID: targetID, Weight: weight})
+			}
+		}
+	}
+	sort.SliceStable(outliers, func(a, b int) bool {
+		if outliers[a].TargetID != outliers[b].TargetID {
+			return outliers[a].TargetID < outliers[b].TargetID
+		}
+		return outliers[a].SourceID < outliers[b].SourceID
+	})
+	return outliers
+}
+
+// ClipWeightOutliers clamps every weight found by WeightOutliers to mean ± zThreshold standard deviations
+// and returns the number of connections changed. The statistics are computed once before clipping.
+func (bp *Blueprint) ClipWeightOutliers(zThreshold float64) int {
+	mean, std, _ := bp.weightStats()
+	if std == 0 {
+		return 0
+	}
+	low, high := mean-zThreshold*std, mean+zThreshold*std
+
+	clipped := 0
+	for _, neuron := range bp.Neurons {
+		for i := 0; i < neuron.numConnections(); i++ {
+			_, weight := neuron.connection(i)
+			if weight < low {
+				neuron.setConnectionWeight(i, low)
+				clipped++
+			} else if weight > high {
+				neuron.setConnectionWeight(i, high)
+				clipped++
+			}
+		}
+	}
+	if clipped > 0 {
+		bp.invalidateCompiled()
+	}
+	return clipped
+}